@@ -6,7 +6,11 @@
 // views/commit.tmpl
 // views/error.tmpl
 // views/index.tmpl
+// views/pull.tmpl
+// views/pulls.tmpl
+// views/refs.tmpl
 // views/repo.tmpl
+// views/status.tmpl
 // views/user.tmpl
 // DO NOT EDIT!
 
@@ -125,7 +129,7 @@ func assetsStyleCss() (*asset, error) {
 	return a, nil
 }
 
-var _viewsCommitTmpl = "\x3c\x21\x64\x6f\x63\x74\x79\x70\x65\x20\x68\x74\x6d\x6c\x3e\x0a\x3c\x68\x74\x6d\x6c\x20\x6c\x61\x6e\x67\x3d\x65\x6e\x3e\x0a\x3c\x68\x65\x61\x64\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x63\x68\x61\x72\x73\x65\x74\x3d\x75\x74\x66\x2d\x38\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x6e\x61\x6d\x65\x3d\x76\x69\x65\x77\x70\x6f\x72\x74\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x22\x77\x69\x64\x74\x68\x3d\x64\x65\x76\x69\x63\x65\x2d\x77\x69\x64\x74\x68\x2c\x69\x6e\x69\x74\x69\x61\x6c\x2d\x73\x63\x61\x6c\x65\x3d\x31\x22\x3e\x0a\x09\x3c\x74\x69\x74\x6c\x65\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x40\x7b\x7b\x74\x72\x75\x6e\x63\x61\x74\x65\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x53\x48\x41\x20\x31\x30\x7d\x7d\x20\xc2\xb7\x20\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x74\x69\x74\x6c\x65\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x73\x74\x79\x6c\x65\x2e\x63\x73\x73\x22\x7d\x7d\x22\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x2e\x48\x69\x67\x68\x6c\x69\x67\x68\x74\x53\x74\x79\x6c\x65\x7d\x7d\x22\x3e\x0a\x3c\x2f\x68\x65\x61\x64\x3e\x0a\x3c\x62\x6f\x64\x79\x3e\x0a\x09\x3c\x68\x65\x61\x64\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x3c\x68\x31\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x2f\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x61\x3e\x3c\x2f\x68\x31\x3e\x0a\x09\x09\x3c\x68\x32\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x3c\x2f\x61\x3e\x2f\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x3c\x2f\x61\x3e\x40\x3c\x63\x6f\x64\x65\x3e\x7b\x7b\x74\x72\x75\x6e\x63\x61\x74\x65\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x53\x48\x41\x20\x31\x30\x7d\x7d\x3c\x2f\x63\x6f\x64\x65\x3e\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x53\x48\x41\x7d\x7d\x2f\x62\x2f\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x42\x75\x69\x6c\x64\x20\x4a\x65\x6b\x79\x6c\x6c\x20\x61\x74\x20\x74\x68\x69\x73\x20\x63\x6f\x6d\x6d\x69\x74\x22\x3e\xe2\x87\x9d\x3c\x2f\x61\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6e\x65\x20\x28\x6c\x65\x6e\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x50\x61\x72\x65\x6e\x74\x73\x29\x20\x30\x29\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x28\x69\x6e\x64\x65\x78\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x50\x61\x72\x65\x6e\x74\x73\x20\x30\x29\x2e\x53\x48\x41\x7d\x7d\x2f\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x50\x61\x72\x65\x6e\x74\x20\x63\x6f\x6d\x6d\x69\x74\x22\x3e\xe2\x86\x91\x3c\x2f\x61\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x48\x54\x4d\x4c\x55\x52\x4c\x7d\x7d\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x56\x69\x65\x77\x20\x6f\x6e\x20\x47\x69\x74\x48\x75\x62\x22\x3e\xe2\xa4\xb4\x3c\x2f\x61\x3e\x3c\x2f\x68\x32\x3e\x0a\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x3c\x6d\x61\x69\x6e\x3e\x0a\x09\x09\x3c\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x09\x3c\x70\x20\x63\x6c\x61\x73\x73\x3d\x63\x6f\x6d\x6d\x69\x74\x2d\x6d\x65\x73\x73\x61\x67\x65\x3e\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x4d\x65\x73\x73\x61\x67\x65\x7d\x7d\x3c\x2f\x70\x3e\x0a\x09\x09\x09\x3c\x70\x20\x63\x6c\x61\x73\x73\x3d\x63\x6f\x6d\x6d\x69\x74\x2d\x61\x75\x74\x68\x6f\x72\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x41\x75\x74\x68\x6f\x72\x2e\x48\x54\x4d\x4c\x55\x52\x4c\x7d\x7d\x22\x3e\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x41\x75\x74\x68\x6f\x72\x2e\x4e\x61\x6d\x65\x7d\x7d\x3c\x2f\x61\x3e\x3c\x2f\x70\x3e\x0a\x09\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x09\x7b\x7b\x2d\x20\x72\x61\x6e\x67\x65\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x46\x69\x6c\x65\x73\x7d\x7d\x0a\x0a\x09\x09\x3c\x64\x69\x76\x20\x63\x6c\x61\x73\x73\x3d\x66\x69\x6c\x65\x2d\x64\x69\x66\x66\x3e\x0a\x09\x09\x09\x3c\x68\x33\x3e\x7b\x7b\x2e\x46\x69\x6c\x65\x6e\x61\x6d\x65\x7d\x7d\x3c\x2f\x68\x33\x3e\x0a\x0a\x09\x09\x09\x7b\x7b\x69\x66\x20\x2e\x50\x61\x74\x63\x68\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x3c\x70\x72\x65\x3e\x3c\x63\x6f\x64\x65\x20\x63\x6c\x61\x73\x73\x3d\x6c\x61\x6e\x67\x75\x61\x67\x65\x2d\x64\x69\x66\x66\x3e\x7b\x7b\x2e\x50\x61\x74\x63\x68\x7d\x7d\x3c\x2f\x63\x6f\x64\x65\x3e\x3c\x2f\x70\x72\x65\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x3c\x70\x20\x63\x6c\x61\x73\x73\x3d\x66\x69\x6c\x65\x2d\x73\x74\x61\x74\x75\x73\x3e\x7b\x7b\x2e\x53\x74\x61\x74\x75\x73\x7d\x7d\x3c\x2f\x70\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x3c\x2f\x64\x69\x76\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x0a\x09\x09\x3c\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6e\x65\x20\x28\x6c\x65\x6e\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x50\x61\x72\x65\x6e\x74\x73\x29\x20\x30\x29\x7d\x7d\x0a\x09\x09\x09\x3c\x70\x3e\x50\x61\x72\x65\x6e\x74\x73\x3a\x20\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x50\x61\x72\x65\x6e\x74\x73\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x3c\x73\x70\x61\x6e\x20\x63\x6c\x61\x73\x73\x3d\x70\x61\x72\x65\x6e\x74\x2d\x63\x6f\x6d\x6d\x69\x74\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x24\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x24\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x53\x48\x41\x7d\x7d\x2f\x22\x3e\x7b\x7b\x74\x72\x75\x6e\x63\x61\x74\x65\x20\x2e\x53\x48\x41\x20\x31\x30\x7d\x7d\x3c\x2f\x61\x3e\x3c\x2f\x73\x70\x61\x6e\x3e\x20\x7b\x7b\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x3c\x2f\x70\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x09\x3c\x70\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x53\x48\x41\x7d\x7d\x2f\x62\x2f\x22\x3e\x42\x75\x69\x6c\x64\x20\x4a\x65\x6b\x79\x6c\x6c\x20\x61\x74\x20\x74\x68\x69\x73\x20\x63\x6f\x6d\x6d\x69\x74\x2e\x3c\x2f\x61\x3e\x3c\x62\x72\x3e\x50\x65\x72\x6d\x61\x6c\x69\x6e\x6b\x3a\x20\x3c\x73\x70\x61\x6e\x20\x63\x6c\x61\x73\x73\x3d\x70\x65\x72\x6d\x61\x6c\x69\x6e\x6b\x3e\x7b\x7b\x2e\x55\x52\x4c\x42\x61\x73\x65\x7d\x7d\x2f\x75\x2f\x7b\x7b\x75\x72\x6c\x71\x75\x65\x72\x79\x20\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x75\x72\x6c\x71\x75\x65\x72\x79\x20\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x75\x72\x6c\x71\x75\x65\x72\x79\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x53\x48\x41\x7d\x7d\x2f\x62\x2f\x3c\x2f\x73\x70\x61\x6e\x3e\x3c\x73\x70\x61\x6e\x20\x63\x6c\x61\x73\x73\x3d\x70\x65\x72\x6d\x61\x6c\x69\x6e\x6b\x2d\x70\x61\x74\x68\x20\x63\x6f\x6e\x74\x65\x6e\x74\x65\x64\x69\x74\x61\x62\x6c\x65\x20\x70\x6c\x61\x63\x65\x68\x6f\x6c\x64\x65\x72\x3d\x70\x61\x74\x68\x2f\x74\x6f\x2f\x66\x69\x6c\x65\x3e\x3c\x2f\x73\x70\x61\x6e\x3e\x3c\x2f\x70\x3e\x0a\x09\x09\x3c\x2f\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x3c\x2f\x6d\x61\x69\x6e\x3e\x0a\x0a\x09\x3c\x73\x63\x72\x69\x70\x74\x20\x64\x65\x66\x65\x72\x20\x73\x72\x63\x3d\x68\x74\x74\x70\x73\x3a\x2f\x2f\x63\x64\x6e\x6a\x73\x2e\x63\x6c\x6f\x75\x64\x66\x6c\x61\x72\x65\x2e\x63\x6f\x6d\x2f\x61\x6a\x61\x78\x2f\x6c\x69\x62\x73\x2f\x68\x69\x67\x68\x6c\x69\x67\x68\x74\x2e\x6a\x73\x2f\x39\x2e\x34\x2e\x30\x2f\x68\x69\x67\x68\x6c\x69\x67\x68\x74\x2e\x6d\x69\x6e\x2e\x6a\x73\x3e\x3c\x2f\x73\x63\x72\x69\x70\x74\x3e\x0a\x09\x3c\x73\x63\x72\x69\x70\x74\x20\x64\x65\x66\x65\x72\x20\x73\x72\x63\x3d\x68\x74\x74\x70\x73\x3a\x2f\x2f\x63\x64\x6e\x6a\x73\x2e\x63\x6c\x6f\x75\x64\x66\x6c\x61\x72\x65\x2e\x63\x6f\x6d\x2f\x61\x6a\x61\x78\x2f\x6c\x69\x62\x73\x2f\x68\x69\x67\x68\x6c\x69\x67\x68\x74\x2e\x6a\x73\x2f\x39\x2e\x34\x2e\x30\x2f\x6c\x61\x6e\x67\x75\x61\x67\x65\x73\x2f\x64\x69\x66\x66\x2e\x6d\x69\x6e\x2e\x6a\x73\x3e\x3c\x2f\x73\x63\x72\x69\x70\x74\x3e\x0a\x09\x3c\x73\x63\x72\x69\x70\x74\x20\x64\x65\x66\x65\x72\x20\x73\x72\x63\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x63\x6f\x6d\x6d\x69\x74\x2e\x6a\x73\x22\x7d\x7d\x22\x3e\x3c\x2f\x73\x63\x72\x69\x70\x74\x3e\x0a\x3c\x2f\x62\x6f\x64\x79\x3e\x0a\x7b\x7b\x2d\x20\x2f\x2a\x20\x2d\x2a\x2d\x20\x6d\x6f\x64\x65\x3a\x20\x68\x74\x6d\x6c\x3b\x2d\x2a\x2d\x20\x2a\x2f\x20\x2d\x7d\x7d\x0a"
+var _viewsCommitTmpl = "\x3c\x21\x64\x6f\x63\x74\x79\x70\x65\x20\x68\x74\x6d\x6c\x3e\x0a\x3c\x68\x74\x6d\x6c\x20\x6c\x61\x6e\x67\x3d\x65\x6e\x3e\x0a\x3c\x68\x65\x61\x64\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x63\x68\x61\x72\x73\x65\x74\x3d\x75\x74\x66\x2d\x38\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x6e\x61\x6d\x65\x3d\x76\x69\x65\x77\x70\x6f\x72\x74\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x22\x77\x69\x64\x74\x68\x3d\x64\x65\x76\x69\x63\x65\x2d\x77\x69\x64\x74\x68\x2c\x69\x6e\x69\x74\x69\x61\x6c\x2d\x73\x63\x61\x6c\x65\x3d\x31\x22\x3e\x0a\x09\x3c\x74\x69\x74\x6c\x65\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x40\x7b\x7b\x74\x72\x75\x6e\x63\x61\x74\x65\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x53\x48\x41\x20\x31\x30\x7d\x7d\x20\xc2\xb7\x20\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x74\x69\x74\x6c\x65\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x73\x74\x79\x6c\x65\x2e\x63\x73\x73\x22\x7d\x7d\x22\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x2e\x48\x69\x67\x68\x6c\x69\x67\x68\x74\x53\x74\x79\x6c\x65\x7d\x7d\x22\x3e\x0a\x3c\x2f\x68\x65\x61\x64\x3e\x0a\x3c\x62\x6f\x64\x79\x3e\x0a\x09\x3c\x68\x65\x61\x64\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x3c\x68\x31\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x2f\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x61\x3e\x3c\x2f\x68\x31\x3e\x0a\x09\x09\x3c\x68\x32\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x3c\x2f\x61\x3e\x2f\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x3c\x2f\x61\x3e\x40\x3c\x63\x6f\x64\x65\x3e\x7b\x7b\x74\x72\x75\x6e\x63\x61\x74\x65\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x53\x48\x41\x20\x31\x30\x7d\x7d\x3c\x2f\x63\x6f\x64\x65\x3e\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x53\x48\x41\x7d\x7d\x2f\x62\x2f\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x42\x75\x69\x6c\x64\x20\x4a\x65\x6b\x79\x6c\x6c\x20\x61\x74\x20\x74\x68\x69\x73\x20\x63\x6f\x6d\x6d\x69\x74\x22\x3e\xe2\x87\x9d\x3c\x2f\x61\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6e\x65\x20\x28\x6c\x65\x6e\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x50\x61\x72\x65\x6e\x74\x73\x29\x20\x30\x29\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x69\x6e\x64\x65\x78\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x50\x61\x72\x65\x6e\x74\x73\x20\x30\x7d\x7d\x2f\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x50\x61\x72\x65\x6e\x74\x20\x63\x6f\x6d\x6d\x69\x74\x22\x3e\xe2\x86\x91\x3c\x2f\x61\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x7b\x7b\x77\x69\x74\x68\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x48\x54\x4d\x4c\x55\x52\x4c\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x7d\x7d\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x56\x69\x65\x77\x20\x6f\x6e\x20\x7b\x7b\x24\x2e\x53\x6f\x75\x72\x63\x65\x2e\x54\x69\x74\x6c\x65\x7d\x7d\x22\x3e\xe2\xa4\xb4\x3c\x2f\x61\x3e\x7b\x7b\x65\x6e\x64\x7d\x7d\x3c\x2f\x68\x32\x3e\x0a\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x3c\x6d\x61\x69\x6e\x3e\x0a\x09\x09\x3c\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x09\x3c\x70\x20\x63\x6c\x61\x73\x73\x3d\x63\x6f\x6d\x6d\x69\x74\x2d\x6d\x65\x73\x73\x61\x67\x65\x3e\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x4d\x65\x73\x73\x61\x67\x65\x7d\x7d\x3c\x2f\x70\x3e\x0a\x09\x09\x09\x3c\x70\x20\x63\x6c\x61\x73\x73\x3d\x63\x6f\x6d\x6d\x69\x74\x2d\x61\x75\x74\x68\x6f\x72\x3e\x7b\x7b\x69\x66\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x41\x75\x74\x68\x6f\x72\x55\x52\x4c\x7d\x7d\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x41\x75\x74\x68\x6f\x72\x55\x52\x4c\x7d\x7d\x22\x3e\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x41\x75\x74\x68\x6f\x72\x4e\x61\x6d\x65\x7d\x7d\x3c\x2f\x61\x3e\x7b\x7b\x65\x6c\x73\x65\x7d\x7d\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x41\x75\x74\x68\x6f\x72\x4e\x61\x6d\x65\x7d\x7d\x7b\x7b\x65\x6e\x64\x7d\x7d\x3c\x2f\x70\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x77\x69\x74\x68\x20\x2e\x42\x75\x69\x6c\x64\x7d\x7d\x0a\x09\x09\x09\x3c\x70\x20\x63\x6c\x61\x73\x73\x3d\x62\x75\x69\x6c\x64\x2d\x69\x6e\x66\x6f\x3e\x42\x75\x69\x6c\x74\x20\x7b\x7b\x61\x67\x6f\x20\x2e\x53\x74\x61\x72\x74\x65\x64\x7d\x7d\x20\x69\x6e\x20\x7b\x7b\x2e\x44\x75\x72\x61\x74\x69\x6f\x6e\x7d\x7d\x2c\x20\x7b\x7b\x2e\x46\x69\x6c\x65\x73\x7d\x7d\x20\x66\x69\x6c\x65\x73\x7b\x7b\x77\x69\x74\x68\x20\x2e\x4a\x65\x6b\x79\x6c\x6c\x56\x65\x72\x73\x69\x6f\x6e\x7d\x7d\x20\x77\x69\x74\x68\x20\x4a\x65\x6b\x79\x6c\x6c\x20\x7b\x7b\x2e\x7d\x7d\x7b\x7b\x65\x6e\x64\x7d\x7d\x2e\x7b\x7b\x77\x69\x74\x68\x20\x2e\x57\x61\x72\x6e\x69\x6e\x67\x73\x7d\x7d\x20\x7b\x7b\x6c\x65\x6e\x20\x2e\x7d\x7d\x20\x77\x61\x72\x6e\x69\x6e\x67\x73\x2e\x7b\x7b\x65\x6e\x64\x7d\x7d\x3c\x2f\x70\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x09\x7b\x7b\x2d\x20\x72\x61\x6e\x67\x65\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x46\x69\x6c\x65\x73\x7d\x7d\x0a\x0a\x09\x09\x3c\x64\x69\x76\x20\x63\x6c\x61\x73\x73\x3d\x66\x69\x6c\x65\x2d\x64\x69\x66\x66\x3e\x0a\x09\x09\x09\x3c\x68\x33\x3e\x7b\x7b\x2e\x46\x69\x6c\x65\x6e\x61\x6d\x65\x7d\x7d\x3c\x2f\x68\x33\x3e\x0a\x0a\x09\x09\x09\x7b\x7b\x69\x66\x20\x2e\x50\x61\x74\x63\x68\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x3c\x70\x72\x65\x3e\x3c\x63\x6f\x64\x65\x20\x63\x6c\x61\x73\x73\x3d\x6c\x61\x6e\x67\x75\x61\x67\x65\x2d\x64\x69\x66\x66\x3e\x7b\x7b\x2e\x50\x61\x74\x63\x68\x7d\x7d\x3c\x2f\x63\x6f\x64\x65\x3e\x3c\x2f\x70\x72\x65\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x3c\x70\x20\x63\x6c\x61\x73\x73\x3d\x66\x69\x6c\x65\x2d\x73\x74\x61\x74\x75\x73\x3e\x7b\x7b\x2e\x53\x74\x61\x74\x75\x73\x7d\x7d\x3c\x2f\x70\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x3c\x2f\x64\x69\x76\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x0a\x09\x09\x3c\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6e\x65\x20\x28\x6c\x65\x6e\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x50\x61\x72\x65\x6e\x74\x73\x29\x20\x30\x29\x7d\x7d\x0a\x09\x09\x09\x3c\x70\x3e\x50\x61\x72\x65\x6e\x74\x73\x3a\x20\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x50\x61\x72\x65\x6e\x74\x73\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x3c\x73\x70\x61\x6e\x20\x63\x6c\x61\x73\x73\x3d\x70\x61\x72\x65\x6e\x74\x2d\x63\x6f\x6d\x6d\x69\x74\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x24\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x24\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x7d\x7d\x2f\x22\x3e\x7b\x7b\x74\x72\x75\x6e\x63\x61\x74\x65\x20\x2e\x20\x31\x30\x7d\x7d\x3c\x2f\x61\x3e\x3c\x2f\x73\x70\x61\x6e\x3e\x20\x7b\x7b\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x3c\x2f\x70\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x09\x3c\x70\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x53\x48\x41\x7d\x7d\x2f\x62\x2f\x22\x3e\x42\x75\x69\x6c\x64\x20\x4a\x65\x6b\x79\x6c\x6c\x20\x61\x74\x20\x74\x68\x69\x73\x20\x63\x6f\x6d\x6d\x69\x74\x2e\x3c\x2f\x61\x3e\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x53\x48\x41\x7d\x7d\x2f\x6c\x6f\x67\x22\x3e\x56\x69\x65\x77\x20\x74\x68\x65\x20\x62\x75\x69\x6c\x64\x20\x6c\x6f\x67\x2e\x3c\x2f\x61\x3e\x3c\x62\x72\x3e\x50\x65\x72\x6d\x61\x6c\x69\x6e\x6b\x3a\x20\x3c\x73\x70\x61\x6e\x20\x63\x6c\x61\x73\x73\x3d\x70\x65\x72\x6d\x61\x6c\x69\x6e\x6b\x3e\x7b\x7b\x2e\x55\x52\x4c\x42\x61\x73\x65\x7d\x7d\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x75\x72\x6c\x71\x75\x65\x72\x79\x20\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x75\x72\x6c\x71\x75\x65\x72\x79\x20\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x75\x72\x6c\x71\x75\x65\x72\x79\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x53\x48\x41\x7d\x7d\x2f\x62\x2f\x3c\x2f\x73\x70\x61\x6e\x3e\x3c\x73\x70\x61\x6e\x20\x63\x6c\x61\x73\x73\x3d\x70\x65\x72\x6d\x61\x6c\x69\x6e\x6b\x2d\x70\x61\x74\x68\x20\x63\x6f\x6e\x74\x65\x6e\x74\x65\x64\x69\x74\x61\x62\x6c\x65\x20\x70\x6c\x61\x63\x65\x68\x6f\x6c\x64\x65\x72\x3d\x70\x61\x74\x68\x2f\x74\x6f\x2f\x66\x69\x6c\x65\x3e\x3c\x2f\x73\x70\x61\x6e\x3e\x3c\x2f\x70\x3e\x0a\x09\x09\x3c\x2f\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x3c\x2f\x6d\x61\x69\x6e\x3e\x0a\x0a\x09\x3c\x73\x63\x72\x69\x70\x74\x20\x64\x65\x66\x65\x72\x20\x73\x72\x63\x3d\x68\x74\x74\x70\x73\x3a\x2f\x2f\x63\x64\x6e\x6a\x73\x2e\x63\x6c\x6f\x75\x64\x66\x6c\x61\x72\x65\x2e\x63\x6f\x6d\x2f\x61\x6a\x61\x78\x2f\x6c\x69\x62\x73\x2f\x68\x69\x67\x68\x6c\x69\x67\x68\x74\x2e\x6a\x73\x2f\x39\x2e\x34\x2e\x30\x2f\x68\x69\x67\x68\x6c\x69\x67\x68\x74\x2e\x6d\x69\x6e\x2e\x6a\x73\x3e\x3c\x2f\x73\x63\x72\x69\x70\x74\x3e\x0a\x09\x3c\x73\x63\x72\x69\x70\x74\x20\x64\x65\x66\x65\x72\x20\x73\x72\x63\x3d\x68\x74\x74\x70\x73\x3a\x2f\x2f\x63\x64\x6e\x6a\x73\x2e\x63\x6c\x6f\x75\x64\x66\x6c\x61\x72\x65\x2e\x63\x6f\x6d\x2f\x61\x6a\x61\x78\x2f\x6c\x69\x62\x73\x2f\x68\x69\x67\x68\x6c\x69\x67\x68\x74\x2e\x6a\x73\x2f\x39\x2e\x34\x2e\x30\x2f\x6c\x61\x6e\x67\x75\x61\x67\x65\x73\x2f\x64\x69\x66\x66\x2e\x6d\x69\x6e\x2e\x6a\x73\x3e\x3c\x2f\x73\x63\x72\x69\x70\x74\x3e\x0a\x09\x3c\x73\x63\x72\x69\x70\x74\x20\x64\x65\x66\x65\x72\x20\x73\x72\x63\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x63\x6f\x6d\x6d\x69\x74\x2e\x6a\x73\x22\x7d\x7d\x22\x3e\x3c\x2f\x73\x63\x72\x69\x70\x74\x3e\x0a\x3c\x2f\x62\x6f\x64\x79\x3e\x0a\x7b\x7b\x2d\x20\x2f\x2a\x20\x2d\x2a\x2d\x20\x6d\x6f\x64\x65\x3a\x20\x68\x74\x6d\x6c\x3b\x2d\x2a\x2d\x20\x2a\x2f\x20\x2d\x7d\x7d\x0a"
 
 func viewsCommitTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -140,12 +144,12 @@ func viewsCommitTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "views/commit.tmpl", size: 2192, mode: os.FileMode(420), modTime: time.Unix(1487842123, 0)}
+	info := bindataFileInfo{name: "views/commit.tmpl", size: 2633, mode: os.FileMode(420), modTime: time.Unix(1792212197, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _viewsErrorTmpl = "\x3c\x21\x64\x6f\x63\x74\x79\x70\x65\x20\x68\x74\x6d\x6c\x3e\x0a\x3c\x68\x74\x6d\x6c\x20\x6c\x61\x6e\x67\x3d\x65\x6e\x3e\x0a\x3c\x68\x65\x61\x64\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x63\x68\x61\x72\x73\x65\x74\x3d\x75\x74\x66\x2d\x38\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x6e\x61\x6d\x65\x3d\x76\x69\x65\x77\x70\x6f\x72\x74\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x22\x77\x69\x64\x74\x68\x3d\x64\x65\x76\x69\x63\x65\x2d\x77\x69\x64\x74\x68\x2c\x69\x6e\x69\x74\x69\x61\x6c\x2d\x73\x63\x61\x6c\x65\x3d\x31\x22\x3e\x0a\x09\x3c\x74\x69\x74\x6c\x65\x3e\x7b\x7b\x2e\x43\x6f\x64\x65\x7d\x7d\x3a\x20\x7b\x7b\x2e\x4e\x61\x6d\x65\x7d\x7d\x20\xc2\xb7\x20\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x74\x69\x74\x6c\x65\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x73\x74\x79\x6c\x65\x2e\x63\x73\x73\x22\x7d\x7d\x22\x3e\x0a\x3c\x2f\x68\x65\x61\x64\x3e\x0a\x3c\x62\x6f\x64\x79\x3e\x0a\x09\x3c\x68\x65\x61\x64\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x3c\x68\x31\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x2f\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x61\x3e\x3c\x2f\x68\x31\x3e\x0a\x09\x09\x3c\x68\x32\x3e\x7b\x7b\x2e\x43\x6f\x64\x65\x7d\x7d\x3a\x20\x7b\x7b\x2e\x4e\x61\x6d\x65\x7d\x7d\x3c\x2f\x68\x32\x3e\x0a\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x3c\x6d\x61\x69\x6e\x3e\x0a\x09\x09\x7b\x7b\x69\x66\x20\x2e\x4d\x65\x73\x73\x61\x67\x65\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x3c\x70\x3e\x7b\x7b\x2e\x4d\x65\x73\x73\x61\x67\x65\x7d\x7d\x3c\x2f\x70\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x7b\x7b\x69\x66\x20\x2e\x44\x65\x73\x63\x72\x69\x70\x74\x69\x6f\x6e\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x3c\x70\x3e\x7b\x7b\x2e\x44\x65\x73\x63\x72\x69\x70\x74\x69\x6f\x6e\x7d\x7d\x3c\x2f\x70\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x3c\x2f\x6d\x61\x69\x6e\x3e\x0a\x3c\x2f\x62\x6f\x64\x79\x3e\x0a\x7b\x7b\x2d\x20\x2e\x50\x61\x64\x64\x69\x6e\x67\x20\x2d\x7d\x7d\x0a\x7b\x7b\x2d\x20\x2f\x2a\x20\x2d\x2a\x2d\x20\x6d\x6f\x64\x65\x3a\x20\x68\x74\x6d\x6c\x3b\x2d\x2a\x2d\x20\x2a\x2f\x20\x2d\x7d\x7d\x0a"
+var _viewsErrorTmpl = "\x3c\x21\x64\x6f\x63\x74\x79\x70\x65\x20\x68\x74\x6d\x6c\x3e\x0a\x3c\x68\x74\x6d\x6c\x20\x6c\x61\x6e\x67\x3d\x65\x6e\x3e\x0a\x3c\x68\x65\x61\x64\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x63\x68\x61\x72\x73\x65\x74\x3d\x75\x74\x66\x2d\x38\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x6e\x61\x6d\x65\x3d\x76\x69\x65\x77\x70\x6f\x72\x74\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x22\x77\x69\x64\x74\x68\x3d\x64\x65\x76\x69\x63\x65\x2d\x77\x69\x64\x74\x68\x2c\x69\x6e\x69\x74\x69\x61\x6c\x2d\x73\x63\x61\x6c\x65\x3d\x31\x22\x3e\x0a\x09\x3c\x74\x69\x74\x6c\x65\x3e\x7b\x7b\x2e\x43\x6f\x64\x65\x7d\x7d\x3a\x20\x7b\x7b\x2e\x4e\x61\x6d\x65\x7d\x7d\x20\xc2\xb7\x20\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x74\x69\x74\x6c\x65\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x73\x74\x79\x6c\x65\x2e\x63\x73\x73\x22\x7d\x7d\x22\x3e\x0a\x3c\x2f\x68\x65\x61\x64\x3e\x0a\x3c\x62\x6f\x64\x79\x3e\x0a\x09\x3c\x68\x65\x61\x64\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x3c\x68\x31\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x2f\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x61\x3e\x3c\x2f\x68\x31\x3e\x0a\x09\x09\x3c\x68\x32\x3e\x7b\x7b\x2e\x43\x6f\x64\x65\x7d\x7d\x3a\x20\x7b\x7b\x2e\x4e\x61\x6d\x65\x7d\x7d\x3c\x2f\x68\x32\x3e\x0a\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x3c\x6d\x61\x69\x6e\x3e\x0a\x09\x09\x7b\x7b\x69\x66\x20\x2e\x4d\x65\x73\x73\x61\x67\x65\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x3c\x70\x3e\x7b\x7b\x2e\x4d\x65\x73\x73\x61\x67\x65\x7d\x7d\x3c\x2f\x70\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x7b\x7b\x69\x66\x20\x2e\x44\x65\x73\x63\x72\x69\x70\x74\x69\x6f\x6e\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x3c\x70\x3e\x7b\x7b\x2e\x44\x65\x73\x63\x72\x69\x70\x74\x69\x6f\x6e\x7d\x7d\x3c\x2f\x70\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x7b\x7b\x69\x66\x20\x2e\x4c\x6f\x67\x55\x52\x4c\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x3c\x70\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x4c\x6f\x67\x55\x52\x4c\x7d\x7d\x22\x3e\x56\x69\x65\x77\x20\x74\x68\x65\x20\x62\x75\x69\x6c\x64\x20\x6c\x6f\x67\x2e\x3c\x2f\x61\x3e\x3c\x2f\x70\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x3c\x2f\x6d\x61\x69\x6e\x3e\x0a\x3c\x2f\x62\x6f\x64\x79\x3e\x0a\x7b\x7b\x2d\x20\x2e\x50\x61\x64\x64\x69\x6e\x67\x20\x2d\x7d\x7d\x0a\x7b\x7b\x2d\x20\x2f\x2a\x20\x2d\x2a\x2d\x20\x6d\x6f\x64\x65\x3a\x20\x68\x74\x6d\x6c\x3b\x2d\x2a\x2d\x20\x2a\x2f\x20\x2d\x7d\x7d\x0a"
 
 func viewsErrorTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -160,12 +164,12 @@ func viewsErrorTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "views/error.tmpl", size: 559, mode: os.FileMode(420), modTime: time.Unix(1487842123, 0)}
+	info := bindataFileInfo{name: "views/error.tmpl", size: 646, mode: os.FileMode(420), modTime: time.Unix(1792210776, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _viewsIndexTmpl = "\x3c\x21\x64\x6f\x63\x74\x79\x70\x65\x20\x68\x74\x6d\x6c\x3e\x0a\x3c\x68\x74\x6d\x6c\x20\x6c\x61\x6e\x67\x3d\x65\x6e\x3e\x0a\x3c\x68\x65\x61\x64\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x63\x68\x61\x72\x73\x65\x74\x3d\x75\x74\x66\x2d\x38\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x6e\x61\x6d\x65\x3d\x76\x69\x65\x77\x70\x6f\x72\x74\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x22\x77\x69\x64\x74\x68\x3d\x64\x65\x76\x69\x63\x65\x2d\x77\x69\x64\x74\x68\x2c\x69\x6e\x69\x74\x69\x61\x6c\x2d\x73\x63\x61\x6c\x65\x3d\x31\x22\x3e\x0a\x09\x3c\x74\x69\x74\x6c\x65\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x74\x69\x74\x6c\x65\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x73\x74\x79\x6c\x65\x2e\x63\x73\x73\x22\x7d\x7d\x22\x3e\x0a\x3c\x2f\x68\x65\x61\x64\x3e\x0a\x3c\x62\x6f\x64\x79\x3e\x0a\x09\x3c\x68\x65\x61\x64\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x3c\x68\x31\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x68\x31\x3e\x0a\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x3c\x6d\x61\x69\x6e\x3e\x0a\x09\x09\x3c\x66\x6f\x72\x6d\x20\x6d\x65\x74\x68\x6f\x64\x3d\x67\x65\x74\x20\x61\x63\x74\x69\x6f\x6e\x3d\x2f\x67\x6f\x74\x6f\x2f\x3e\x0a\x09\x09\x09\x3c\x6c\x61\x62\x65\x6c\x20\x66\x6f\x72\x3d\x75\x72\x6c\x20\x63\x6c\x61\x73\x73\x3d\x6f\x66\x66\x73\x63\x72\x65\x65\x6e\x3e\x47\x69\x74\x48\x75\x62\x20\x55\x52\x4c\x3a\x3c\x2f\x6c\x61\x62\x65\x6c\x3e\x0a\x09\x09\x09\x3c\x69\x6e\x70\x75\x74\x20\x63\x6c\x61\x73\x73\x3d\x75\x72\x6c\x20\x6e\x61\x6d\x65\x3d\x75\x72\x6c\x20\x74\x79\x70\x65\x3d\x75\x72\x6c\x20\x70\x6c\x61\x63\x65\x68\x6f\x6c\x64\x65\x72\x3d\x22\x50\x61\x73\x74\x65\x20\x61\x20\x47\x69\x74\x48\x75\x62\x20\x75\x73\x65\x72\x2c\x20\x72\x65\x70\x6f\x20\x6f\x72\x20\x63\x6f\x6d\x6d\x69\x74\x20\x55\x52\x4c\x20\x68\x65\x72\x65\x2e\x22\x20\x61\x75\x74\x6f\x66\x6f\x63\x75\x73\x3e\x0a\x09\x09\x3c\x2f\x66\x6f\x72\x6d\x3e\x0a\x0a\x09\x09\x3c\x70\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x20\x69\x73\x20\x61\x20\x68\x6f\x73\x74\x65\x64\x20\x73\x65\x72\x76\x69\x63\x65\x20\x74\x68\x61\x74\x20\x64\x6f\x77\x6e\x6c\x6f\x61\x64\x73\x20\x61\x20\x67\x69\x74\x20\x72\x65\x70\x6f\x73\x69\x74\x6f\x72\x79\x20\x61\x6e\x64\x20\x72\x75\x6e\x73\x20\x3c\x63\x6f\x64\x65\x3e\x6a\x65\x6b\x79\x6c\x6c\x20\x62\x75\x69\x6c\x64\x3c\x2f\x63\x6f\x64\x65\x3e\x20\x61\x74\x20\x61\x6e\x79\x20\x63\x6f\x6d\x6d\x69\x74\x20\x69\x6e\x20\x69\x74\x73\x20\x68\x69\x73\x74\x6f\x72\x79\x2e\x3c\x2f\x70\x3e\x0a\x09\x3c\x2f\x6d\x61\x69\x6e\x3e\x0a\x0a\x09\x3c\x66\x6f\x6f\x74\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x3c\x70\x3e\xc2\xa9\x20\x32\x30\x31\x36\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x68\x74\x74\x70\x73\x3a\x2f\x2f\x74\x6f\x6d\x74\x68\x6f\x72\x6f\x67\x6f\x6f\x64\x2e\x63\x6f\x2e\x75\x6b\x2f\x3e\x54\x6f\x6d\x20\x54\x68\x6f\x72\x6f\x67\x6f\x6f\x64\x3c\x2f\x61\x3e\x2e\x3c\x2f\x70\x3e\x0a\x09\x3c\x2f\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x3c\x2f\x62\x6f\x64\x79\x3e\x0a\x7b\x7b\x2d\x20\x2f\x2a\x20\x2d\x2a\x2d\x20\x6d\x6f\x64\x65\x3a\x20\x68\x74\x6d\x6c\x3b\x2d\x2a\x2d\x20\x2a\x2f\x20\x2d\x7d\x7d\x0a"
+var _viewsIndexTmpl = "\x3c\x21\x64\x6f\x63\x74\x79\x70\x65\x20\x68\x74\x6d\x6c\x3e\x0a\x3c\x68\x74\x6d\x6c\x20\x6c\x61\x6e\x67\x3d\x65\x6e\x3e\x0a\x3c\x68\x65\x61\x64\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x63\x68\x61\x72\x73\x65\x74\x3d\x75\x74\x66\x2d\x38\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x6e\x61\x6d\x65\x3d\x76\x69\x65\x77\x70\x6f\x72\x74\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x22\x77\x69\x64\x74\x68\x3d\x64\x65\x76\x69\x63\x65\x2d\x77\x69\x64\x74\x68\x2c\x69\x6e\x69\x74\x69\x61\x6c\x2d\x73\x63\x61\x6c\x65\x3d\x31\x22\x3e\x0a\x09\x3c\x74\x69\x74\x6c\x65\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x74\x69\x74\x6c\x65\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x73\x74\x79\x6c\x65\x2e\x63\x73\x73\x22\x7d\x7d\x22\x3e\x0a\x3c\x2f\x68\x65\x61\x64\x3e\x0a\x3c\x62\x6f\x64\x79\x3e\x0a\x09\x3c\x68\x65\x61\x64\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x3c\x68\x31\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x68\x31\x3e\x0a\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x3c\x6d\x61\x69\x6e\x3e\x0a\x09\x09\x3c\x66\x6f\x72\x6d\x20\x6d\x65\x74\x68\x6f\x64\x3d\x67\x65\x74\x20\x61\x63\x74\x69\x6f\x6e\x3d\x2f\x67\x6f\x74\x6f\x2f\x3e\x0a\x09\x09\x09\x3c\x6c\x61\x62\x65\x6c\x20\x66\x6f\x72\x3d\x75\x72\x6c\x20\x63\x6c\x61\x73\x73\x3d\x6f\x66\x66\x73\x63\x72\x65\x65\x6e\x3e\x52\x65\x70\x6f\x73\x69\x74\x6f\x72\x79\x20\x55\x52\x4c\x3a\x3c\x2f\x6c\x61\x62\x65\x6c\x3e\x0a\x09\x09\x09\x3c\x69\x6e\x70\x75\x74\x20\x63\x6c\x61\x73\x73\x3d\x75\x72\x6c\x20\x6e\x61\x6d\x65\x3d\x75\x72\x6c\x20\x74\x79\x70\x65\x3d\x75\x72\x6c\x20\x70\x6c\x61\x63\x65\x68\x6f\x6c\x64\x65\x72\x3d\x22\x50\x61\x73\x74\x65\x20\x61\x20\x47\x69\x74\x48\x75\x62\x2c\x20\x47\x69\x74\x4c\x61\x62\x20\x6f\x72\x20\x47\x69\x74\x65\x61\x20\x75\x73\x65\x72\x2c\x20\x72\x65\x70\x6f\x20\x6f\x72\x20\x63\x6f\x6d\x6d\x69\x74\x20\x55\x52\x4c\x20\x68\x65\x72\x65\x2e\x22\x20\x61\x75\x74\x6f\x66\x6f\x63\x75\x73\x3e\x0a\x09\x09\x3c\x2f\x66\x6f\x72\x6d\x3e\x0a\x0a\x09\x09\x3c\x70\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x20\x69\x73\x20\x61\x20\x68\x6f\x73\x74\x65\x64\x20\x73\x65\x72\x76\x69\x63\x65\x20\x74\x68\x61\x74\x20\x64\x6f\x77\x6e\x6c\x6f\x61\x64\x73\x20\x61\x20\x67\x69\x74\x20\x72\x65\x70\x6f\x73\x69\x74\x6f\x72\x79\x20\x61\x6e\x64\x20\x72\x75\x6e\x73\x20\x3c\x63\x6f\x64\x65\x3e\x6a\x65\x6b\x79\x6c\x6c\x20\x62\x75\x69\x6c\x64\x3c\x2f\x63\x6f\x64\x65\x3e\x20\x61\x74\x20\x61\x6e\x79\x20\x63\x6f\x6d\x6d\x69\x74\x20\x69\x6e\x20\x69\x74\x73\x20\x68\x69\x73\x74\x6f\x72\x79\x2e\x3c\x2f\x70\x3e\x0a\x09\x3c\x2f\x6d\x61\x69\x6e\x3e\x0a\x0a\x09\x3c\x66\x6f\x6f\x74\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x3c\x70\x3e\xc2\xa9\x20\x32\x30\x31\x36\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x68\x74\x74\x70\x73\x3a\x2f\x2f\x74\x6f\x6d\x74\x68\x6f\x72\x6f\x67\x6f\x6f\x64\x2e\x63\x6f\x2e\x75\x6b\x2f\x3e\x54\x6f\x6d\x20\x54\x68\x6f\x72\x6f\x67\x6f\x6f\x64\x3c\x2f\x61\x3e\x2e\x3c\x2f\x70\x3e\x0a\x09\x3c\x2f\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x3c\x2f\x62\x6f\x64\x79\x3e\x0a\x7b\x7b\x2d\x20\x2f\x2a\x20\x2d\x2a\x2d\x20\x6d\x6f\x64\x65\x3a\x20\x68\x74\x6d\x6c\x3b\x2d\x2a\x2d\x20\x2a\x2f\x20\x2d\x7d\x7d\x0a"
 
 func viewsIndexTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -180,12 +184,72 @@ func viewsIndexTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "views/index.tmpl", size: 815, mode: os.FileMode(420), modTime: time.Unix(1487842123, 0)}
+	info := bindataFileInfo{name: "views/index.tmpl", size: 836, mode: os.FileMode(420), modTime: time.Unix(1792211306, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _viewsRepoTmpl = "\x3c\x21\x64\x6f\x63\x74\x79\x70\x65\x20\x68\x74\x6d\x6c\x3e\x0a\x3c\x68\x74\x6d\x6c\x20\x6c\x61\x6e\x67\x3d\x65\x6e\x3e\x0a\x3c\x68\x65\x61\x64\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x63\x68\x61\x72\x73\x65\x74\x3d\x75\x74\x66\x2d\x38\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x6e\x61\x6d\x65\x3d\x76\x69\x65\x77\x70\x6f\x72\x74\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x22\x77\x69\x64\x74\x68\x3d\x64\x65\x76\x69\x63\x65\x2d\x77\x69\x64\x74\x68\x2c\x69\x6e\x69\x74\x69\x61\x6c\x2d\x73\x63\x61\x6c\x65\x3d\x31\x22\x3e\x0a\x09\x3c\x74\x69\x74\x6c\x65\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x7b\x7b\x69\x66\x20\x2e\x54\x72\x65\x65\x7d\x7d\x2f\x7b\x7b\x2e\x54\x72\x65\x65\x7d\x7d\x7b\x7b\x65\x6e\x64\x7d\x7d\x20\xc2\xb7\x20\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x74\x69\x74\x6c\x65\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x73\x74\x79\x6c\x65\x2e\x63\x73\x73\x22\x7d\x7d\x22\x3e\x0a\x3c\x2f\x68\x65\x61\x64\x3e\x0a\x3c\x62\x6f\x64\x79\x3e\x0a\x09\x3c\x68\x65\x61\x64\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x3c\x68\x31\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x2f\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x61\x3e\x3c\x2f\x68\x31\x3e\x0a\x09\x09\x3c\x68\x32\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x2e\x54\x72\x65\x65\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x2f\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x3c\x2f\x61\x3e\x2f\x7b\x7b\x2e\x54\x72\x65\x65\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x68\x74\x74\x70\x73\x3a\x2f\x2f\x67\x69\x74\x68\x75\x62\x2e\x63\x6f\x6d\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x74\x72\x65\x65\x2f\x7b\x7b\x2e\x54\x72\x65\x65\x7d\x7d\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x56\x69\x65\x77\x20\x6f\x6e\x20\x47\x69\x74\x48\x75\x62\x22\x3e\xe2\xa4\xb4\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x68\x74\x74\x70\x73\x3a\x2f\x2f\x67\x69\x74\x68\x75\x62\x2e\x63\x6f\x6d\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x56\x69\x65\x77\x20\x6f\x6e\x20\x47\x69\x74\x48\x75\x62\x22\x3e\xe2\xa4\xb4\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x3c\x2f\x68\x32\x3e\x0a\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x3c\x6d\x61\x69\x6e\x3e\x0a\x09\x09\x3c\x75\x6c\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x72\x61\x6e\x67\x65\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x73\x7d\x7d\x0a\x09\x09\x09\x3c\x6c\x69\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x24\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x24\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x53\x48\x41\x7d\x7d\x2f\x22\x3e\x3c\x63\x6f\x64\x65\x3e\x7b\x7b\x74\x72\x75\x6e\x63\x61\x74\x65\x20\x2e\x53\x48\x41\x20\x31\x30\x7d\x7d\x3c\x2f\x63\x6f\x64\x65\x3e\x3c\x2f\x61\x3e\x3a\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x7d\x7d\x20\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x2e\x4d\x65\x73\x73\x61\x67\x65\x7d\x7d\x7b\x7b\x65\x6e\x64\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x48\x54\x4d\x4c\x55\x52\x4c\x7d\x7d\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x56\x69\x65\x77\x20\x6f\x6e\x20\x47\x69\x74\x48\x75\x62\x22\x3e\xe2\xa4\xb4\x3c\x2f\x61\x3e\x3c\x2f\x6c\x69\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x3c\x2f\x75\x6c\x3e\x0a\x0a\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6f\x72\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x29\x7d\x7d\x0a\x0a\x09\x09\x3c\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x09\x3c\x70\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x2e\x54\x72\x65\x65\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x65\x71\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x31\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x74\x2f\x7b\x7b\x2e\x54\x72\x65\x65\x7d\x7d\x2f\x22\x3e\xe2\x86\x90\x20\x50\x72\x65\x76\x20\x70\x61\x67\x65\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x20\x69\x66\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x74\x2f\x7b\x7b\x2e\x54\x72\x65\x65\x7d\x7d\x2f\x70\x2f\x7b\x7b\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x7d\x7d\x2f\x22\x3e\xe2\x86\x90\x20\x50\x72\x65\x76\x20\x70\x61\x67\x65\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x61\x6e\x64\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x29\x7d\x7d\x20\xc2\xb7\x20\x7b\x7b\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x74\x2f\x7b\x7b\x2e\x54\x72\x65\x65\x7d\x7d\x2f\x70\x2f\x7b\x7b\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x7d\x7d\x2f\x22\x3e\x4e\x65\x78\x74\x20\x70\x61\x67\x65\x20\xe2\x86\x92\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x65\x71\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x31\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x22\x3e\xe2\x86\x90\x20\x50\x72\x65\x76\x20\x70\x61\x67\x65\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x20\x69\x66\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x70\x2f\x7b\x7b\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x7d\x7d\x2f\x22\x3e\xe2\x86\x90\x20\x50\x72\x65\x76\x20\x70\x61\x67\x65\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x61\x6e\x64\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x29\x7d\x7d\x20\xc2\xb7\x20\x7b\x7b\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x70\x2f\x7b\x7b\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x7d\x7d\x2f\x22\x3e\x4e\x65\x78\x74\x20\x70\x61\x67\x65\x20\xe2\x86\x92\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x3c\x2f\x70\x3e\x0a\x09\x09\x3c\x2f\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x3c\x2f\x6d\x61\x69\x6e\x3e\x0a\x3c\x2f\x62\x6f\x64\x79\x3e\x0a\x7b\x7b\x2d\x20\x2f\x2a\x20\x2d\x2a\x2d\x20\x6d\x6f\x64\x65\x3a\x20\x68\x74\x6d\x6c\x3b\x2d\x2a\x2d\x20\x2a\x2f\x20\x2d\x7d\x7d\x0a"
+var _viewsPullTmpl = "\x3c\x21\x64\x6f\x63\x74\x79\x70\x65\x20\x68\x74\x6d\x6c\x3e\x0a\x3c\x68\x74\x6d\x6c\x20\x6c\x61\x6e\x67\x3d\x65\x6e\x3e\x0a\x3c\x68\x65\x61\x64\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x63\x68\x61\x72\x73\x65\x74\x3d\x75\x74\x66\x2d\x38\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x6e\x61\x6d\x65\x3d\x76\x69\x65\x77\x70\x6f\x72\x74\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x22\x77\x69\x64\x74\x68\x3d\x64\x65\x76\x69\x63\x65\x2d\x77\x69\x64\x74\x68\x2c\x69\x6e\x69\x74\x69\x61\x6c\x2d\x73\x63\x61\x6c\x65\x3d\x31\x22\x3e\x0a\x09\x3c\x74\x69\x74\x6c\x65\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x23\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x4e\x75\x6d\x62\x65\x72\x7d\x7d\x20\xc2\xb7\x20\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x74\x69\x74\x6c\x65\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x73\x74\x79\x6c\x65\x2e\x63\x73\x73\x22\x7d\x7d\x22\x3e\x0a\x3c\x2f\x68\x65\x61\x64\x3e\x0a\x3c\x62\x6f\x64\x79\x3e\x0a\x09\x3c\x68\x65\x61\x64\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x3c\x68\x31\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x2f\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x61\x3e\x3c\x2f\x68\x31\x3e\x0a\x09\x09\x3c\x68\x32\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x3c\x2f\x61\x3e\x2f\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x3c\x2f\x61\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x70\x75\x6c\x6c\x73\x2f\x22\x3e\x23\x3c\x2f\x61\x3e\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x4e\x75\x6d\x62\x65\x72\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x53\x48\x41\x7d\x7d\x2f\x62\x2f\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x42\x75\x69\x6c\x64\x20\x4a\x65\x6b\x79\x6c\x6c\x20\x61\x74\x20\x74\x68\x65\x20\x68\x65\x61\x64\x20\x63\x6f\x6d\x6d\x69\x74\x22\x3e\xe2\x87\x9d\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x77\x69\x74\x68\x20\x2e\x50\x75\x6c\x6c\x2e\x48\x54\x4d\x4c\x55\x52\x4c\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x7d\x7d\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x56\x69\x65\x77\x20\x6f\x6e\x20\x7b\x7b\x24\x2e\x53\x6f\x75\x72\x63\x65\x2e\x54\x69\x74\x6c\x65\x7d\x7d\x22\x3e\xe2\xa4\xb4\x3c\x2f\x61\x3e\x7b\x7b\x65\x6e\x64\x7d\x7d\x3c\x2f\x68\x32\x3e\x0a\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x3c\x6d\x61\x69\x6e\x3e\x0a\x09\x09\x3c\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x09\x3c\x70\x20\x63\x6c\x61\x73\x73\x3d\x63\x6f\x6d\x6d\x69\x74\x2d\x6d\x65\x73\x73\x61\x67\x65\x3e\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x54\x69\x74\x6c\x65\x7d\x7d\x3c\x2f\x70\x3e\x0a\x09\x09\x09\x3c\x70\x20\x63\x6c\x61\x73\x73\x3d\x63\x6f\x6d\x6d\x69\x74\x2d\x61\x75\x74\x68\x6f\x72\x3e\x7b\x7b\x69\x66\x20\x2e\x50\x75\x6c\x6c\x2e\x41\x75\x74\x68\x6f\x72\x55\x52\x4c\x7d\x7d\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x41\x75\x74\x68\x6f\x72\x55\x52\x4c\x7d\x7d\x22\x3e\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x41\x75\x74\x68\x6f\x72\x4e\x61\x6d\x65\x7d\x7d\x3c\x2f\x61\x3e\x7b\x7b\x65\x6c\x73\x65\x7d\x7d\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x41\x75\x74\x68\x6f\x72\x4e\x61\x6d\x65\x7d\x7d\x7b\x7b\x65\x6e\x64\x7d\x7d\x3c\x2f\x70\x3e\x0a\x09\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x09\x3c\x70\x3e\x48\x65\x61\x64\x3a\x20\x3c\x63\x6f\x64\x65\x3e\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x55\x73\x65\x72\x7d\x7d\x3a\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x52\x65\x66\x7d\x7d\x3c\x2f\x63\x6f\x64\x65\x3e\x20\x61\x74\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x53\x48\x41\x7d\x7d\x2f\x22\x3e\x3c\x63\x6f\x64\x65\x3e\x7b\x7b\x74\x72\x75\x6e\x63\x61\x74\x65\x20\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x53\x48\x41\x20\x31\x30\x7d\x7d\x3c\x2f\x63\x6f\x64\x65\x3e\x3c\x2f\x61\x3e\x3c\x2f\x70\x3e\x0a\x0a\x09\x09\x3c\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x09\x3c\x70\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x53\x48\x41\x7d\x7d\x2f\x62\x2f\x22\x3e\x42\x75\x69\x6c\x64\x20\x4a\x65\x6b\x79\x6c\x6c\x20\x61\x74\x20\x74\x68\x65\x20\x68\x65\x61\x64\x20\x63\x6f\x6d\x6d\x69\x74\x2e\x3c\x2f\x61\x3e\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x50\x75\x6c\x6c\x2e\x48\x65\x61\x64\x53\x48\x41\x7d\x7d\x2f\x6c\x6f\x67\x22\x3e\x56\x69\x65\x77\x20\x74\x68\x65\x20\x62\x75\x69\x6c\x64\x20\x6c\x6f\x67\x2e\x3c\x2f\x61\x3e\x3c\x2f\x70\x3e\x0a\x09\x09\x3c\x2f\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x3c\x2f\x6d\x61\x69\x6e\x3e\x0a\x3c\x2f\x62\x6f\x64\x79\x3e\x0a\x7b\x7b\x2d\x20\x2f\x2a\x20\x2d\x2a\x2d\x20\x6d\x6f\x64\x65\x3a\x20\x68\x74\x6d\x6c\x3b\x2d\x2a\x2d\x20\x2a\x2f\x20\x2d\x7d\x7d\x0a"
+
+func viewsPullTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_viewsPullTmpl,
+		"views/pull.tmpl",
+	)
+}
+
+func viewsPullTmpl() (*asset, error) {
+	bytes, err := viewsPullTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "views/pull.tmpl", size: 1515, mode: os.FileMode(420), modTime: time.Unix(1792214509, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _viewsPullsTmpl = "\x3c\x21\x64\x6f\x63\x74\x79\x70\x65\x20\x68\x74\x6d\x6c\x3e\x0a\x3c\x68\x74\x6d\x6c\x20\x6c\x61\x6e\x67\x3d\x65\x6e\x3e\x0a\x3c\x68\x65\x61\x64\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x63\x68\x61\x72\x73\x65\x74\x3d\x75\x74\x66\x2d\x38\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x6e\x61\x6d\x65\x3d\x76\x69\x65\x77\x70\x6f\x72\x74\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x22\x77\x69\x64\x74\x68\x3d\x64\x65\x76\x69\x63\x65\x2d\x77\x69\x64\x74\x68\x2c\x69\x6e\x69\x74\x69\x61\x6c\x2d\x73\x63\x61\x6c\x65\x3d\x31\x22\x3e\x0a\x09\x3c\x74\x69\x74\x6c\x65\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x20\x70\x75\x6c\x6c\x20\x72\x65\x71\x75\x65\x73\x74\x73\x20\xc2\xb7\x20\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x74\x69\x74\x6c\x65\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x73\x74\x79\x6c\x65\x2e\x63\x73\x73\x22\x7d\x7d\x22\x3e\x0a\x3c\x2f\x68\x65\x61\x64\x3e\x0a\x3c\x62\x6f\x64\x79\x3e\x0a\x09\x3c\x68\x65\x61\x64\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x3c\x68\x31\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x2f\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x61\x3e\x3c\x2f\x68\x31\x3e\x0a\x09\x09\x3c\x68\x32\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x3c\x2f\x61\x3e\x2f\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x3c\x2f\x61\x3e\x2f\x70\x75\x6c\x6c\x73\x3c\x2f\x68\x32\x3e\x0a\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x3c\x6d\x61\x69\x6e\x3e\x0a\x09\x09\x3c\x75\x6c\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x72\x61\x6e\x67\x65\x20\x2e\x50\x75\x6c\x6c\x73\x7d\x7d\x0a\x09\x09\x09\x3c\x6c\x69\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x24\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x24\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x70\x75\x6c\x6c\x2f\x7b\x7b\x2e\x4e\x75\x6d\x62\x65\x72\x7d\x7d\x2f\x22\x3e\x23\x7b\x7b\x2e\x4e\x75\x6d\x62\x65\x72\x7d\x7d\x3c\x2f\x61\x3e\x3a\x20\x7b\x7b\x2e\x54\x69\x74\x6c\x65\x7d\x7d\x20\xc2\xb7\x0a\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x48\x65\x61\x64\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x48\x65\x61\x64\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x48\x65\x61\x64\x53\x48\x41\x7d\x7d\x2f\x22\x3e\x3c\x63\x6f\x64\x65\x3e\x7b\x7b\x74\x72\x75\x6e\x63\x61\x74\x65\x20\x2e\x48\x65\x61\x64\x53\x48\x41\x20\x31\x30\x7d\x7d\x3c\x2f\x63\x6f\x64\x65\x3e\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x48\x65\x61\x64\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x48\x65\x61\x64\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x48\x65\x61\x64\x53\x48\x41\x7d\x7d\x2f\x62\x2f\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x42\x75\x69\x6c\x64\x20\x4a\x65\x6b\x79\x6c\x6c\x20\x61\x74\x20\x74\x68\x69\x73\x20\x63\x6f\x6d\x6d\x69\x74\x22\x3e\xe2\x87\x9d\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x77\x69\x74\x68\x20\x2e\x48\x54\x4d\x4c\x55\x52\x4c\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x7d\x7d\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x56\x69\x65\x77\x20\x6f\x6e\x20\x7b\x7b\x24\x2e\x53\x6f\x75\x72\x63\x65\x2e\x54\x69\x74\x6c\x65\x7d\x7d\x22\x3e\xe2\xa4\xb4\x3c\x2f\x61\x3e\x7b\x7b\x65\x6e\x64\x7d\x7d\x3c\x2f\x6c\x69\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x7d\x7d\x0a\x09\x09\x09\x3c\x6c\x69\x3e\x54\x68\x65\x72\x65\x20\x61\x72\x65\x20\x6e\x6f\x20\x6f\x70\x65\x6e\x20\x70\x75\x6c\x6c\x20\x72\x65\x71\x75\x65\x73\x74\x73\x2e\x3c\x2f\x6c\x69\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x3c\x2f\x75\x6c\x3e\x0a\x0a\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6f\x72\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x29\x7d\x7d\x0a\x0a\x09\x09\x3c\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x09\x3c\x70\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x65\x71\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x31\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x70\x75\x6c\x6c\x73\x2f\x22\x3e\xe2\x86\x90\x20\x50\x72\x65\x76\x20\x70\x61\x67\x65\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x20\x69\x66\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x70\x75\x6c\x6c\x73\x2f\x70\x2f\x7b\x7b\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x7d\x7d\x2f\x22\x3e\xe2\x86\x90\x20\x50\x72\x65\x76\x20\x70\x61\x67\x65\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x61\x6e\x64\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x29\x7d\x7d\x20\xc2\xb7\x20\x7b\x7b\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x70\x75\x6c\x6c\x73\x2f\x70\x2f\x7b\x7b\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x7d\x7d\x2f\x22\x3e\x4e\x65\x78\x74\x20\x70\x61\x67\x65\x20\xe2\x86\x92\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x3c\x2f\x70\x3e\x0a\x09\x09\x3c\x2f\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x3c\x2f\x6d\x61\x69\x6e\x3e\x0a\x3c\x2f\x62\x6f\x64\x79\x3e\x0a\x7b\x7b\x2d\x20\x2f\x2a\x20\x2d\x2a\x2d\x20\x6d\x6f\x64\x65\x3a\x20\x68\x74\x6d\x6c\x3b\x2d\x2a\x2d\x20\x2a\x2f\x20\x2d\x7d\x7d\x0a"
+
+func viewsPullsTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_viewsPullsTmpl,
+		"views/pulls.tmpl",
+	)
+}
+
+func viewsPullsTmpl() (*asset, error) {
+	bytes, err := viewsPullsTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "views/pulls.tmpl", size: 1683, mode: os.FileMode(420), modTime: time.Unix(1792214509, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _viewsRefsTmpl = "\x3c\x21\x64\x6f\x63\x74\x79\x70\x65\x20\x68\x74\x6d\x6c\x3e\x0a\x3c\x68\x74\x6d\x6c\x20\x6c\x61\x6e\x67\x3d\x65\x6e\x3e\x0a\x3c\x68\x65\x61\x64\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x63\x68\x61\x72\x73\x65\x74\x3d\x75\x74\x66\x2d\x38\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x6e\x61\x6d\x65\x3d\x76\x69\x65\x77\x70\x6f\x72\x74\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x22\x77\x69\x64\x74\x68\x3d\x64\x65\x76\x69\x63\x65\x2d\x77\x69\x64\x74\x68\x2c\x69\x6e\x69\x74\x69\x61\x6c\x2d\x73\x63\x61\x6c\x65\x3d\x31\x22\x3e\x0a\x09\x3c\x74\x69\x74\x6c\x65\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x20\x7b\x7b\x2e\x4b\x69\x6e\x64\x7d\x7d\x20\xc2\xb7\x20\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x74\x69\x74\x6c\x65\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x73\x74\x79\x6c\x65\x2e\x63\x73\x73\x22\x7d\x7d\x22\x3e\x0a\x3c\x2f\x68\x65\x61\x64\x3e\x0a\x3c\x62\x6f\x64\x79\x3e\x0a\x09\x3c\x68\x65\x61\x64\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x3c\x68\x31\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x2f\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x61\x3e\x3c\x2f\x68\x31\x3e\x0a\x09\x09\x3c\x68\x32\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x3c\x2f\x61\x3e\x2f\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x3c\x2f\x61\x3e\x2f\x7b\x7b\x2e\x4b\x69\x6e\x64\x7d\x7d\x3c\x2f\x68\x32\x3e\x0a\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x3c\x6d\x61\x69\x6e\x3e\x0a\x09\x09\x3c\x75\x6c\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x72\x61\x6e\x67\x65\x20\x2e\x52\x65\x66\x73\x7d\x7d\x0a\x09\x09\x09\x3c\x6c\x69\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x24\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x24\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x74\x2f\x7b\x7b\x2e\x54\x72\x65\x65\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x4e\x61\x6d\x65\x7d\x7d\x3c\x2f\x61\x3e\x3a\x0a\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x24\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x24\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x53\x48\x41\x7d\x7d\x2f\x22\x3e\x3c\x63\x6f\x64\x65\x3e\x7b\x7b\x74\x72\x75\x6e\x63\x61\x74\x65\x20\x2e\x53\x48\x41\x20\x31\x30\x7d\x7d\x3c\x2f\x63\x6f\x64\x65\x3e\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x24\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x24\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x53\x48\x41\x7d\x7d\x2f\x62\x2f\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x42\x75\x69\x6c\x64\x20\x4a\x65\x6b\x79\x6c\x6c\x20\x61\x74\x20\x74\x68\x69\x73\x20\x63\x6f\x6d\x6d\x69\x74\x22\x3e\xe2\x87\x9d\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x6e\x6f\x74\x20\x2e\x44\x61\x74\x65\x2e\x49\x73\x5a\x65\x72\x6f\x7d\x7d\x20\xc2\xb7\x20\x7b\x7b\x61\x67\x6f\x20\x2e\x44\x61\x74\x65\x7d\x7d\x7b\x7b\x65\x6e\x64\x7d\x7d\x3c\x2f\x6c\x69\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x7d\x7d\x0a\x09\x09\x09\x3c\x6c\x69\x3e\x54\x68\x65\x72\x65\x20\x61\x72\x65\x20\x6e\x6f\x20\x7b\x7b\x2e\x4b\x69\x6e\x64\x7d\x7d\x2e\x3c\x2f\x6c\x69\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x3c\x2f\x75\x6c\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x2e\x4d\x6f\x72\x65\x7d\x7d\x0a\x0a\x09\x09\x3c\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x09\x3c\x70\x3e\x4f\x6e\x6c\x79\x20\x74\x68\x65\x20\x66\x69\x72\x73\x74\x20\x7b\x7b\x6c\x65\x6e\x20\x2e\x52\x65\x66\x73\x7d\x7d\x20\x7b\x7b\x2e\x4b\x69\x6e\x64\x7d\x7d\x20\x61\x72\x65\x20\x6c\x69\x73\x74\x65\x64\x2e\x3c\x2f\x70\x3e\x0a\x09\x09\x3c\x2f\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x3c\x2f\x6d\x61\x69\x6e\x3e\x0a\x3c\x2f\x62\x6f\x64\x79\x3e\x0a\x7b\x7b\x2d\x20\x2f\x2a\x20\x2d\x2a\x2d\x20\x6d\x6f\x64\x65\x3a\x20\x68\x74\x6d\x6c\x3b\x2d\x2a\x2d\x20\x2a\x2f\x20\x2d\x7d\x7d\x0a"
+
+func viewsRefsTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_viewsRefsTmpl,
+		"views/refs.tmpl",
+	)
+}
+
+func viewsRefsTmpl() (*asset, error) {
+	bytes, err := viewsRefsTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "views/refs.tmpl", size: 1099, mode: os.FileMode(420), modTime: time.Unix(1792217185, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _viewsRepoTmpl = "\x3c\x21\x64\x6f\x63\x74\x79\x70\x65\x20\x68\x74\x6d\x6c\x3e\x0a\x3c\x68\x74\x6d\x6c\x20\x6c\x61\x6e\x67\x3d\x65\x6e\x3e\x0a\x3c\x68\x65\x61\x64\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x63\x68\x61\x72\x73\x65\x74\x3d\x75\x74\x66\x2d\x38\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x6e\x61\x6d\x65\x3d\x76\x69\x65\x77\x70\x6f\x72\x74\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x22\x77\x69\x64\x74\x68\x3d\x64\x65\x76\x69\x63\x65\x2d\x77\x69\x64\x74\x68\x2c\x69\x6e\x69\x74\x69\x61\x6c\x2d\x73\x63\x61\x6c\x65\x3d\x31\x22\x3e\x0a\x09\x3c\x74\x69\x74\x6c\x65\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x7b\x7b\x69\x66\x20\x2e\x54\x72\x65\x65\x7d\x7d\x2f\x7b\x7b\x2e\x54\x72\x65\x65\x7d\x7d\x7b\x7b\x65\x6e\x64\x7d\x7d\x20\xc2\xb7\x20\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x74\x69\x74\x6c\x65\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x73\x74\x79\x6c\x65\x2e\x63\x73\x73\x22\x7d\x7d\x22\x3e\x0a\x3c\x2f\x68\x65\x61\x64\x3e\x0a\x3c\x62\x6f\x64\x79\x3e\x0a\x09\x3c\x68\x65\x61\x64\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x3c\x68\x31\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x2f\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x61\x3e\x3c\x2f\x68\x31\x3e\x0a\x09\x09\x3c\x68\x32\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x2e\x54\x72\x65\x65\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x2f\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x3c\x2f\x61\x3e\x2f\x7b\x7b\x2e\x54\x72\x65\x65\x7d\x7d\x7b\x7b\x77\x69\x74\x68\x20\x2e\x53\x6f\x75\x72\x63\x65\x2e\x54\x72\x65\x65\x55\x52\x4c\x20\x2e\x55\x73\x65\x72\x20\x2e\x52\x65\x70\x6f\x20\x2e\x54\x72\x65\x65\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x7d\x7d\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x56\x69\x65\x77\x20\x6f\x6e\x20\x7b\x7b\x24\x2e\x53\x6f\x75\x72\x63\x65\x2e\x54\x69\x74\x6c\x65\x7d\x7d\x22\x3e\xe2\xa4\xb4\x3c\x2f\x61\x3e\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x7b\x7b\x69\x66\x20\x2e\x52\x65\x66\x73\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x62\x72\x61\x6e\x63\x68\x65\x73\x2f\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x42\x72\x61\x6e\x63\x68\x65\x73\x22\x3e\xe2\x91\x82\x3c\x2f\x61\x3e\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x74\x61\x67\x73\x2f\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x54\x61\x67\x73\x22\x3e\xe2\x9a\x91\x3c\x2f\x61\x3e\x7b\x7b\x65\x6e\x64\x7d\x7d\x7b\x7b\x69\x66\x20\x2e\x50\x75\x6c\x6c\x73\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x70\x75\x6c\x6c\x73\x2f\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x50\x75\x6c\x6c\x20\x72\x65\x71\x75\x65\x73\x74\x73\x22\x3e\xe2\x87\x84\x3c\x2f\x61\x3e\x7b\x7b\x65\x6e\x64\x7d\x7d\x7b\x7b\x77\x69\x74\x68\x20\x2e\x53\x6f\x75\x72\x63\x65\x2e\x52\x65\x70\x6f\x55\x52\x4c\x20\x2e\x55\x73\x65\x72\x20\x2e\x52\x65\x70\x6f\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x7d\x7d\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x56\x69\x65\x77\x20\x6f\x6e\x20\x7b\x7b\x24\x2e\x53\x6f\x75\x72\x63\x65\x2e\x54\x69\x74\x6c\x65\x7d\x7d\x22\x3e\xe2\xa4\xb4\x3c\x2f\x61\x3e\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x3c\x2f\x68\x32\x3e\x0a\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x3c\x6d\x61\x69\x6e\x3e\x0a\x09\x09\x3c\x75\x6c\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x72\x61\x6e\x67\x65\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x73\x7d\x7d\x0a\x09\x09\x09\x3c\x6c\x69\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x24\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x24\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x53\x48\x41\x7d\x7d\x2f\x22\x3e\x3c\x63\x6f\x64\x65\x3e\x7b\x7b\x74\x72\x75\x6e\x63\x61\x74\x65\x20\x2e\x53\x48\x41\x20\x31\x30\x7d\x7d\x3c\x2f\x63\x6f\x64\x65\x3e\x3c\x2f\x61\x3e\x3a\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x2e\x4d\x65\x73\x73\x61\x67\x65\x7d\x7d\x20\x7b\x7b\x2e\x4d\x65\x73\x73\x61\x67\x65\x7d\x7d\x7b\x7b\x65\x6e\x64\x7d\x7d\x7b\x7b\x77\x69\x74\x68\x20\x2e\x48\x54\x4d\x4c\x55\x52\x4c\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x7d\x7d\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x56\x69\x65\x77\x20\x6f\x6e\x20\x7b\x7b\x24\x2e\x53\x6f\x75\x72\x63\x65\x2e\x54\x69\x74\x6c\x65\x7d\x7d\x22\x3e\xe2\xa4\xb4\x3c\x2f\x61\x3e\x7b\x7b\x65\x6e\x64\x7d\x7d\x3c\x2f\x6c\x69\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x3c\x2f\x75\x6c\x3e\x0a\x0a\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6f\x72\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x29\x7d\x7d\x0a\x0a\x09\x09\x3c\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x09\x3c\x70\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x2e\x54\x72\x65\x65\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x65\x71\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x31\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x74\x2f\x7b\x7b\x2e\x54\x72\x65\x65\x7d\x7d\x2f\x22\x3e\xe2\x86\x90\x20\x50\x72\x65\x76\x20\x70\x61\x67\x65\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x20\x69\x66\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x74\x2f\x7b\x7b\x2e\x54\x72\x65\x65\x7d\x7d\x2f\x70\x2f\x7b\x7b\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x7d\x7d\x2f\x22\x3e\xe2\x86\x90\x20\x50\x72\x65\x76\x20\x70\x61\x67\x65\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x61\x6e\x64\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x29\x7d\x7d\x20\xc2\xb7\x20\x7b\x7b\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x74\x2f\x7b\x7b\x2e\x54\x72\x65\x65\x7d\x7d\x2f\x70\x2f\x7b\x7b\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x7d\x7d\x2f\x22\x3e\x4e\x65\x78\x74\x20\x70\x61\x67\x65\x20\xe2\x86\x92\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x65\x71\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x31\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x22\x3e\xe2\x86\x90\x20\x50\x72\x65\x76\x20\x70\x61\x67\x65\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x20\x69\x66\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x70\x2f\x7b\x7b\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x7d\x7d\x2f\x22\x3e\xe2\x86\x90\x20\x50\x72\x65\x76\x20\x70\x61\x67\x65\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x61\x6e\x64\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x29\x7d\x7d\x20\xc2\xb7\x20\x7b\x7b\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x70\x2f\x7b\x7b\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x7d\x7d\x2f\x22\x3e\x4e\x65\x78\x74\x20\x70\x61\x67\x65\x20\xe2\x86\x92\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x3c\x2f\x70\x3e\x0a\x09\x09\x3c\x2f\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x3c\x2f\x6d\x61\x69\x6e\x3e\x0a\x3c\x2f\x62\x6f\x64\x79\x3e\x0a\x7b\x7b\x2d\x20\x2f\x2a\x20\x2d\x2a\x2d\x20\x6d\x6f\x64\x65\x3a\x20\x68\x74\x6d\x6c\x3b\x2d\x2a\x2d\x20\x2a\x2f\x20\x2d\x7d\x7d\x0a"
 
 func viewsRepoTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -200,12 +264,32 @@ func viewsRepoTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "views/repo.tmpl", size: 2115, mode: os.FileMode(420), modTime: time.Unix(1487842123, 0)}
+	info := bindataFileInfo{name: "views/repo.tmpl", size: 2549, mode: os.FileMode(420), modTime: time.Unix(1792214622, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _viewsStatusTmpl = "\x3c\x21\x64\x6f\x63\x74\x79\x70\x65\x20\x68\x74\x6d\x6c\x3e\x0a\x3c\x68\x74\x6d\x6c\x20\x6c\x61\x6e\x67\x3d\x65\x6e\x3e\x0a\x3c\x68\x65\x61\x64\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x63\x68\x61\x72\x73\x65\x74\x3d\x75\x74\x66\x2d\x38\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x6e\x61\x6d\x65\x3d\x76\x69\x65\x77\x70\x6f\x72\x74\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x22\x77\x69\x64\x74\x68\x3d\x64\x65\x76\x69\x63\x65\x2d\x77\x69\x64\x74\x68\x2c\x69\x6e\x69\x74\x69\x61\x6c\x2d\x73\x63\x61\x6c\x65\x3d\x31\x22\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x68\x74\x74\x70\x2d\x65\x71\x75\x69\x76\x3d\x72\x65\x66\x72\x65\x73\x68\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x32\x3e\x0a\x09\x3c\x74\x69\x74\x6c\x65\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x40\x7b\x7b\x74\x72\x75\x6e\x63\x61\x74\x65\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x20\x31\x30\x7d\x7d\x20\xc2\xb7\x20\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x74\x69\x74\x6c\x65\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x73\x74\x79\x6c\x65\x2e\x63\x73\x73\x22\x7d\x7d\x22\x3e\x0a\x3c\x2f\x68\x65\x61\x64\x3e\x0a\x3c\x62\x6f\x64\x79\x3e\x0a\x09\x3c\x68\x65\x61\x64\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x3c\x68\x31\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x2f\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x61\x3e\x3c\x2f\x68\x31\x3e\x0a\x09\x09\x3c\x68\x32\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x3c\x2f\x61\x3e\x2f\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x3c\x2f\x61\x3e\x40\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x52\x65\x70\x6f\x7d\x7d\x2f\x63\x2f\x7b\x7b\x2e\x43\x6f\x6d\x6d\x69\x74\x7d\x7d\x2f\x22\x3e\x3c\x63\x6f\x64\x65\x3e\x7b\x7b\x74\x72\x75\x6e\x63\x61\x74\x65\x20\x2e\x43\x6f\x6d\x6d\x69\x74\x20\x31\x30\x7d\x7d\x3c\x2f\x63\x6f\x64\x65\x3e\x3c\x2f\x61\x3e\x3c\x2f\x68\x32\x3e\x0a\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x3c\x6d\x61\x69\x6e\x3e\x0a\x09\x09\x3c\x70\x20\x63\x6c\x61\x73\x73\x3d\x22\x62\x75\x69\x6c\x64\x2d\x73\x74\x61\x74\x75\x73\x20\x62\x75\x69\x6c\x64\x2d\x7b\x7b\x2e\x53\x74\x61\x74\x65\x7d\x7d\x22\x3e\x42\x75\x69\x6c\x64\x20\x7b\x7b\x2e\x53\x74\x61\x74\x65\x7d\x7d\xe2\x80\xa6\x3c\x2f\x70\x3e\x0a\x0a\x09\x09\x3c\x70\x3e\x54\x68\x69\x73\x20\x70\x61\x67\x65\x20\x77\x69\x6c\x6c\x20\x72\x65\x66\x72\x65\x73\x68\x20\x61\x75\x74\x6f\x6d\x61\x74\x69\x63\x61\x6c\x6c\x79\x20\x61\x6e\x64\x20\x72\x65\x64\x69\x72\x65\x63\x74\x20\x74\x6f\x20\x74\x68\x65\x20\x62\x75\x69\x6c\x74\x20\x73\x69\x74\x65\x20\x6f\x6e\x63\x65\x20\x74\x68\x65\x20\x62\x75\x69\x6c\x64\x20\x68\x61\x73\x20\x66\x69\x6e\x69\x73\x68\x65\x64\x2e\x3c\x2f\x70\x3e\x0a\x09\x3c\x2f\x6d\x61\x69\x6e\x3e\x0a\x3c\x2f\x62\x6f\x64\x79\x3e\x0a\x7b\x7b\x2d\x20\x2f\x2a\x20\x2d\x2a\x2d\x20\x6d\x6f\x64\x65\x3a\x20\x68\x74\x6d\x6c\x3b\x2d\x2a\x2d\x20\x2a\x2f\x20\x2d\x7d\x7d\x0a"
+
+func viewsStatusTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_viewsStatusTmpl,
+		"views/status.tmpl",
+	)
+}
+
+func viewsStatusTmpl() (*asset, error) {
+	bytes, err := viewsStatusTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "views/status.tmpl", size: 849, mode: os.FileMode(420), modTime: time.Unix(1792211306, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _viewsUserTmpl = "\x3c\x21\x64\x6f\x63\x74\x79\x70\x65\x20\x68\x74\x6d\x6c\x3e\x0a\x3c\x68\x74\x6d\x6c\x20\x6c\x61\x6e\x67\x3d\x65\x6e\x3e\x0a\x3c\x68\x65\x61\x64\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x63\x68\x61\x72\x73\x65\x74\x3d\x75\x74\x66\x2d\x38\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x6e\x61\x6d\x65\x3d\x76\x69\x65\x77\x70\x6f\x72\x74\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x22\x77\x69\x64\x74\x68\x3d\x64\x65\x76\x69\x63\x65\x2d\x77\x69\x64\x74\x68\x2c\x69\x6e\x69\x74\x69\x61\x6c\x2d\x73\x63\x61\x6c\x65\x3d\x31\x22\x3e\x0a\x09\x3c\x74\x69\x74\x6c\x65\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x20\xc2\xb7\x20\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x74\x69\x74\x6c\x65\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x73\x74\x79\x6c\x65\x2e\x63\x73\x73\x22\x7d\x7d\x22\x3e\x0a\x3c\x2f\x68\x65\x61\x64\x3e\x0a\x3c\x62\x6f\x64\x79\x3e\x0a\x09\x3c\x68\x65\x61\x64\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x3c\x68\x31\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x2f\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x61\x3e\x3c\x2f\x68\x31\x3e\x0a\x09\x09\x3c\x68\x32\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x68\x74\x74\x70\x73\x3a\x2f\x2f\x67\x69\x74\x68\x75\x62\x2e\x63\x6f\x6d\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x56\x69\x65\x77\x20\x6f\x6e\x20\x47\x69\x74\x48\x75\x62\x22\x3e\xe2\xa4\xb4\x3c\x2f\x61\x3e\x3c\x2f\x68\x32\x3e\x0a\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x3c\x6d\x61\x69\x6e\x3e\x0a\x09\x09\x3c\x75\x6c\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x72\x61\x6e\x67\x65\x20\x2e\x52\x65\x70\x6f\x73\x7d\x7d\x0a\x09\x09\x09\x3c\x6c\x69\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x24\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x4e\x61\x6d\x65\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x4e\x61\x6d\x65\x7d\x7d\x3c\x2f\x61\x3e\x3a\x20\x7b\x7b\x2e\x44\x65\x73\x63\x72\x69\x70\x74\x69\x6f\x6e\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x48\x54\x4d\x4c\x55\x52\x4c\x7d\x7d\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x56\x69\x65\x77\x20\x6f\x6e\x20\x47\x69\x74\x48\x75\x62\x22\x3e\xe2\xa4\xb4\x3c\x2f\x61\x3e\x3c\x2f\x6c\x69\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x3c\x2f\x75\x6c\x3e\x0a\x0a\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6f\x72\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x29\x7d\x7d\x0a\x0a\x09\x09\x3c\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x09\x3c\x70\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x65\x71\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x31\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x22\x3e\xe2\x86\x90\x20\x50\x72\x65\x76\x20\x70\x61\x67\x65\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x20\x69\x66\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x70\x2f\x7b\x7b\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x7d\x7d\x2f\x22\x3e\xe2\x86\x90\x20\x50\x72\x65\x76\x20\x70\x61\x67\x65\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x61\x6e\x64\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x29\x7d\x7d\x20\xc2\xb7\x20\x7b\x7b\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x70\x2f\x7b\x7b\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x7d\x7d\x2f\x22\x3e\x4e\x65\x78\x74\x20\x70\x61\x67\x65\x20\xe2\x86\x92\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x3c\x2f\x70\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x3c\x2f\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x3c\x2f\x6d\x61\x69\x6e\x3e\x0a\x3c\x2f\x62\x6f\x64\x79\x3e\x0a\x7b\x7b\x2d\x20\x2f\x2a\x20\x2d\x2a\x2d\x20\x6d\x6f\x64\x65\x3a\x20\x68\x74\x6d\x6c\x3b\x2d\x2a\x2d\x20\x2a\x2f\x20\x2d\x7d\x7d\x0a"
+var _viewsUserTmpl = "\x3c\x21\x64\x6f\x63\x74\x79\x70\x65\x20\x68\x74\x6d\x6c\x3e\x0a\x3c\x68\x74\x6d\x6c\x20\x6c\x61\x6e\x67\x3d\x65\x6e\x3e\x0a\x3c\x68\x65\x61\x64\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x63\x68\x61\x72\x73\x65\x74\x3d\x75\x74\x66\x2d\x38\x3e\x0a\x09\x3c\x6d\x65\x74\x61\x20\x6e\x61\x6d\x65\x3d\x76\x69\x65\x77\x70\x6f\x72\x74\x20\x63\x6f\x6e\x74\x65\x6e\x74\x3d\x22\x77\x69\x64\x74\x68\x3d\x64\x65\x76\x69\x63\x65\x2d\x77\x69\x64\x74\x68\x2c\x69\x6e\x69\x74\x69\x61\x6c\x2d\x73\x63\x61\x6c\x65\x3d\x31\x22\x3e\x0a\x09\x3c\x74\x69\x74\x6c\x65\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x20\xc2\xb7\x20\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x74\x69\x74\x6c\x65\x3e\x0a\x09\x3c\x6c\x69\x6e\x6b\x20\x72\x65\x6c\x3d\x73\x74\x79\x6c\x65\x73\x68\x65\x65\x74\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x61\x73\x73\x65\x74\x5f\x70\x61\x74\x68\x20\x22\x73\x74\x79\x6c\x65\x2e\x63\x73\x73\x22\x7d\x7d\x22\x3e\x0a\x3c\x2f\x68\x65\x61\x64\x3e\x0a\x3c\x62\x6f\x64\x79\x3e\x0a\x09\x3c\x68\x65\x61\x64\x65\x72\x20\x63\x6c\x61\x73\x73\x3d\x73\x69\x74\x65\x2d\x68\x65\x61\x64\x65\x72\x3e\x0a\x09\x09\x3c\x68\x31\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x2f\x3e\x6a\x65\x6b\x79\x6c\x6c\x2d\x68\x69\x73\x74\x6f\x72\x79\x3c\x2f\x61\x3e\x3c\x2f\x68\x31\x3e\x0a\x09\x09\x3c\x68\x32\x3e\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x7b\x7b\x77\x69\x74\x68\x20\x2e\x53\x6f\x75\x72\x63\x65\x2e\x55\x73\x65\x72\x55\x52\x4c\x20\x2e\x55\x73\x65\x72\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x7d\x7d\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x56\x69\x65\x77\x20\x6f\x6e\x20\x7b\x7b\x24\x2e\x53\x6f\x75\x72\x63\x65\x2e\x54\x69\x74\x6c\x65\x7d\x7d\x22\x3e\xe2\xa4\xb4\x3c\x2f\x61\x3e\x7b\x7b\x65\x6e\x64\x7d\x7d\x3c\x2f\x68\x32\x3e\x0a\x09\x3c\x2f\x68\x65\x61\x64\x65\x72\x3e\x0a\x0a\x09\x3c\x6d\x61\x69\x6e\x3e\x0a\x09\x09\x3c\x75\x6c\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x72\x61\x6e\x67\x65\x20\x2e\x52\x65\x70\x6f\x73\x7d\x7d\x0a\x09\x09\x09\x3c\x6c\x69\x3e\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x24\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x72\x2f\x7b\x7b\x2e\x4e\x61\x6d\x65\x7d\x7d\x2f\x22\x3e\x7b\x7b\x2e\x4e\x61\x6d\x65\x7d\x7d\x3c\x2f\x61\x3e\x3a\x20\x7b\x7b\x2e\x44\x65\x73\x63\x72\x69\x70\x74\x69\x6f\x6e\x7d\x7d\x7b\x7b\x77\x69\x74\x68\x20\x2e\x48\x54\x4d\x4c\x55\x52\x4c\x7d\x7d\x20\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x7d\x7d\x22\x20\x74\x69\x74\x6c\x65\x3d\x22\x56\x69\x65\x77\x20\x6f\x6e\x20\x7b\x7b\x24\x2e\x53\x6f\x75\x72\x63\x65\x2e\x54\x69\x74\x6c\x65\x7d\x7d\x22\x3e\xe2\xa4\xb4\x3c\x2f\x61\x3e\x7b\x7b\x65\x6e\x64\x7d\x7d\x3c\x2f\x6c\x69\x3e\x0a\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x3c\x2f\x75\x6c\x3e\x0a\x0a\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6f\x72\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x29\x7d\x7d\x0a\x0a\x09\x09\x3c\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x09\x09\x3c\x70\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x65\x71\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x31\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x22\x3e\xe2\x86\x90\x20\x50\x72\x65\x76\x20\x70\x61\x67\x65\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6c\x73\x65\x20\x69\x66\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x70\x2f\x7b\x7b\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x7d\x7d\x2f\x22\x3e\xe2\x86\x90\x20\x50\x72\x65\x76\x20\x70\x61\x67\x65\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x61\x6e\x64\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x50\x72\x65\x76\x50\x61\x67\x65\x20\x30\x29\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x29\x7d\x7d\x20\xc2\xb7\x20\x7b\x7b\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x69\x66\x20\x28\x6e\x65\x20\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x20\x30\x29\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x09\x09\x3c\x61\x20\x68\x72\x65\x66\x3d\x22\x7b\x7b\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x2f\x75\x2f\x7b\x7b\x2e\x55\x73\x65\x72\x7d\x7d\x2f\x70\x2f\x7b\x7b\x2e\x52\x65\x73\x70\x2e\x4e\x65\x78\x74\x50\x61\x67\x65\x7d\x7d\x2f\x22\x3e\x4e\x65\x78\x74\x20\x70\x61\x67\x65\x20\xe2\x86\x92\x3c\x2f\x61\x3e\x0a\x09\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x20\x2d\x7d\x7d\x0a\x09\x09\x09\x3c\x2f\x70\x3e\x0a\x09\x09\x09\x7b\x7b\x2d\x20\x65\x6e\x64\x7d\x7d\x0a\x09\x09\x3c\x2f\x66\x6f\x6f\x74\x65\x72\x3e\x0a\x09\x3c\x2f\x6d\x61\x69\x6e\x3e\x0a\x3c\x2f\x62\x6f\x64\x79\x3e\x0a\x7b\x7b\x2d\x20\x2f\x2a\x20\x2d\x2a\x2d\x20\x6d\x6f\x64\x65\x3a\x20\x68\x74\x6d\x6c\x3b\x2d\x2a\x2d\x20\x2a\x2f\x20\x2d\x7d\x7d\x0a"
 
 func viewsUserTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -220,7 +304,7 @@ func viewsUserTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "views/user.tmpl", size: 1175, mode: os.FileMode(420), modTime: time.Unix(1487842123, 0)}
+	info := bindataFileInfo{name: "views/user.tmpl", size: 1275, mode: os.FileMode(420), modTime: time.Unix(1792211461, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -283,7 +367,11 @@ var _bindata = map[string]func() (*asset, error){
 	"views/commit.tmpl": viewsCommitTmpl,
 	"views/error.tmpl":  viewsErrorTmpl,
 	"views/index.tmpl":  viewsIndexTmpl,
+	"views/pull.tmpl":   viewsPullTmpl,
+	"views/pulls.tmpl":  viewsPullsTmpl,
+	"views/refs.tmpl":   viewsRefsTmpl,
 	"views/repo.tmpl":   viewsRepoTmpl,
+	"views/status.tmpl": viewsStatusTmpl,
 	"views/user.tmpl":   viewsUserTmpl,
 }
 
@@ -337,7 +425,11 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"commit.tmpl": &bintree{viewsCommitTmpl, map[string]*bintree{}},
 		"error.tmpl":  &bintree{viewsErrorTmpl, map[string]*bintree{}},
 		"index.tmpl":  &bintree{viewsIndexTmpl, map[string]*bintree{}},
+		"pull.tmpl":   &bintree{viewsPullTmpl, map[string]*bintree{}},
+		"pulls.tmpl":  &bintree{viewsPullsTmpl, map[string]*bintree{}},
+		"refs.tmpl":   &bintree{viewsRefsTmpl, map[string]*bintree{}},
 		"repo.tmpl":   &bintree{viewsRepoTmpl, map[string]*bintree{}},
+		"status.tmpl": &bintree{viewsStatusTmpl, map[string]*bintree{}},
 		"user.tmpl":   &bintree{viewsUserTmpl, map[string]*bintree{}},
 	}},
 }}
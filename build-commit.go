@@ -10,49 +10,106 @@ import (
 	"encoding/hex"
 	"log"
 	"net/http"
+	"net/url"
+	"time"
 
-	"github.com/golang/groupcache"
 	"github.com/julienschmidt/httprouter"
 )
 
-func getBuildCommitHandler(buildJekyll *groupcache.Group) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+// buildWait is how long the build handler waits for a
+// build to finish before redirecting to the status page.
+const buildWait = 2 * time.Second
+
+// buildKey returns the tag for a commit and the key to
+// request from buildJekyll.
+func buildKey(source, user, repo, commit string) (tag, key string) {
+	data := user + "\x00" + repo + "\x00" + commit
+
+	// GitHub builds keep the tags they had before other
+	// source providers were supported
+	if source != defaultSourceName {
+		data = source + "\x00" + data
+	}
+
+	rawTag := sha256.Sum256([]byte(data))
+	tag = hex.EncodeToString(rawTag[:16])
+
+	return tag, tag + "\x00" + data
+}
+
+// isBuilt reports whether the site for tag has already been
+// built, so that it need not be queued again. The manifest
+// is written only after every file of the site, so a build
+// that is still being stored is not yet built.
+func isBuilt(store storage, tag string) bool {
+	manifest, err := loadBuildManifest(store, tagPrefix(tag))
+	if err != nil {
+		log.Printf("%[1]T: %[1]v", err)
+		return false
+	}
+
+	return manifest != nil
+}
+
+func getBuildCommitHandler(sources sourceRegistry, queue *buildQueue, store storage, hosts *hostConfig) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		w.Header().Set("Cache-Control", "max-age=0")
 
-		user, repo, commit := ps.ByName("user"), ps.ByName("repo"), ps.ByName("commit")
+		source, ok := sources.fromParams(ps)
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		user, repo, ref := ps.ByName("user"), ps.ByName("repo"), ps.ByName("commit")
 
-		data := user + "\x00" + repo + "\x00" + commit
+		commit, code, err := resolveCommit(source, user, repo, ref)
+		if err != nil {
+			if code != http.StatusNotFound {
+				log.Printf("%[1]T %[1]v", err)
+			}
 
-		rawTag := sha256.Sum256([]byte(data))
-		tag := hex.EncodeToString(rawTag[:16])
+			http.Error(w, http.StatusText(code), code)
+			return
+		}
 
-		var resp BuildJekyllResponse
+		if commit != ref {
+			newURL := *r.URL
+			newURL.Path = commitPath(source.Name(), user, repo, commit) + "b" + ps.ByName("path")
 
-		if err := buildJekyll.Get(nil, tag+"\x00"+data, groupcache.ProtoSink(&resp)); err != nil {
-			log.Printf("%[1]T %[1]v", err)
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			http.Redirect(w, r, newURL.String(), http.StatusFound)
 			return
 		}
 
-		if len(resp.Error) != 0 {
-			switch resp.Code {
-			case http.StatusNotFound:
-				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
-			case 0:
-				log.Println(resp.Error)
-				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-			default:
-				log.Println(resp.Error)
-				http.Error(w, http.StatusText(int(resp.Code)), int(resp.Code))
-			}
+		if tag, _ := buildKey(source.Name(), user, repo, commit); isBuilt(store, tag) {
+			http.Redirect(w, r, hosts.siteURL(r, tag, ps.ByName("path"), r.URL.RawQuery), http.StatusFound)
+			return
+		}
 
+		job, err := queue.Enqueue(source.Name(), user, repo, commit)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
 			return
 		}
 
-		url := *r.URL
-		url.Host = tag + "." + r.Host
-		url.Path = ps.ByName("path")
+		if job.Wait(buildWait) {
+			if state, _ := job.State(); state == buildSucceeded {
+				http.Redirect(w, r, hosts.siteURL(r, job.Tag, ps.ByName("path"), r.URL.RawQuery), http.StatusFound)
+				return
+			}
+		}
+
+		statusURL := url.URL{
+			Path: commitPath(source.Name(), user, repo, commit) + "status",
+		}
+
+		if path := ps.ByName("path"); len(path) != 0 {
+			statusURL.RawQuery = url.Values{
+				"path": {path},
+			}.Encode()
+		}
 
-		http.Redirect(w, r, url.String(), http.StatusFound)
+		http.Redirect(w, r, statusURL.String(), http.StatusFound)
 	}
 }
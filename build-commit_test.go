@@ -0,0 +1,111 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestBuildCommitExisting(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err := ioutil.TempDir("", "build-commit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	commit := newTestGitRepo(t, root, "user", "site", map[string]string{
+		"index.md": "# Hello",
+	})
+
+	ls, err := newLocalSource("local", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sources := sourceRegistry{"local": ls}
+	hosts := &hostConfig{Primary: "jekyllhistory.org"}
+	store := newMemoryStorage()
+
+	// the queue is always full, so only builds that need
+	// not be queued succeed
+	queue := &buildQueue{
+		queue: make(chan *buildJob),
+		jobs:  make(map[string]*buildJob),
+	}
+
+	buildCommit := getBuildCommitHandler(sources, queue, store, hosts)
+	buildStatus := getBuildStatusHandler(sources, queue, store, hosts)
+
+	ps := httprouter.Params{
+		{Key: "provider", Value: "local"},
+		{Key: "user", Value: "user"},
+		{Key: "repo", Value: "site"},
+		{Key: "commit", Value: commit},
+	}
+
+	serve := func(handle httprouter.Handle, target string) *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		handle(rw, httptest.NewRequest(http.MethodGet, target, nil), ps)
+		return rw
+	}
+
+	commitURL := "http://jekyllhistory.org" + commitPath("local", "user", "site", commit)
+
+	if rw := serve(buildCommit, commitURL+"b"); rw.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET b before the build returned %d, expected %d", rw.Code, http.StatusServiceUnavailable)
+	}
+
+	tag, _ := buildKey("local", "user", "site", commit)
+
+	if err := store.Put(tagPrefix(tag)+"/index.html", bytes.NewReader([]byte("index")), 5, http.Header{
+		"Content-Type": {"text/html; charset=utf-8"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the site is not built until its manifest is stored
+	if rw := serve(buildCommit, commitURL+"b"); rw.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET b while the site was stored returned %d, expected %d", rw.Code, http.StatusServiceUnavailable)
+	}
+
+	if err := saveBuildManifest(store, tagPrefix(tag), newBuildManifest("local", "user", "site", &BuildJekyllResponse{
+		Commit: commit,
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		handle httprouter.Handle
+		target string
+	}{
+		{buildCommit, commitURL + "b"},
+		{buildStatus, commitURL + "status"},
+	} {
+		rw := serve(test.handle, test.target)
+
+		if location := rw.HeaderMap.Get("Location"); rw.Code != http.StatusFound || !strings.HasPrefix(location, "http://"+tag+".jekyllhistory.org") {
+			t.Errorf("GET %s after the build returned %d to %q, expected a redirect to the site", test.target, rw.Code, location)
+		}
+	}
+
+	if len(queue.jobs) != 0 {
+		t.Errorf("%d builds were queued, expected none", len(queue.jobs))
+	}
+}
@@ -6,198 +6,507 @@
 package main
 
 import (
-	"archive/tar"
 	"bytes"
 	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"mime"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/golang/groupcache"
-	"github.com/google/go-github/github"
-	"github.com/mitchellh/goamz/s3"
+	"github.com/golang/protobuf/proto"
 )
 
 const sniffLen = 512
 
+// maxBuildLogSize is the maximum number of bytes of jekyll
+// output that are stored for each build.
+const maxBuildLogSize = 1 << 20
+
+// buildLogReserve is the part of maxBuildLogSize that is
+// kept back from the build for the line saying why it
+// failed.
+const buildLogReserve = 4 << 10
+
+const (
+	defaultFailureTTL = 24 * time.Hour
+
+	minRetryBackoff = 30 * time.Second
+	maxRetryBackoff = time.Hour
+
+	// maxTimedOutAttempts is how many times a build that
+	// timed out is retried before it is treated as failing
+	// permanently, as it may have been slowed by load.
+	maxTimedOutAttempts = 3
+)
+
+// buildFailedError is returned from buildJekyllGetter.Get
+// for failed builds. groupcache does not cache errors, so
+// a later request will retry the build once RetryAfter has
+// passed; until then the stored failure is returned.
+type buildFailedError struct {
+	Response BuildJekyllResponse
+}
+
+func (e *buildFailedError) Error() string {
+	return e.Response.Error
+}
+
+func retryBackoff(attempts int32) time.Duration {
+	backoff := minRetryBackoff
+
+	for i := int32(1); i < attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+
+	return backoff
+}
+
 type buildJekyllGetter struct {
 	WorkingDirectory string
 
-	ExecuteJekyll func(src, dst string) error
+	// Executor runs jekyll, it defaults to running jekyll
+	// from the PATH.
+	Executor jekyllExecutor
 
-	S3Bucket *s3.Bucket
+	// Timeout, if non-zero, is the longest jekyll may run
+	// for each build.
+	Timeout time.Duration
 
-	GithubClient *github.Client
-	HTTPClient   *http.Client
+	// FetchTimeout, if non-zero, is the longest downloading
+	// or cloning a repository may take.
+	FetchTimeout time.Duration
+
+	// ExtractLimits bounds the size of each downloaded
+	// archive or checked out repository.
+	ExtractLimits extractLimits
+
+	Storage storage
+
+	// FailureTTL is how long a build that failed permanently
+	// is remembered before it may be retried.
+	FailureTTL time.Duration
+
+	Sources sourceRegistry
+
+	// Hosts, if set, decides the baseurl sites are built
+	// with. See hostConfig.baseURL.
+	Hosts *hostConfig
+
+	// Mirror, if set, is used to clone repositories from
+	// sources that support it instead of downloading an
+	// archive.
+	Mirror *gitMirror
 }
 
 func (bj buildJekyllGetter) Get(_ groupcache.Context, key string, dest groupcache.Sink) error {
 	var resp BuildJekyllResponse
 
 	parts := strings.Split(key, "\x00")
-	if len(parts) != 4 {
+
+	// GitHub keys do not name the source, see buildKey
+	if len(parts) == 4 {
+		parts = append([]string{parts[0], defaultSourceName}, parts[1:]...)
+	}
+
+	if len(parts) != 5 {
 		resp.Error = "invalid key"
 		resp.Code = http.StatusBadRequest
 		return dest.SetProto(&resp)
 	}
 
-	tag, user, repo, commit := parts[0], parts[1], parts[2], parts[3]
-
-	tagPath := filepath.Join(tag[0:1], tag[1:2], tag[2:])
+	tag, user, repo, commit := parts[0], parts[2], parts[3], parts[4]
 
-	basePath := filepath.Join(bj.WorkingDirectory, tagPath)
+	tagPath := tagPrefix(tag)
 
-	if !debug {
-		defer os.RemoveAll(basePath)
+	// the manifest is only written once the whole site has
+	// been stored, see isBuilt
+	if manifest, err := loadBuildManifest(bj.Storage, tagPath); err != nil {
+		log.Printf("%[1]T: %[1]v", err)
+	} else if manifest != nil {
+		manifest.Response(&resp)
+		return dest.SetProto(&resp)
 	}
 
-	repoPath := filepath.Join(basePath, "repo")
-	sitePath := filepath.Join(basePath, "site")
+	now := time.Now()
 
-	if list, err := bj.S3Bucket.List(tagPath, "/", "", 1); err == nil && len(list.CommonPrefixes) != 0 {
-		return dest.SetProto(&resp)
-	} else if err != nil {
+	failure, err := loadBuildFailure(bj.Storage, tagPath)
+	if err != nil {
 		log.Printf("%[1]T: %[1]v", err)
+	} else if failure != nil && now.Unix() < failure.RetryAfter {
+		return &buildFailedError{*failure}
 	}
 
-	u, gresp, err := bj.GithubClient.Repositories.GetArchiveLink(context.Background(), user, repo, github.Tarball, &github.RepositoryContentGetOptions{
-		Ref: commit,
-	})
+	source, ok := bj.Sources[parts[1]]
+	if !ok {
+		resp.Error = fmt.Sprintf("unknown source '%s'", parts[1])
+		resp.Code = http.StatusBadRequest
+		return dest.SetProto(&resp)
+	}
+
+	buildLog := bufferPool.Get().(*bytes.Buffer)
+	defer bufferPool.Put(buildLog)
+	buildLog.Reset()
+
+	logw := &limitWriter{
+		W: buildLog,
+		N: maxBuildLogSize - buildLogReserve,
+	}
+
+	resp, err = bj.build(source, tag, user, repo, commit, logw)
 	if err != nil {
 		resp.Error = fmt.Sprintf("%[1]T: %[1]v", err)
+		resp.Code = http.StatusInternalServerError
+	}
+
+	if len(resp.Error) == 0 {
+		resp.Commit = commit
+		resp.Started = now.Unix()
+		resp.DurationMs = int64(time.Since(now) / time.Millisecond)
+		resp.JekyllVersion, resp.Warnings = parseBuildLog(buildLog.Bytes())
 
-		if gerr, ok := err.(*github.ErrorResponse); ok && gerr.Response.StatusCode == http.StatusNotFound {
-			resp.Code = http.StatusNotFound
+		if len(resp.JekyllVersion) == 0 {
+			resp.JekyllVersion = bj.executor().Capabilities().Version
+		}
+
+		if err := saveBuildManifest(bj.Storage, tagPath, newBuildManifest(parts[1], user, repo, &resp)); err != nil {
+			log.Printf("%[1]T: %[1]v", err)
+		}
+	}
+
+	if len(resp.Error) != 0 {
+		logw.N += buildLogReserve
+		fmt.Fprintf(logw, "\nbuild failed: %s\n", resp.Error)
+
+		if failure != nil {
+			resp.Attempts = failure.Attempts
+		}
+
+		resp.Attempts++
+
+		// client errors, such as a missing commit or a
+		// site that jekyll fails to build, are permanent
+		resp.Transient = resp.Code == 0 || resp.Code >= http.StatusInternalServerError ||
+			resp.TimedOut && resp.Attempts < maxTimedOutAttempts
+
+		if resp.Transient {
+			resp.RetryAfter = now.Add(retryBackoff(resp.Attempts)).Unix()
 		} else {
-			resp.Code = http.StatusBadGateway
+			failureTTL := bj.FailureTTL
+			if failureTTL == 0 {
+				failureTTL = defaultFailureTTL
+			}
+
+			resp.RetryAfter = now.Add(failureTTL).Unix()
 		}
 
-		return dest.SetProto(&resp)
+		if err := bj.saveFailure(tagPath, &resp); err != nil {
+			log.Printf("%[1]T: %[1]v", err)
+		}
+	} else if failure != nil {
+		if err := bj.Storage.Delete(tagPath + ".failure"); err != nil {
+			log.Printf("%[1]T: %[1]v", err)
+		}
 	}
 
-	if verbose {
-		log.Printf("GitHub API Rate Limit is %d remaining of %d, to be reset at %s\n", gresp.Remaining, gresp.Limit, gresp.Reset)
+	if err := bj.Storage.Put(tagPath+".log", buildLog, int64(buildLog.Len()), http.Header{
+		"Content-Type": {"text/plain; charset=utf-8"},
+	}); err != nil {
+		log.Printf("%[1]T: %[1]v", err)
 	}
 
-	if u == nil {
-		resp.Error = "not found"
-		resp.Code = http.StatusNotFound
-		return dest.SetProto(&resp)
+	if len(resp.Error) != 0 {
+		return &buildFailedError{resp}
+	}
+
+	return dest.SetProto(&resp)
+}
+
+func (bj buildJekyllGetter) executor() jekyllExecutor {
+	if bj.Executor != nil {
+		return bj.Executor
 	}
 
-	client := bj.HTTPClient
-	if client == nil {
-		client = http.DefaultClient
+	return defaultJekyllExecutor
+}
+
+// loadBuildFailure returns the failure record for a
+// previous build or nil if there is none.
+func loadBuildFailure(store storage, tagPath string) (*BuildJekyllResponse, error) {
+	obj, err := store.Get(tagPath + ".failure")
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
 	}
 
-	hresp, err := client.Do(&http.Request{
-		URL:  u,
-		Host: u.Host,
-		Header: http.Header{
-			"User-Agent": []string{fullVersionStr},
-		},
-	})
+	defer obj.Body.Close()
+
+	data, err := ioutil.ReadAll(obj.Body)
 	if err != nil {
-		resp.Error = fmt.Sprintf("%[1]T: %[1]v", err)
-		resp.Code = http.StatusBadGateway
-		return dest.SetProto(&resp)
+		return nil, err
 	}
 
-	if hresp.Body == nil {
-		resp.Error = "(*http.Client).Do did not return body"
-		return dest.SetProto(&resp)
+	var resp BuildJekyllResponse
+	if err = proto.Unmarshal(data, &resp); err != nil {
+		return nil, err
 	}
 
-	defer hresp.Body.Close()
+	return &resp, nil
+}
 
-	reader, err := gzip.NewReader(hresp.Body)
+func (bj buildJekyllGetter) saveFailure(tagPath string, resp *BuildJekyllResponse) error {
+	data, err := proto.Marshal(resp)
 	if err != nil {
 		return err
 	}
 
-	defer reader.Close()
+	return bj.Storage.Put(tagPath+".failure", bytes.NewReader(data), int64(len(data)), http.Header{
+		"Content-Type": {"application/x-protobuf"},
+	})
+}
+
+func (bj buildJekyllGetter) build(source sourceProvider, tag, user, repo, commit string, buildLog io.Writer) (resp BuildJekyllResponse, err error) {
+	tagPath := tagPrefix(tag)
+
+	basePath := filepath.Join(bj.WorkingDirectory, filepath.FromSlash(tagPath))
+
+	if !debug {
+		defer os.RemoveAll(basePath)
+	}
+
+	repoPath := filepath.Join(basePath, "repo")
+	sitePath := filepath.Join(basePath, "site")
+	encodedPath := filepath.Join(basePath, "encoded")
+
+	if resp, err = bj.fetch(source, user, repo, commit, repoPath, buildLog); err != nil || len(resp.Error) != 0 {
+		return resp, err
+	}
+
+	ctx := context.Background()
+
+	if bj.Timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, bj.Timeout)
+		defer cancel()
+	}
+
+	baseURL := bj.Hosts.baseURL(tag)
+
+	if err := bj.executor().Build(ctx, repoPath, sitePath, baseURL, buildLog); ctx.Err() == context.DeadlineExceeded {
+		// a build that runs too long will likely do so
+		// again, see maxTimedOutAttempts
+		resp.Error = fmt.Sprintf("jekyll build timed out after %s", bj.Timeout)
+		resp.Code = http.StatusUnprocessableEntity
+		resp.TimedOut = true
+		return resp, nil
+	} else if err != nil {
+		resp.Error = fmt.Sprintf("%[1]T: %[1]v", err)
 
-	tarReader := tar.NewReader(reader)
+		if isJekyllExitError(err) {
+			resp.Code = http.StatusUnprocessableEntity
+		}
+
+		return resp, nil
+	}
 
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			resp.Error = fmt.Sprintf("%[1]T: %[1]v", err)
-			return dest.SetProto(&resp)
+	var uploaded []string
+
+	files, err := collectSiteFiles(sitePath, buildLog)
+	if err != nil {
+		resp.Error = fmt.Sprintf("%[1]T: %[1]v", err)
+		return resp, nil
+	}
+
+	rules := siteRules{
+		BaseURL: baseURL,
+	}
+
+	if err := parseRulesFile(filepath.Join(sitePath, "_headers"), rules.parseHeadersFile, buildLog); err != nil {
+		return resp, err
+	}
+
+	if err := parseRulesFile(filepath.Join(sitePath, "_redirects"), rules.parseRedirectsFile, buildLog); err != nil {
+		return resp, err
+	}
+
+	// files are compressed first so the index can record
+	// the encodings each is stored in
+	for i := range files {
+		if err := files[i].encode(encodedPath); err != nil {
+			return resp, err
 		}
+	}
 
-		idx := strings.IndexRune(header.Name, filepath.Separator)
-		if idx == -1 {
-			continue
+	// the rules and index are stored first so the site
+	// is never served without them
+	err = func() error {
+		if !rules.empty() {
+			if err := saveSiteRules(bj.Storage, tagPath, &rules); err != nil {
+				return err
+			}
+
+			uploaded = append(uploaded, tagPath+".rules")
+		}
+
+		paths := make([]string, len(files))
+		for i, file := range files {
+			paths[i] = sitePathsLine(file.relPath, file.encodings)
 		}
 
-		path := filepath.Join(repoPath, header.Name[idx+1:])
+		if err := saveSitePaths(bj.Storage, tagPath, paths); err != nil {
+			return err
+		}
 
-		info := header.FileInfo()
-		mode := info.Mode()
+		uploaded = append(uploaded, tagPath+".paths")
 
-		if info.IsDir() {
-			if err = os.MkdirAll(path, mode); err != nil {
-				resp.Error = fmt.Sprintf("%[1]T: %[1]v", err)
-				return dest.SetProto(&resp)
+		for _, file := range files {
+			names, size, err := bj.upload(tagPath, file, encodedPath)
+			uploaded = append(uploaded, names...)
+
+			if err != nil {
+				return err
 			}
 
-			continue
+			resp.Files++
+			resp.Bytes += file.info.Size()
+			resp.CompressedBytes += size
 		}
 
-		if mode&(os.ModeSymlink|os.ModeNamedPipe|os.ModeSocket|os.ModeDevice) != 0 {
-			log.Printf("tar file '%s' has invalid mode: %d", header.Name, mode)
-			continue
+		return nil
+	}()
+	if err != nil {
+		resp.Error = fmt.Sprintf("%[1]T: %[1]v", err)
+
+		// a partial upload would otherwise be taken as a
+		// successful build by the next request
+		for _, name := range uploaded {
+			if err := bj.Storage.Delete(name); err != nil {
+				log.Printf("%[1]T: %[1]v", err)
+			}
 		}
+	}
 
-		file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
-		if err != nil {
-			resp.Error = fmt.Sprintf("%[1]T: %[1]v", err)
-			return dest.SetProto(&resp)
+	return resp, nil
+}
+
+// siteFile is a file of the build output.
+type siteFile struct {
+	path    string
+	relPath string
+	info    os.FileInfo
+
+	// encodings are those the file is stored in, if it is
+	// compressed. The first is of the file itself and the
+	// rest are variants, see variantPath.
+	encodings []string
+}
+
+// encodedPath returns where the file is written to within
+// dir once compressed with encoding.
+func (file *siteFile) encodedPath(dir, encoding string) string {
+	return filepath.Join(dir, encoding, filepath.FromSlash(file.relPath))
+}
+
+// encode compresses the file into dir with each of the
+// precompressors. Files larger than 1KiB are stored gzipped
+// if that is smaller, along with any smaller brotli and
+// zstd variants.
+func (file *siteFile) encode(dir string) error {
+	size := file.info.Size()
+	if size <= 1024 {
+		return nil
+	}
+
+	f, err := os.Open(file.path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	sizes := make(map[string]int64, len(precompressors))
+
+	for _, pc := range precompressors {
+		if _, err := f.Seek(0, os.SEEK_SET); err != nil {
+			return err
 		}
 
-		_, err = copyBuffer(file, tarReader)
-		file.Close()
+		name := file.encodedPath(dir, pc.name)
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			return err
+		}
 
+		out, err := os.Create(name)
 		if err != nil {
-			resp.Error = fmt.Sprintf("%[1]T: %[1]v", err)
-			return dest.SetProto(&resp)
+			return err
 		}
 
-		if !header.ModTime.IsZero() && !header.ModTime.Equal(unixEpochTime) {
-			access := header.AccessTime
+		w, err := pc.newWriter(out)
+		if err == nil {
+			_, err = copyBuffer(w, f)
 
-			if access.IsZero() || access.Equal(unixEpochTime) {
-				access = time.Now()
+			if cerr := w.Close(); err == nil {
+				err = cerr
 			}
+		}
 
-			if err := os.Chtimes(path, access, header.ModTime); err != nil {
-				log.Printf("%[1]T: %[1]v", err)
-			}
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+
+		if err != nil {
+			return err
+		}
+
+		info, err := os.Stat(name)
+		if err != nil {
+			return err
 		}
+
+		sizes[pc.name] = info.Size()
 	}
 
-	executeJekyll := bj.ExecuteJekyll
-	if executeJekyll == nil {
-		executeJekyll = defaultExecuteJekyll
+	gzLen := sizes["gzip"]
+	if gzLen >= size {
+		return nil
 	}
 
-	if err := executeJekyll(repoPath, sitePath); err != nil {
-		resp.Error = fmt.Sprintf("%[1]T: %[1]v", err)
-		return dest.SetProto(&resp)
+	file.encodings = []string{"gzip"}
+
+	for _, pc := range precompressors[:len(precompressors)-1] {
+		if sizes[pc.name] < gzLen {
+			file.encodings = append(file.encodings, pc.name)
+		}
 	}
 
-	if err := filepath.Walk(sitePath, func(filePath string, info os.FileInfo, err error) error {
+	return nil
+}
+
+// collectSiteFiles returns the files of the build output
+// at sitePath that are to be served, in lexical order.
+func collectSiteFiles(sitePath string, buildLog io.Writer) ([]siteFile, error) {
+	siteRoot, err := filepath.EvalSymlinks(sitePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []siteFile
+
+	err = filepath.Walk(sitePath, func(filePath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -210,69 +519,220 @@ func (bj buildJekyllGetter) Get(_ groupcache.Context, key string, dest groupcach
 			return nil
 		}
 
+		relPath := filepath.ToSlash(filePath[len(sitePath):])
+
+		// the index is line based
+		if strings.ContainsAny(relPath, "\t\n\r") {
+			fmt.Fprintf(buildLog, "skipping %q: name contains a tab or newline\n", relPath)
+			return nil
+		}
+
+		// rule files configure the site, they are not served
+		if relPath == "/_headers" || relPath == "/_redirects" {
+			return nil
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, ok := resolveInTree(siteRoot, filePath)
+			if !ok {
+				fmt.Fprintf(buildLog, "skipping %s: symlink does not resolve inside of the site\n", relPath)
+				return nil
+			}
+
+			if info, err = os.Stat(target); err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				fmt.Fprintf(buildLog, "skipping %s: symlink to a directory\n", relPath)
+				return nil
+			}
+		}
+
 		if info.Mode()&(os.ModeDir|os.ModeSymlink|os.ModeNamedPipe|os.ModeSocket|os.ModeDevice) != 0 {
 			return &os.PathError{Op: "open", Path: filePath, Err: errors.New("not a regular file")}
 		}
 
-		f, err := os.Open(filePath)
-		if err != nil {
+		files = append(files, siteFile{path: filePath, relPath: relPath, info: info})
+		return nil
+	})
+	return files, err
+}
+
+// upload stores file, and any smaller encoded variants of
+// it, as part of the site at tagPath. It returns the names
+// it stored, even if it fails, and the stored size.
+func (bj buildJekyllGetter) upload(tagPath string, file siteFile, encodedPath string) (names []string, size int64, err error) {
+	f, err := os.Open(file.path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer f.Close()
+
+	ctype := mime.TypeByExtension(filepath.Ext(file.path))
+	if len(ctype) == 0 {
+		// read a chunk to decide between utf-8 text and binary
+		var buf [sniffLen]byte
+		n, _ := io.ReadFull(f, buf[:])
+
+		ctype = http.DetectContentType(buf[:n])
+
+		if _, err := f.Seek(0, os.SEEK_SET); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	put := func(name string, f *os.File, size int64, header http.Header) error {
+		header.Set("Cache-Control", builtRepoCacheControl)
+		header.Set("Content-Type", ctype)
+
+		if err := bj.Storage.Put(name, f, size, header); err != nil {
 			return err
 		}
 
-		defer f.Close()
+		names = append(names, name)
+		return nil
+	}
+
+	name := path.Join(tagPath, file.relPath)
 
-		ctype := mime.TypeByExtension(filepath.Ext(filePath))
-		if len(ctype) == 0 {
-			// read a chunk to decide between utf-8 text and binary
-			var buf [sniffLen]byte
-			n, _ := io.ReadFull(f, buf[:])
+	if len(file.encodings) == 0 {
+		size = file.info.Size()
+		return names, size, put(name, f, size, make(http.Header))
+	}
 
-			ctype = http.DetectContentType(buf[:n])
+	// variants are stored before the file that lists them
+	variants := file.encodings[1:]
 
-			if _, err := f.Seek(0, os.SEEK_SET); err != nil {
-				return err
+	for i := range file.encodings {
+		encoding := file.encodings[(i+1)%len(file.encodings)]
+
+		ef, err := os.Open(file.encodedPath(encodedPath, encoding))
+		if err != nil {
+			return names, 0, err
+		}
+
+		info, err := ef.Stat()
+		if err != nil {
+			ef.Close()
+			return names, 0, err
+		}
+
+		header := http.Header{
+			"Content-Encoding": {encoding},
+		}
+
+		objName := variantPath(tagPath, encoding, file.relPath)
+		if i == len(variants) {
+			objName = name
+			size = info.Size()
+
+			if len(variants) != 0 {
+				header.Set(variantsHeader, strings.Join(variants, ","))
 			}
 		}
 
-		size := info.Size()
+		err = put(objName, ef, info.Size(), header)
+		ef.Close()
 
-		var r io.Reader = f
-		var encoding []string
+		if err != nil {
+			return names, 0, err
+		}
+	}
 
-		if size > 1024 {
-			buf := bufferPool.Get().(*bytes.Buffer)
-			defer bufferPool.Put(buf)
-			buf.Reset()
+	return names, size, nil
+}
 
-			gzw := gzip.NewWriter(buf)
+// fetch writes the source of the repository at commit
+// to repoPath.
+func (bj buildJekyllGetter) fetch(source sourceProvider, user, repo, commit, repoPath string, buildLog io.Writer) (resp BuildJekyllResponse, err error) {
+	ctx := context.Background()
 
-			if _, err := copyBuffer(gzw, f); err != nil {
-				return err
-			}
+	if bj.FetchTimeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, bj.FetchTimeout)
+		defer cancel()
+	}
 
-			if err := gzw.Close(); err != nil {
-				return err
-			}
+	if gs, ok := source.(gitSource); ok && bj.Mirror != nil {
+		resp, err = bj.fetchGit(ctx, gs, user, repo, commit, repoPath, buildLog)
+	} else {
+		resp, err = bj.fetchArchive(ctx, source, user, repo, commit, repoPath)
+	}
 
-			if bufLen := int64(buf.Len()); bufLen < size {
-				r = buf
-				size = bufLen
-				encoding = []string{"gzip"}
-			} else if _, err := f.Seek(0, os.SEEK_SET); err != nil {
-				return err
-			}
+	if ctx.Err() == context.DeadlineExceeded {
+		// a slow source may be quicker next time
+		resp.Error = fmt.Sprintf("fetching the repository timed out after %s", bj.FetchTimeout)
+		resp.Code = http.StatusGatewayTimeout
+		return resp, nil
+	}
+
+	return resp, err
+}
+
+func (bj buildJekyllGetter) fetchGit(ctx context.Context, source gitSource, user, repo, commit, repoPath string, buildLog io.Writer) (resp BuildJekyllResponse, err error) {
+	if !isCommitSHA(commit) {
+		resp.Error = fmt.Sprintf("invalid commit '%s'", commit)
+		resp.Code = http.StatusBadRequest
+		return resp, nil
+	}
+
+	remote := source.CloneURL(user, repo)
+	if len(remote) == 0 {
+		resp.Error = fmt.Sprintf("no clone URL for %s/%s", user, repo)
+		resp.Code = http.StatusNotFound
+		return resp, nil
+	}
+
+	auth, err := source.CloneAuthorization()
+	if err != nil {
+		return resp, err
+	}
+
+	if err := bj.Mirror.Checkout(ctx, remote, auth, commit, repoPath, buildLog); err != nil {
+		resp.Error = fmt.Sprintf("%[1]T: %[1]v", err)
+		resp.Code = int32(sourceErrorCode(err))
+		return resp, nil
+	}
+
+	if err := checkTree(repoPath, bj.ExtractLimits); err != nil {
+		eerr, ok := err.(*extractError)
+		if !ok {
+			return resp, err
 		}
 
-		err = bj.S3Bucket.PutReaderHeader(filepath.Join(tagPath, filePath[len(repoPath):]), r, size, map[string][]string{
-			"Cache-Control":       {builtRepoCacheControl},
-			"Content-Encoding":    encoding,
-			"Content-Type":        {ctype},
-			"x-amz-storage-class": {"REDUCED_REDUNDANCY"},
-		}, "")
-		return err
-	}); err != nil {
+		resp.Error = eerr.Error()
+		resp.Code = int32(eerr.Code)
+	}
+
+	return resp, nil
+}
+
+func (bj buildJekyllGetter) fetchArchive(ctx context.Context, source sourceProvider, user, repo, commit, repoPath string) (resp BuildJekyllResponse, err error) {
+	archive, err := source.Archive(ctx, user, repo, commit)
+	if err != nil {
 		resp.Error = fmt.Sprintf("%[1]T: %[1]v", err)
+		resp.Code = int32(sourceErrorCode(err))
+		return resp, nil
 	}
 
-	return dest.SetProto(&resp)
+	defer archive.Close()
+
+	reader, err := gzip.NewReader(archive)
+	if err != nil {
+		return resp, err
+	}
+
+	defer reader.Close()
+
+	if err := extractTarball(reader, repoPath, bj.ExtractLimits); err != nil {
+		resp.Error = fmt.Sprintf("%[1]T: %[1]v", err)
+
+		if eerr, ok := err.(*extractError); ok {
+			resp.Code = int32(eerr.Code)
+		}
+	}
+
+	return resp, nil
 }
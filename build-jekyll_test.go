@@ -0,0 +1,709 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// GOMAXPROCS=10 go test
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/groupcache"
+	"github.com/golang/protobuf/proto"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	for _, test := range []struct {
+		attempts int32
+		backoff  time.Duration
+	}{
+		{1, minRetryBackoff},
+		{2, 2 * minRetryBackoff},
+		{3, 4 * minRetryBackoff},
+		{100, maxRetryBackoff},
+	} {
+		if backoff := retryBackoff(test.attempts); backoff != test.backoff {
+			t.Errorf("retryBackoff(%d) = %v, expected %v", test.attempts, backoff, test.backoff)
+		}
+	}
+}
+
+func TestBuildJekyllFailureRecord(t *testing.T) {
+	store := newMemoryStorage()
+	bj := buildJekyllGetter{Storage: store}
+
+	_, key := buildKey(defaultSourceName, "user", "repo", testTag)
+	tag := key[:32]
+
+	retryAfter := time.Now().Add(time.Hour).Unix()
+
+	if err := bj.saveFailure(tagPrefix(tag), &BuildJekyllResponse{
+		Error:      "upstream failed",
+		Code:       http.StatusBadGateway,
+		Transient:  true,
+		RetryAfter: retryAfter,
+		Attempts:   1,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp BuildJekyllResponse
+
+	err := bj.Get(nil, key, groupcache.ProtoSink(&resp))
+	if ferr, ok := err.(*buildFailedError); !ok {
+		t.Errorf("Get returned %v, expected *buildFailedError", err)
+	} else if ferr.Response.RetryAfter != retryAfter || ferr.Response.Attempts != 1 {
+		t.Errorf("Get returned unexpected failure %+v", ferr.Response)
+	}
+
+	if err := bj.saveFailure(tagPrefix(tag), &BuildJekyllResponse{
+		Error:      "jekyll failed",
+		Code:       http.StatusUnprocessableEntity,
+		RetryAfter: retryAfter,
+		Attempts:   1,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// permanent failures are errors too, so that groupcache
+	// does not keep them beyond RetryAfter
+	err = bj.Get(nil, key, groupcache.ProtoSink(&resp))
+	if ferr, ok := err.(*buildFailedError); !ok {
+		t.Errorf("Get returned %v, expected *buildFailedError", err)
+	} else if ferr.Response.Code != http.StatusUnprocessableEntity || ferr.Response.Transient {
+		t.Errorf("Get returned unexpected failure %+v", ferr.Response)
+	}
+}
+
+func TestBuildJekyllPartialSite(t *testing.T) {
+	store := newMemoryStorage()
+	bj := buildJekyllGetter{Storage: store}
+
+	tag, key := buildKey("local", "user", "repo", testSHA)
+
+	// a site that is still being stored is not built
+	if err := store.Put(tagPrefix(tag)+"/index.html", bytes.NewReader([]byte("index")), 5, http.Header{
+		"Content-Type": {"text/html; charset=utf-8"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp BuildJekyllResponse
+
+	if err := bj.Get(nil, key, groupcache.ProtoSink(&resp)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Error) == 0 {
+		t.Error("Get returned a site without a manifest as built")
+	}
+
+	if err := saveBuildManifest(store, tagPrefix(tag), newBuildManifest("local", "user", "repo", &BuildJekyllResponse{
+		Commit: testSHA,
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	resp = BuildJekyllResponse{}
+
+	if err := bj.Get(nil, key, groupcache.ProtoSink(&resp)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Error) != 0 || resp.Commit != testSHA {
+		t.Errorf("Get returned %+v, expected the built site", &resp)
+	}
+}
+
+func TestBuildJekyllTimeout(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err := ioutil.TempDir("", "build-timeout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	commit := newTestGitRepo(t, root, "user", "site", map[string]string{
+		"index.md": "# Hello",
+	})
+
+	ls, err := newLocalSource("local", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bj := buildJekyllGetter{
+		WorkingDirectory: root,
+
+		Executor: jekyllExecutorFunc(func(ctx context.Context, src, dst, baseURL string, out io.Writer) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+		Timeout: 10 * time.Millisecond,
+
+		Storage: newMemoryStorage(),
+
+		Sources: sourceRegistry{"local": ls},
+	}
+
+	tag, key := buildKey("local", "user", "site", commit)
+
+	// timeouts are retried, up to maxTimedOutAttempts
+	for attempt := int32(1); attempt <= maxTimedOutAttempts; attempt++ {
+		var resp BuildJekyllResponse
+
+		ferr, ok := bj.Get(nil, key, groupcache.ProtoSink(&resp)).(*buildFailedError)
+		if !ok {
+			t.Fatal("Get did not return a *buildFailedError")
+		}
+
+		resp = ferr.Response
+
+		if transient := attempt < maxTimedOutAttempts; !resp.TimedOut || resp.Code != http.StatusUnprocessableEntity ||
+			resp.Transient != transient || resp.Attempts != attempt {
+			t.Errorf("Get returned unexpected failure %+v on attempt %d", resp, attempt)
+		}
+
+		// allow the next attempt
+		resp.RetryAfter = 0
+
+		if err := bj.saveFailure(tagPrefix(tag), &resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBuildJekyllLogLimit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err := ioutil.TempDir("", "build-log-limit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	commit := newTestGitRepo(t, root, "user", "site", map[string]string{
+		"index.md": "# Hello",
+	})
+
+	ls, err := newLocalSource("local", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := newMemoryStorage()
+
+	bj := buildJekyllGetter{
+		WorkingDirectory: root,
+
+		// the build fills the log before it fails
+		Executor: jekyllExecutorFunc(func(ctx context.Context, src, dst, baseURL string, out io.Writer) error {
+			out.Write(bytes.Repeat([]byte("x"), maxBuildLogSize))
+			return errors.New("jekyll exited")
+		}),
+
+		Storage: store,
+
+		Sources: sourceRegistry{"local": ls},
+	}
+
+	tag, key := buildKey("local", "user", "site", commit)
+
+	var resp BuildJekyllResponse
+	if _, ok := bj.Get(nil, key, groupcache.ProtoSink(&resp)).(*buildFailedError); !ok {
+		t.Fatal("Get did not return a *buildFailedError")
+	}
+
+	obj, err := store.Get(tagPrefix(tag) + ".log")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer obj.Body.Close()
+
+	log, err := ioutil.ReadAll(obj.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(log) > maxBuildLogSize {
+		t.Errorf("build log is %d bytes, expected at most %d", len(log), maxBuildLogSize)
+	}
+
+	if !bytes.Contains(log, []byte("\nbuild failed: ")) {
+		t.Error("build log does not say why the build failed")
+	}
+}
+
+func TestBuildJekyllFetchGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err := ioutil.TempDir("", "build-fetch")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	commit := newTestGitRepo(t, root, "user", "site", map[string]string{
+		"index.md": "# Hello",
+		"about.md": "# About",
+	})
+
+	ls, err := newLocalSource("local", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		limits  extractLimits
+		timeout time.Duration
+
+		code      int32
+		transient bool
+	}{
+		{extractLimits{MaxFiles: 1}, 0, http.StatusRequestEntityTooLarge, false},
+		{extractLimits{MaxFileSize: 4}, 0, http.StatusRequestEntityTooLarge, false},
+		{extractLimits{MaxBytes: 10}, 0, http.StatusRequestEntityTooLarge, false},
+		{extractLimits{}, time.Nanosecond, http.StatusGatewayTimeout, true},
+	} {
+		bj := buildJekyllGetter{
+			WorkingDirectory: filepath.Join(root, "work"),
+
+			Executor: jekyllExecutorFunc(func(ctx context.Context, src, dst, baseURL string, out io.Writer) error {
+				t.Error("jekyll was run")
+				return nil
+			}),
+
+			FetchTimeout:  test.timeout,
+			ExtractLimits: test.limits,
+
+			Storage: newMemoryStorage(),
+
+			Sources: sourceRegistry{"local": ls},
+
+			Mirror: &gitMirror{Dir: filepath.Join(root, "mirrors")},
+		}
+
+		_, key := buildKey("local", "user", "site", commit)
+
+		var resp BuildJekyllResponse
+
+		err := bj.Get(nil, key, groupcache.ProtoSink(&resp))
+		if ferr, ok := err.(*buildFailedError); ok {
+			resp = ferr.Response
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.Code != test.code || resp.Transient != test.transient {
+			t.Errorf("Get with limits %+v and timeout %s returned unexpected failure %+v", test.limits, test.timeout, resp)
+		}
+	}
+}
+
+func TestBuildJekyllBaseURL(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err := ioutil.TempDir("", "build-baseurl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	commit := newTestGitRepo(t, root, "user", "site", map[string]string{
+		"index.md": "# Hello",
+	})
+
+	ls, err := newLocalSource("local", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tag, key := buildKey("local", "user", "site", commit)
+
+	for _, sitePaths := range []bool{false, true} {
+		expect := ""
+		if sitePaths {
+			expect = sitePathPrefix + tag
+		}
+
+		var baseURL string
+
+		bj := buildJekyllGetter{
+			WorkingDirectory: root,
+
+			Executor: jekyllExecutorFunc(func(ctx context.Context, src, dst, url string, out io.Writer) error {
+				baseURL = url
+
+				if err := os.MkdirAll(dst, 0755); err != nil {
+					return err
+				}
+
+				return ioutil.WriteFile(filepath.Join(dst, "index.html"), []byte("<h1>Hello</h1>"), 0644)
+			}),
+
+			Storage: newMemoryStorage(),
+
+			Sources: sourceRegistry{"local": ls},
+			Hosts: &hostConfig{
+				Primary:   "jekyllhistory.org",
+				SitePaths: sitePaths,
+			},
+		}
+
+		var resp BuildJekyllResponse
+
+		if err := bj.Get(nil, key, groupcache.ProtoSink(&resp)); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(resp.Error) != 0 {
+			t.Fatalf("Get returned error %q", resp.Error)
+		}
+
+		if baseURL != expect {
+			t.Errorf("site built with baseurl %q with -site-paths=%t, expected %q", baseURL, sitePaths, expect)
+		}
+
+		rules, err := loadSiteRules(bj.Storage, tagPrefix(tag))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := rules.baseURL(); got != expect {
+			t.Errorf("rules recorded baseurl %q with -site-paths=%t, expected %q", got, sitePaths, expect)
+		}
+	}
+}
+
+func TestBuildJekyllManifest(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err := ioutil.TempDir("", "build-manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	commit := newTestGitRepo(t, root, "user", "site", map[string]string{
+		"index.md": "# Hello",
+	})
+
+	ls, err := newLocalSource("local", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := newMemoryStorage()
+
+	bj := buildJekyllGetter{
+		WorkingDirectory: root,
+
+		Executor: jekyllExecutorFunc(func(ctx context.Context, src, dst, baseURL string, out io.Writer) error {
+			io.WriteString(out, "jekyll 3.1.6\n       Deprecation: old option\n")
+
+			if err := os.MkdirAll(dst, 0755); err != nil {
+				return err
+			}
+
+			return ioutil.WriteFile(filepath.Join(dst, "index.html"), []byte("<h1>Hello</h1>"), 0644)
+		}),
+
+		Storage: store,
+
+		Sources: sourceRegistry{"local": ls},
+	}
+
+	_, key := buildKey("local", "user", "site", commit)
+
+	var built, cached BuildJekyllResponse
+
+	if err := bj.Get(nil, key, groupcache.ProtoSink(&built)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(built.Error) != 0 {
+		t.Fatal(built.Error)
+	}
+
+	if built.Commit != commit || built.Started == 0 || built.JekyllVersion != "3.1.6" ||
+		built.Files != 1 || built.Bytes != 14 || built.CompressedBytes != 14 ||
+		len(built.Warnings) != 1 || built.Warnings[0] != "Deprecation: old option" {
+		t.Errorf("Get returned unexpected metadata %+v", built)
+	}
+
+	// an already built site is described by its manifest
+	if err := bj.Get(nil, key, groupcache.ProtoSink(&cached)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !proto.Equal(&built, &cached) {
+		t.Errorf("Get returned %+v for built site, expected %+v", cached, built)
+	}
+}
+
+func TestBuildJekyllVariants(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err := ioutil.TempDir("", "build-variants")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	commit := newTestGitRepo(t, root, "user", "site", map[string]string{
+		"index.md": "# Hello",
+	})
+
+	ls, err := newLocalSource("local", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := newMemoryStorage()
+
+	bj := buildJekyllGetter{
+		WorkingDirectory: root,
+
+		Executor: jekyllExecutorFunc(func(ctx context.Context, src, dst, baseURL string, out io.Writer) error {
+			if err := os.MkdirAll(dst, 0755); err != nil {
+				return err
+			}
+
+			return ioutil.WriteFile(filepath.Join(dst, "index.html"), bytes.Repeat([]byte("<p>Hello</p>\n"), 256), 0644)
+		}),
+
+		Storage: store,
+
+		Sources: sourceRegistry{"local": ls},
+	}
+
+	tag, key := buildKey("local", "user", "site", commit)
+
+	var resp BuildJekyllResponse
+
+	if err := bj.Get(nil, key, groupcache.ProtoSink(&resp)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Error) != 0 {
+		t.Fatal(resp.Error)
+	}
+
+	obj, err := store.Head(tagPrefix(tag) + "/index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if enc := obj.Header.Get("Content-Encoding"); enc != "gzip" {
+		t.Errorf("site was stored with Content-Encoding %q, expected gzip", enc)
+	}
+
+	variants := splitList(obj.Header.Get(variantsHeader))
+	if len(variants) == 0 {
+		t.Fatal("site was stored without variants")
+	}
+
+	for _, variant := range variants {
+		vobj, err := store.Head(variantPath(tagPrefix(tag), variant, "index.html"))
+		if err != nil {
+			t.Errorf("%s variant was not stored: %v", variant, err)
+			continue
+		}
+
+		if enc := vobj.Header.Get("Content-Encoding"); enc != variant {
+			t.Errorf("%s variant was stored with Content-Encoding %q", variant, enc)
+		}
+	}
+
+	// the index records the variants
+	paths, err := loadSitePaths(store, tagPrefix(tag))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := append([]string{"gzip"}, variants...); !reflect.DeepEqual(paths.encodings["/index.html"], expect) {
+		t.Errorf("index recorded encodings %q, expected %q", paths.encodings["/index.html"], expect)
+	}
+}
+
+func TestBuildJekyllSiteRules(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err := ioutil.TempDir("", "build-rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	commit := newTestGitRepo(t, root, "user", "site", map[string]string{
+		"index.md": "# Hello",
+	})
+
+	ls, err := newLocalSource("local", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := newMemoryStorage()
+
+	bj := buildJekyllGetter{
+		WorkingDirectory: root,
+
+		Executor: jekyllExecutorFunc(func(ctx context.Context, src, dst, baseURL string, out io.Writer) error {
+			if err := os.MkdirAll(dst, 0755); err != nil {
+				return err
+			}
+
+			for name, body := range map[string]string{
+				"index.html": "<h1>Hello</h1>",
+				"_headers":   "/*\n  X-Frame-Options: DENY\n",
+				"_redirects": "/old /new\n/proxy https://example.com/ 200\n",
+			} {
+				if err := ioutil.WriteFile(filepath.Join(dst, name), []byte(body), 0644); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}),
+
+		Storage: store,
+
+		Sources: sourceRegistry{"local": ls},
+	}
+
+	tag, key := buildKey("local", "user", "site", commit)
+
+	var resp BuildJekyllResponse
+
+	if err := bj.Get(nil, key, groupcache.ProtoSink(&resp)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Error) != 0 {
+		t.Fatal(resp.Error)
+	}
+
+	if resp.Files != 1 || len(resp.Warnings) != 1 {
+		t.Errorf("Get returned unexpected metadata %+v", resp)
+	}
+
+	for _, name := range []string{"_headers", "_redirects"} {
+		if _, err := store.Head(tagPrefix(tag) + "/" + name); !os.IsNotExist(err) {
+			t.Errorf("%s was uploaded", name)
+		}
+	}
+
+	rules, err := loadSiteRules(store, tagPrefix(tag))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rules == nil || len(rules.Headers) != 1 || len(rules.Redirects) != 1 {
+		t.Errorf("site was stored with rules %+v", rules)
+	}
+
+	paths, err := loadSitePaths(store, tagPrefix(tag))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if paths == nil || len(paths.files) != 1 || !paths.has("/index.html") {
+		t.Errorf("site was stored with paths %+v", paths)
+	}
+}
+
+type versionedExecutor struct {
+	jekyllExecutorFunc
+}
+
+func (versionedExecutor) Capabilities() jekyllCapabilities {
+	return jekyllCapabilities{Version: "4.0.0", Isolated: true}
+}
+
+func TestBuildJekyllExecutorVersion(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err := ioutil.TempDir("", "build-version")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	commit := newTestGitRepo(t, root, "user", "site", map[string]string{
+		"index.md": "# Hello",
+	})
+
+	ls, err := newLocalSource("local", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bj := buildJekyllGetter{
+		WorkingDirectory: root,
+
+		// the version is not logged
+		Executor: versionedExecutor{func(ctx context.Context, src, dst, baseURL string, out io.Writer) error {
+			if err := os.MkdirAll(dst, 0755); err != nil {
+				return err
+			}
+
+			return ioutil.WriteFile(filepath.Join(dst, "index.html"), []byte("<h1>Hello</h1>"), 0644)
+		}},
+
+		Storage: newMemoryStorage(),
+
+		Sources: sourceRegistry{"local": ls},
+	}
+
+	_, key := buildKey("local", "user", "site", commit)
+
+	var resp BuildJekyllResponse
+
+	if err := bj.Get(nil, key, groupcache.ProtoSink(&resp)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Error) != 0 || resp.JekyllVersion != "4.0.0" {
+		t.Errorf("Get returned version %q and error %q, expected version %q", resp.JekyllVersion, resp.Error, "4.0.0")
+	}
+}
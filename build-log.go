@@ -0,0 +1,78 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func getBuildLogHandler(sources sourceRegistry, store storage) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var cacheControl = fmt.Sprintf("public, max-age=%d", time.Minute/time.Second)
+
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		h := w.Header()
+
+		source, ok := sources.fromParams(ps)
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		user, repo, ref := ps.ByName("user"), ps.ByName("repo"), ps.ByName("commit")
+
+		commit, code, err := resolveCommit(source, user, repo, ref)
+		if err != nil {
+			if code != http.StatusNotFound {
+				log.Printf("%[1]T %[1]v", err)
+			}
+
+			http.Error(w, http.StatusText(code), code)
+			return
+		}
+
+		if commit != ref {
+			h.Set("Cache-Control", "max-age=0")
+
+			newURL := *r.URL
+			newURL.Path = commitPath(source.Name(), user, repo, commit) + "log"
+
+			http.Redirect(w, r, newURL.String(), http.StatusFound)
+			return
+		}
+
+		tag, _ := buildKey(source.Name(), user, repo, commit)
+
+		obj, err := store.Get(tagPrefix(tag) + ".log")
+		if err != nil {
+			code := storageErrorCode(err)
+			if code != http.StatusNotFound {
+				log.Printf("%[1]T: %[1]v", err)
+			}
+
+			http.Error(w, http.StatusText(code), code)
+			return
+		}
+
+		defer obj.Body.Close()
+
+		h.Set("Cache-Control", cacheControl)
+		h.Set("Content-Type", "text/plain; charset=utf-8")
+		h.Set("X-Content-Type-Options", "nosniff")
+
+		if clen := obj.Header.Get("Content-Length"); len(clen) != 0 {
+			h.Set("Content-Length", clen)
+		}
+
+		w.WriteHeader(http.StatusOK)
+
+		copyBuffer(w, obj.Body)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxBuildWarnings is the most warnings kept for a build.
+const maxBuildWarnings = 100
+
+// buildManifest describes a built site. It is stored as
+// JSON next to the site.
+type buildManifest struct {
+	Source string `json:"source"`
+	User   string `json:"user"`
+	Repo   string `json:"repo"`
+	Commit string `json:"commit"`
+
+	Started    time.Time `json:"started"`
+	DurationMS int64     `json:"duration_ms"`
+
+	JekyllVersion string `json:"jekyll_version,omitempty"`
+
+	Files           int64 `json:"files"`
+	Bytes           int64 `json:"bytes"`
+	CompressedBytes int64 `json:"compressed_bytes"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func newBuildManifest(source, user, repo string, resp *BuildJekyllResponse) *buildManifest {
+	return &buildManifest{
+		Source: source,
+		User:   user,
+		Repo:   repo,
+		Commit: resp.Commit,
+
+		Started:    time.Unix(resp.Started, 0).UTC(),
+		DurationMS: resp.DurationMs,
+
+		JekyllVersion: resp.JekyllVersion,
+
+		Files:           resp.Files,
+		Bytes:           resp.Bytes,
+		CompressedBytes: resp.CompressedBytes,
+
+		Warnings: resp.Warnings,
+	}
+}
+
+// Response fills in the build metadata of resp.
+func (m *buildManifest) Response(resp *BuildJekyllResponse) {
+	resp.Commit = m.Commit
+	resp.Started = m.Started.Unix()
+	resp.DurationMs = m.DurationMS
+	resp.JekyllVersion = m.JekyllVersion
+	resp.Files = m.Files
+	resp.Bytes = m.Bytes
+	resp.CompressedBytes = m.CompressedBytes
+	resp.Warnings = m.Warnings
+}
+
+// Duration returns how long the build took to the
+// nearest second.
+func (m *buildManifest) Duration() time.Duration {
+	d := time.Duration(m.DurationMS) * time.Millisecond
+	return (d + time.Second/2) / time.Second * time.Second
+}
+
+func saveBuildManifest(store storage, tagPath string, m *buildManifest) error {
+	data, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return store.Put(tagPath+".json", bytes.NewReader(data), int64(len(data)), http.Header{
+		"Content-Type": {"application/json; charset=utf-8"},
+	})
+}
+
+// loadBuildManifest returns the manifest of a built site
+// or nil if there is none.
+func loadBuildManifest(store storage, tagPath string) (*buildManifest, error) {
+	obj, err := store.Get(tagPath + ".json")
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	defer obj.Body.Close()
+
+	var m buildManifest
+	if err = json.NewDecoder(obj.Body).Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+var (
+	jekyllVersionRegexp = regexp.MustCompile(`^jekyll (\S+)$`)
+	jekyllWarningRegexp = regexp.MustCompile(`^(?:Build Warning|Warning|Deprecation|Conflict):`)
+)
+
+// parseBuildLog returns the jekyll version the executor
+// logged and the warnings jekyll logged.
+func parseBuildLog(log []byte) (version string, warnings []string) {
+	s := bufio.NewScanner(bytes.NewReader(log))
+
+	for first := true; s.Scan(); first = false {
+		line := strings.TrimSpace(s.Text())
+
+		if m := jekyllVersionRegexp.FindStringSubmatch(line); first && m != nil {
+			version = m[1]
+		} else if jekyllWarningRegexp.MatchString(line) && len(warnings) < maxBuildWarnings {
+			warnings = append(warnings, line)
+		}
+	}
+
+	return
+}
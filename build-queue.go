@@ -0,0 +1,300 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/groupcache"
+)
+
+var errBuildQueueFull = errors.New("build queue is full")
+
+type buildState int
+
+const (
+	buildQueued buildState = iota
+	buildRunning
+	buildSucceeded
+	buildFailed
+)
+
+func (s buildState) String() string {
+	switch s {
+	case buildQueued:
+		return "queued"
+	case buildRunning:
+		return "running"
+	case buildSucceeded:
+		return "succeeded"
+	case buildFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("buildState(%d)", int(s))
+	}
+}
+
+type buildJob struct {
+	Tag string
+	Key string
+
+	Source, User, Repo, Commit string
+
+	mu    sync.Mutex
+	state buildState
+	resp  BuildJekyllResponse
+
+	statusUser, statusRepo string
+
+	done chan struct{}
+}
+
+// State returns the current state of the job and, once
+// the job has finished, the response from buildJekyll.
+func (j *buildJob) State() (buildState, *BuildJekyllResponse) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	switch j.state {
+	case buildSucceeded, buildFailed:
+		resp := j.resp
+		return j.state, &resp
+	default:
+		return j.state, nil
+	}
+}
+
+// Wait waits up to timeout for the job to finish and
+// reports whether it did.
+func (j *buildJob) Wait(timeout time.Duration) bool {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	select {
+	case <-j.done:
+		return true
+	case <-t.C:
+		return false
+	}
+}
+
+// StatusRepo returns the repository that the statuses of
+// the job are reported on. It is the one built, unless the
+// job was queued with EnqueueFor.
+func (j *buildJob) StatusRepo() (user, repo string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if len(j.statusUser) != 0 {
+		return j.statusUser, j.statusRepo
+	}
+
+	return j.User, j.Repo
+}
+
+func (j *buildJob) setState(state buildState, resp *BuildJekyllResponse) {
+	j.mu.Lock()
+	j.state = state
+
+	if resp != nil {
+		j.resp = *resp
+	}
+	j.mu.Unlock()
+}
+
+// buildNotifier is told when a build starts and when it
+// finishes.
+type buildNotifier interface {
+	Notify(job *buildJob, state buildState, resp *BuildJekyllResponse)
+}
+
+// buildQueue runs buildJekyll builds on a fixed number of
+// workers so that requests need not wait for them.
+//
+// Finished jobs are remembered for Retain so the status
+// page can report them.
+type buildQueue struct {
+	Group  *groupcache.Group
+	Retain time.Duration
+
+	// Notifier, if set, is told about each build.
+	Notifier buildNotifier
+
+	// Storage, if set, is read for the failure record of
+	// builds that failed on a peer, as errors from peers
+	// only carry their status.
+	Storage storage
+
+	queue chan *buildJob
+
+	mu   sync.Mutex
+	jobs map[string]*buildJob
+}
+
+func newBuildQueue(group *groupcache.Group, workers, size int) *buildQueue {
+	q := &buildQueue{
+		Group:  group,
+		Retain: 10 * time.Minute,
+
+		queue: make(chan *buildJob, size),
+
+		jobs: make(map[string]*buildJob),
+	}
+
+	for i := 0; i < workers; i++ {
+		go q.worker()
+	}
+
+	return q
+}
+
+// Enqueue queues a build of the given commit unless one is
+// already known. It returns errBuildQueueFull if the queue
+// has no room left.
+func (q *buildQueue) Enqueue(source, user, repo, commit string) (*buildJob, error) {
+	return q.EnqueueFor(source, user, repo, commit, "", "")
+}
+
+// EnqueueFor is like Enqueue, but the statuses of the build
+// are reported on statusUser/statusRepo, such as the base
+// repository of a pull request from a fork.
+func (q *buildQueue) EnqueueFor(source, user, repo, commit, statusUser, statusRepo string) (*buildJob, error) {
+	tag, key := buildKey(source, user, repo, commit)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if job, ok := q.jobs[tag]; ok {
+		if len(statusUser) != 0 {
+			job.mu.Lock()
+			job.statusUser, job.statusRepo = statusUser, statusRepo
+			job.mu.Unlock()
+		}
+
+		return job, nil
+	}
+
+	job := &buildJob{
+		Tag: tag,
+		Key: key,
+
+		Source: source,
+		User:   user,
+		Repo:   repo,
+		Commit: commit,
+
+		statusUser: statusUser,
+		statusRepo: statusRepo,
+
+		done: make(chan struct{}),
+	}
+
+	select {
+	case q.queue <- job:
+	default:
+		return nil, errBuildQueueFull
+	}
+
+	q.jobs[tag] = job
+	return job, nil
+}
+
+// Lookup returns the job for tag or nil if it is unknown.
+func (q *buildQueue) Lookup(tag string) *buildJob {
+	q.mu.Lock()
+	job := q.jobs[tag]
+	q.mu.Unlock()
+	return job
+}
+
+func (q *buildQueue) worker() {
+	for job := range q.queue {
+		job.setState(buildRunning, nil)
+		q.notify(job, buildRunning, nil)
+
+		var resp BuildJekyllResponse
+
+		if err := q.Group.Get(context.Background(), job.Key, groupcache.ProtoSink(&resp)); err != nil {
+			if ferr, ok := err.(*buildFailedError); ok {
+				resp = ferr.Response
+			} else if failure := q.peerFailure(job); failure != nil {
+				resp = *failure
+			} else {
+				resp = BuildJekyllResponse{
+					Error:      fmt.Sprintf("%[1]T: %[1]v", err),
+					Code:       http.StatusBadGateway,
+					Transient:  true,
+					RetryAfter: time.Now().Add(minRetryBackoff).Unix(),
+				}
+			}
+		}
+
+		if len(resp.Error) != 0 {
+			if resp.Code != http.StatusNotFound {
+				log.Println(resp.Error)
+			}
+
+			job.setState(buildFailed, &resp)
+		} else {
+			job.setState(buildSucceeded, &resp)
+		}
+
+		close(job.done)
+
+		state, _ := job.State()
+		q.notify(job, state, &resp)
+
+		// failures are forgotten once they may be retried
+		// so that the next request queues a new build
+		retain := q.Retain
+		if len(resp.Error) != 0 && resp.RetryAfter != 0 {
+			if until := time.Unix(resp.RetryAfter, 0).Sub(time.Now()); until < retain {
+				retain = until
+			}
+		}
+
+		job := job
+		time.AfterFunc(retain, func() {
+			q.mu.Lock()
+			if q.jobs[job.Tag] == job {
+				delete(q.jobs, job.Tag)
+			}
+			q.mu.Unlock()
+		})
+	}
+}
+
+// peerFailure returns the stored failure record of job if
+// it may not yet be retried, or nil.
+func (q *buildQueue) peerFailure(job *buildJob) *BuildJekyllResponse {
+	if q.Storage == nil {
+		return nil
+	}
+
+	failure, err := loadBuildFailure(q.Storage, tagPrefix(job.Tag))
+	if err != nil {
+		log.Printf("%[1]T: %[1]v", err)
+		return nil
+	}
+
+	if failure == nil || time.Now().Unix() >= failure.RetryAfter {
+		return nil
+	}
+
+	return failure
+}
+
+func (q *buildQueue) notify(job *buildJob, state buildState, resp *BuildJekyllResponse) {
+	if q.Notifier != nil {
+		q.Notifier.Notify(job, state, resp)
+	}
+}
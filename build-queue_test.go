@@ -0,0 +1,132 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/groupcache"
+)
+
+func TestBuildQueue(t *testing.T) {
+	var calls int32
+
+	group := groupcache.NewGroup("test-build-queue", 1<<20, groupcache.GetterFunc(func(_ groupcache.Context, key string, dest groupcache.Sink) error {
+		atomic.AddInt32(&calls, 1)
+
+		var resp BuildJekyllResponse
+		if strings.HasSuffix(key, "\x00missing") {
+			resp.Error = "not found"
+			resp.Code = http.StatusNotFound
+		}
+
+		return dest.SetProto(&resp)
+	}))
+
+	q := newBuildQueue(group, 1, 10)
+
+	job, err := q.Enqueue(defaultSourceName, "user", "repo", "commit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dup, err := q.Enqueue(defaultSourceName, "user", "repo", "commit"); err != nil || dup != job {
+		t.Error("Enqueue did not return existing job")
+	}
+
+	if !job.Wait(time.Second) {
+		t.Fatal("job did not finish")
+	}
+
+	if state, _ := job.State(); state != buildSucceeded {
+		t.Errorf("job in wrong state, expected %s, got %s", buildSucceeded, state)
+	}
+
+	if q.Lookup(job.Tag) != job {
+		t.Error("Lookup did not return job")
+	}
+
+	job, err = q.Enqueue(defaultSourceName, "user", "repo", "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !job.Wait(time.Second) {
+		t.Fatal("job did not finish")
+	}
+
+	if state, resp := job.State(); state != buildFailed || resp.Code != http.StatusNotFound {
+		t.Errorf("job in wrong state, expected %s with code %d, got %s with %v", buildFailed, http.StatusNotFound, state, resp)
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("getter called %d times, expected 2", n)
+	}
+}
+
+func TestBuildQueuePeerFailure(t *testing.T) {
+	// errors from peers carry no response
+	group := groupcache.NewGroup("test-build-queue-peer-failure", 1<<20, groupcache.GetterFunc(func(_ groupcache.Context, key string, dest groupcache.Sink) error {
+		return errors.New("server returned: 500 Internal Server Error")
+	}))
+
+	store := newMemoryStorage()
+
+	q := newBuildQueue(group, 1, 10)
+	q.Storage = store
+
+	tag, _ := buildKey(defaultSourceName, "user", "repo", "failed")
+
+	if err := (buildJekyllGetter{Storage: store}).saveFailure(tagPrefix(tag), &BuildJekyllResponse{
+		Error:      "jekyll failed",
+		Code:       http.StatusUnprocessableEntity,
+		RetryAfter: time.Now().Add(time.Hour).Unix(),
+		Attempts:   1,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		commit string
+		code   int32
+	}{
+		{"failed", http.StatusUnprocessableEntity},
+		{"unknown", http.StatusBadGateway},
+	} {
+		job, err := q.Enqueue(defaultSourceName, "user", "repo", test.commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !job.Wait(time.Second) {
+			t.Fatal("job did not finish")
+		}
+
+		if state, resp := job.State(); state != buildFailed || resp.Code != test.code {
+			t.Errorf("job for %s in wrong state, expected %s with code %d, got %s with %v", test.commit, buildFailed, test.code, state, resp)
+		}
+	}
+}
+
+func TestBuildQueueFull(t *testing.T) {
+	q := &buildQueue{
+		queue: make(chan *buildJob, 1),
+		jobs:  make(map[string]*buildJob),
+	}
+
+	if _, err := q.Enqueue(defaultSourceName, "user", "repo", "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := q.Enqueue(defaultSourceName, "user", "repo", "b"); err != errBuildQueueFull {
+		t.Errorf("Enqueue returned wrong error, expected %v, got %v", errBuildQueueFull, err)
+	}
+}
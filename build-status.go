@@ -0,0 +1,118 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func getBuildStatusHandler(sources sourceRegistry, queue *buildQueue, store storage, hosts *hostConfig) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		h := w.Header()
+		h.Set("Cache-Control", "no-cache")
+
+		source, ok := sources.fromParams(ps)
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		user, repo, ref := ps.ByName("user"), ps.ByName("repo"), ps.ByName("commit")
+
+		commit, code, err := resolveCommit(source, user, repo, ref)
+		if err != nil {
+			if code != http.StatusNotFound {
+				log.Printf("%[1]T %[1]v", err)
+			}
+
+			http.Error(w, http.StatusText(code), code)
+			return
+		}
+
+		if commit != ref {
+			newURL := *r.URL
+			newURL.Path = commitPath(source.Name(), user, repo, commit) + "status"
+
+			http.Redirect(w, r, newURL.String(), http.StatusFound)
+			return
+		}
+
+		path := r.URL.Query().Get("path")
+		if !strings.HasPrefix(path, "/") {
+			path = "/"
+		}
+
+		tag, _ := buildKey(source.Name(), user, repo, commit)
+
+		job := queue.Lookup(tag)
+		if job == nil && isBuilt(store, tag) {
+			http.Redirect(w, r, hosts.siteURL(r, tag, path, ""), http.StatusFound)
+			return
+		}
+
+		if job == nil {
+			if job, err = queue.Enqueue(source.Name(), user, repo, commit); err != nil {
+				log.Println(err)
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+		}
+
+		state, resp := job.State()
+
+		switch state {
+		case buildSucceeded:
+			http.Redirect(w, r, hosts.siteURL(r, tag, path, ""), http.StatusFound)
+			return
+		case buildFailed:
+			if resp.Transient {
+				if retry := resp.RetryAfter - time.Now().Unix(); retry > 0 {
+					h.Set("Retry-After", strconv.FormatInt(retry, 10))
+				}
+			}
+
+			if resp.Code != http.StatusNotFound {
+				setErrorLogURL(w, commitPath(source.Name(), user, repo, commit)+"log")
+			}
+
+			if resp.Code == 0 {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			} else {
+				http.Error(w, http.StatusText(int(resp.Code)), int(resp.Code))
+			}
+
+			return
+		}
+
+		if wrote, err := executeTemplate(statusTemplate, struct {
+			Prefix string
+
+			User   string
+			Repo   string
+			Commit string
+			State  buildState
+		}{
+			Prefix: sourcePrefix(source.Name()),
+
+			User:   user,
+			Repo:   repo,
+			Commit: commit,
+			State:  state,
+		}, w); err != nil {
+			log.Printf("%[1]T %[1]v", err)
+
+			if !wrote {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}
+	}
+}
@@ -13,11 +13,10 @@ import (
 	"net/url"
 	"time"
 
-	"github.com/google/go-github/github"
 	"github.com/julienschmidt/httprouter"
 )
 
-func getCommitHandler(githubClient *github.Client, highlightStyle string) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+func getCommitHandler(sources sourceRegistry, store storage, highlightStyle string) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	var cacheControl = fmt.Sprintf("public, max-age=%d", time.Minute/time.Second)
 
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
@@ -28,24 +27,34 @@ func getCommitHandler(githubClient *github.Client, highlightStyle string) func(w
 			return
 		}
 
+		source, ok := sources.fromParams(ps)
+		if !ok {
+			h.Del("Cache-Control")
+
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
 		user, repo, commit := ps.ByName("user"), ps.ByName("repo"), ps.ByName("commit")
 
-		repoCommit, resp, err := githubClient.Repositories.GetCommit(context.Background(), user, repo, commit)
+		repoCommit, err := source.GetCommit(context.Background(), user, repo, commit)
 		if err != nil {
 			h.Del("Cache-Control")
 
-			if gerr, ok := err.(*github.ErrorResponse); ok && gerr.Response.StatusCode == http.StatusNotFound {
-				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
-			} else {
+			code := sourceErrorCode(err)
+			if code != http.StatusNotFound {
 				log.Printf("%[1]T %[1]v", err)
-				http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
 			}
 
+			http.Error(w, http.StatusText(code), code)
 			return
 		}
 
-		if verbose {
-			log.Printf("GitHub API Rate Limit is %d remaining of %d, to be reset at %s\n", resp.Remaining, resp.Limit, resp.Reset)
+		tag, _ := buildKey(source.Name(), user, repo, repoCommit.SHA)
+
+		build, err := loadBuildManifest(store, tagPrefix(tag))
+		if err != nil {
+			log.Printf("%[1]T: %[1]v", err)
 		}
 
 		base := url.URL{
@@ -58,18 +67,28 @@ func getCommitHandler(githubClient *github.Client, highlightStyle string) func(w
 		}
 
 		if wrote, err := executeTemplate(commitTemplate, struct {
+			Source sourceProvider
+			Prefix string
+
 			User   string
 			Repo   string
-			Commit *github.RepositoryCommit
+			Commit *sourceCommit
+
+			Build *buildManifest
 
 			URLBase string
 
 			HighlightStyle string
 		}{
+			Source: source,
+			Prefix: sourcePrefix(source.Name()),
+
 			User:   user,
 			Repo:   repo,
 			Commit: repoCommit,
 
+			Build: build,
+
 			URLBase: base.String(),
 
 			HighlightStyle: highlightStyle,
@@ -0,0 +1,166 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// variantsHeader lists the encodings, other than that of
+// the object itself, that a built file is also stored in.
+// It is stored as S3 user metadata.
+const variantsHeader = "X-Amz-Meta-Encodings"
+
+// precompressors are the encodings built files are stored
+// in, most preferred first. gzip must be last as it is the
+// encoding of the object itself.
+var precompressors = []struct {
+	name      string
+	newWriter func(w io.Writer) (io.WriteCloser, error)
+}{
+	{"br", func(w io.Writer) (io.WriteCloser, error) {
+		return brotli.NewWriter(w), nil
+	}},
+	{"zstd", func(w io.Writer) (io.WriteCloser, error) {
+		return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	}},
+	{"gzip", func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	}},
+}
+
+// variantPath returns the name that the file name of the
+// site stored at tagPath is stored under when encoded with
+// encoding. Variants are kept apart from the site so they
+// cannot collide with its files.
+func variantPath(tagPath, encoding, name string) string {
+	return tagPath + "." + encoding + "/" + strings.TrimPrefix(name, "/")
+}
+
+// parseAcceptEncoding returns the q-value of each coding
+// in an Accept-Encoding header. Codings are lower case.
+func parseAcceptEncoding(header string) map[string]float64 {
+	codings := make(map[string]float64)
+
+	for _, v := range strings.Split(header, ",") {
+		params := strings.Split(v, ";")
+
+		coding := strings.ToLower(strings.TrimSpace(params[0]))
+		if len(coding) == 0 {
+			continue
+		}
+
+		q := 1.0
+
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if len(param) < 2 || param[0] != 'q' && param[0] != 'Q' || param[1] != '=' {
+				continue
+			}
+
+			if v, err := strconv.ParseFloat(param[2:], 64); err == nil && v >= 0 && v <= 1 {
+				q = v
+			} else {
+				q = 0
+			}
+		}
+
+		codings[coding] = q
+	}
+
+	return codings
+}
+
+// acceptsEncoding returns the q-value r gives coding.
+func acceptsEncoding(r *http.Request, coding string) float64 {
+	header, ok := r.Header["Accept-Encoding"]
+	if !ok {
+		// anything is acceptable, but only identity is
+		// sent without being asked for
+		if coding == "identity" {
+			return 1
+		}
+
+		return 0
+	}
+
+	codings := parseAcceptEncoding(strings.Join(header, ","))
+
+	if q, ok := codings[coding]; ok {
+		return q
+	}
+
+	if q, ok := codings["*"]; ok {
+		return q
+	}
+
+	// identity is acceptable unless it is refused
+	if coding == "identity" {
+		return 1
+	}
+
+	return 0
+}
+
+// negotiateEncoding returns the most preferred of the
+// available encodings, or the empty string for identity.
+// Ties go to the earliest encoding in available, which is
+// ordered by the server's preference, and then to identity.
+func negotiateEncoding(r *http.Request, available []string) string {
+	var best string
+	var bestQ float64
+
+	for _, coding := range available {
+		if q := acceptsEncoding(r, coding); q > bestQ {
+			best, bestQ = coding, q
+		}
+	}
+
+	if acceptsEncoding(r, "identity") > bestQ {
+		return ""
+	}
+
+	return best
+}
+
+// chooseVariant returns whichever of variants is the best
+// encoding for r, or an empty string if the object itself,
+// stored with encoding, is best.
+func chooseVariant(r *http.Request, encoding string, variants []string) string {
+	available := variants
+	if len(encoding) != 0 {
+		available = append(available[:len(available):len(available)], encoding)
+	}
+
+	best := negotiateEncoding(r, available)
+
+	for _, variant := range variants {
+		if variant == best {
+			return best
+		}
+	}
+
+	return ""
+}
+
+// variantETag returns the entity tag of the variant of an
+// object with etag stored with encoding. Each encoding is a
+// different representation, so each must have its own
+// strong tag.
+func variantETag(etag, encoding string) string {
+	if !strings.HasSuffix(etag, `"`) || len(etag) < 2 {
+		return etag
+	}
+
+	return etag[:len(etag)-1] + "-" + encoding + `"`
+}
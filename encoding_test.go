@@ -0,0 +1,248 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestParseAcceptEncoding(t *testing.T) {
+	for _, test := range []struct {
+		header string
+		expect map[string]float64
+	}{
+		{"", map[string]float64{}},
+		{"gzip", map[string]float64{"gzip": 1}},
+		{"gzip, deflate, br", map[string]float64{"gzip": 1, "deflate": 1, "br": 1}},
+		{"BR;q=0.5, gzip;Q=0.8", map[string]float64{"br": 0.5, "gzip": 0.8}},
+		{"gzip;q=0, *;q=0.1", map[string]float64{"gzip": 0, "*": 0.1}},
+		{"gzip;q=2, br;q=x", map[string]float64{"gzip": 0, "br": 0}},
+	} {
+		if codings := parseAcceptEncoding(test.header); !reflect.DeepEqual(codings, test.expect) {
+			t.Errorf("parseAcceptEncoding(%q) returned %v, expected %v", test.header, codings, test.expect)
+		}
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	available := []string{"br", "zstd", "gzip"}
+
+	for _, test := range []struct {
+		header string
+		set    bool
+		expect string
+	}{
+		{"", false, ""},
+		{"", true, ""},
+		{"gzip", true, "gzip"},
+		{"gzip, deflate, br", true, "br"},
+		{"gzip, zstd", true, "zstd"},
+		{"br;q=0.5, gzip", true, "gzip"},
+		{"br;q=0, gzip;q=0", true, ""},
+		{"*", true, "br"},
+		{"*, br;q=0", true, "zstd"},
+		{"br;q=0.5, identity;q=0.1", true, "br"},
+		{"br;q=0.5", true, ""},
+		{"br;q=0.5, identity;q=0", true, "br"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+		if test.set {
+			req.Header.Set("Accept-Encoding", test.header)
+		}
+
+		if best := negotiateEncoding(req, available); best != test.expect {
+			t.Errorf("negotiateEncoding with Accept-Encoding %q returned %q, expected %q", test.header, best, test.expect)
+		}
+	}
+}
+
+func TestRepoSwitchVariants(t *testing.T) {
+	rs := newTestRepoSwitch(t)
+
+	body := bytes.Repeat([]byte("variant "), 16)
+
+	var brBuf, zstdBuf bytes.Buffer
+
+	brw := brotli.NewWriter(&brBuf)
+	brw.Write(body)
+	brw.Close()
+
+	zw, err := zstd.NewWriter(&zstdBuf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zw.Write(body)
+	zw.Close()
+
+	var gzBuf bytes.Buffer
+
+	gzw := gzip.NewWriter(&gzBuf)
+	gzw.Write(body)
+	gzw.Close()
+
+	put := func(name string, buf []byte, header http.Header) {
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+
+		if err := rs.Storage.Put(name, bytes.NewReader(buf), int64(len(buf)), header); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	put(tagPrefix(testTag)+"/variant.txt", gzBuf.Bytes(), http.Header{
+		"Content-Encoding": {"gzip"},
+		variantsHeader:     {"br,zstd"},
+	})
+	put(variantPath(tagPrefix(testTag), "br", "variant.txt"), brBuf.Bytes(), http.Header{
+		"Content-Encoding": {"br"},
+	})
+	put(variantPath(tagPrefix(testTag), "zstd", "variant.txt"), zstdBuf.Bytes(), http.Header{
+		"Content-Encoding": {"zstd"},
+	})
+
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		for _, test := range []struct {
+			accept string
+			body   []byte
+			enc    string
+		}{
+			{"", body, ""},
+			{"gzip", gzBuf.Bytes(), "gzip"},
+			{"gzip, deflate, br", brBuf.Bytes(), "br"},
+			{"gzip, zstd", zstdBuf.Bytes(), "zstd"},
+			{"br;q=0.5, gzip", gzBuf.Bytes(), "gzip"},
+			{"br, zstd", brBuf.Bytes(), "br"},
+		} {
+			req := httptest.NewRequest(method, "http://"+testTag+".jekyllhistory.org/variant.txt", nil)
+			if len(test.accept) != 0 {
+				req.Header.Set("Accept-Encoding", test.accept)
+			}
+
+			rw := httptest.NewRecorder()
+			rs.ServeHTTP(rw, req)
+
+			if rw.Code != http.StatusOK {
+				t.Errorf("%s with Accept-Encoding %q returned wrong status code, expected %d, got %d", method, test.accept, http.StatusOK, rw.Code)
+				continue
+			}
+
+			if enc := rw.HeaderMap.Get("Content-Encoding"); enc != test.enc {
+				t.Errorf("%s with Accept-Encoding %q returned wrong Content-Encoding, expected %q, got %q", method, test.accept, test.enc, enc)
+			}
+
+			if vary := rw.HeaderMap.Get("Vary"); vary != "Accept-Encoding" {
+				t.Errorf("%s with Accept-Encoding %q returned wrong Vary, expected %q, got %q", method, test.accept, "Accept-Encoding", vary)
+			}
+
+			if method == http.MethodGet && !bytes.Equal(rw.Body.Bytes(), test.body) {
+				t.Errorf("GET with Accept-Encoding %q returned wrong body", test.accept)
+			}
+		}
+	}
+
+	// the variant is decodable
+	req := httptest.NewRequest(http.MethodGet, "http://"+testTag+".jekyllhistory.org/variant.txt", nil)
+	req.Header.Set("Accept-Encoding", "br")
+
+	rw := httptest.NewRecorder()
+	rs.ServeHTTP(rw, req)
+
+	if got, err := ioutil.ReadAll(brotli.NewReader(rw.Body)); err != nil || !bytes.Equal(got, body) {
+		t.Errorf("brotli variant decoded to %q, %v, expected %q", got, err, body)
+	}
+
+	// each variant has its own entity tag
+	for _, test := range []struct {
+		accept, etag string
+	}{
+		{"", `"` + testTag + `"`},
+		{"gzip", `"` + testTag + `"`},
+		{"br", `"` + testTag + `-br"`},
+		{"zstd", `"` + testTag + `-zstd"`},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "http://"+testTag+".jekyllhistory.org/variant.txt", nil)
+		req.Header.Set("Accept-Encoding", test.accept)
+
+		rw := httptest.NewRecorder()
+		rs.ServeHTTP(rw, req)
+
+		if etag := rw.HeaderMap.Get("Etag"); etag != test.etag {
+			t.Errorf("GET with Accept-Encoding %q returned wrong Etag, expected %s, got %s", test.accept, test.etag, etag)
+		}
+
+		req.Header.Set("If-None-Match", test.etag)
+
+		rw = httptest.NewRecorder()
+		rs.ServeHTTP(rw, req)
+
+		if rw.Code != http.StatusNotModified {
+			t.Errorf("GET with If-None-Match %s returned %d, expected %d", test.etag, rw.Code, http.StatusNotModified)
+		}
+	}
+
+	// the tags of other variants are not echoed back
+	for _, inm := range []string{`"` + testTag + `-br"`, `"` + testTag + `-<script>"`} {
+		req := httptest.NewRequest(http.MethodGet, "http://"+testTag+".jekyllhistory.org/variant.txt", nil)
+		req.Header.Set("Accept-Encoding", "zstd")
+		req.Header.Set("If-None-Match", inm)
+
+		rw := httptest.NewRecorder()
+		rs.ServeHTTP(rw, req)
+
+		if etag := `"` + testTag + `-zstd"`; rw.Code != http.StatusOK || rw.HeaderMap.Get("Etag") != etag {
+			t.Errorf("GET with If-None-Match %s returned %d with Etag %s, expected %d with Etag %s", inm, rw.Code, rw.HeaderMap.Get("Etag"), http.StatusOK, etag)
+		}
+	}
+
+	// sites with an index fetch the variant directly
+	if err := saveSitePaths(rs.Storage, tagPrefix(testTag), []string{
+		sitePathsLine("/variant.txt", []string{"gzip", "br", "zstd"}),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rs.Sites = new(siteCache)
+
+	counter := &countingStorage{storage: rs.Storage}
+	rs.Storage = counter
+
+	for _, test := range []struct {
+		accept string
+		body   []byte
+	}{
+		{"gzip", gzBuf.Bytes()},
+		{"br", brBuf.Bytes()},
+		{"gzip, zstd", zstdBuf.Bytes()},
+	} {
+		// load the index first
+		rs.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://"+testTag+".jekyllhistory.org/missing", nil))
+
+		req := httptest.NewRequest(http.MethodGet, "http://"+testTag+".jekyllhistory.org/variant.txt", nil)
+		req.Header.Set("Accept-Encoding", test.accept)
+
+		counter.lookups = 0
+
+		rw := httptest.NewRecorder()
+		rs.ServeHTTP(rw, req)
+
+		if !bytes.Equal(rw.Body.Bytes(), test.body) {
+			t.Errorf("GET with Accept-Encoding %q and an index returned wrong body", test.accept)
+		}
+
+		if counter.lookups != 1 {
+			t.Errorf("GET with Accept-Encoding %q and an index made %d storage lookups, expected 1", test.accept, counter.lookups)
+		}
+	}
+}
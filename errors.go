@@ -17,6 +17,9 @@ type errorResponseWriter struct {
 	http.ResponseWriter
 	Request *http.Request
 
+	// LogURL, if set, is linked to from the error page.
+	LogURL string
+
 	wroteHeader bool
 	didWrite    bool
 	skipWrite   bool
@@ -63,12 +66,21 @@ func (w *errorResponseWriter) WriteHeader(code int) {
 
 			description = fmt.Sprintf("%s Allowed verbs are %s.", description, allow)
 		}
+	case http.StatusRequestEntityTooLarge:
+		name = "Request Entity Too Large"
+		message = "The repository is too large to be built."
+	case http.StatusUnprocessableEntity:
+		name = "Unprocessable Entity"
+		message = "The request was understood but could not be completed."
 	case http.StatusInternalServerError:
 		name = "Internal Server Error"
 		message = "An internal server error has occurred."
 	case http.StatusBadGateway:
 		name = "Bad Gateway"
 		message = "The upstream failed or was unreachable."
+	case http.StatusServiceUnavailable:
+		name = "Service Unavailable"
+		message = "The server is too busy to handle this request. Please try again later."
 	default:
 		w.ResponseWriter.WriteHeader(code)
 		return
@@ -104,12 +116,14 @@ func (w *errorResponseWriter) WriteHeader(code int) {
 		Name        string
 		Message     string
 		Description string
+		LogURL      string
 		Padding     template.HTML
 	}{
 		Code:        code,
 		Name:        name,
 		Message:     message,
 		Description: description,
+		LogURL:      w.LogURL,
 		Padding:     padding,
 	}, w.ResponseWriter, code); err != nil {
 		log.Printf("%[1]T %[1]v", err)
@@ -133,6 +147,14 @@ func (w *errorResponseWriter) Write(p []byte) (int, error) {
 	return w.ResponseWriter.Write(p)
 }
 
+// setErrorLogURL links the error page, if one is
+// written, to the build log at url.
+func setErrorLogURL(w http.ResponseWriter, url string) {
+	if ew, ok := w.(*errorResponseWriter); ok {
+		ew.LogURL = url
+	}
+}
+
 type errorHandler struct {
 	http.Handler
 }
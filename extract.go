@@ -0,0 +1,327 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// extractLimits bounds the size of an extracted archive.
+// A zero limit is not enforced.
+type extractLimits struct {
+	// MaxBytes is the most data all files may hold.
+	MaxBytes int64
+	// MaxFiles is the most files and directories.
+	MaxFiles int64
+	// MaxFileSize is the most data any one file may hold.
+	MaxFileSize int64
+}
+
+// extractError is returned when an archive is rejected
+// by extractTarball.
+type extractError struct {
+	Code int
+	Err  error
+}
+
+func (e *extractError) Error() string {
+	return e.Err.Error()
+}
+
+// extractPath returns the path within dst that the
+// archive entry name is written to, with the top-level
+// directory removed. It returns false if the entry should
+// be skipped and an error if it would escape dst.
+func extractPath(dst, name string) (string, bool, error) {
+	idx := strings.IndexByte(name, '/')
+	if idx == -1 {
+		return "", false, nil
+	}
+
+	name = name[idx+1:]
+
+	for _, elem := range strings.Split(name, "/") {
+		if elem == ".." || strings.ContainsAny(elem, "\\\x00") {
+			return "", false, &extractError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  fmt.Errorf("tar file '%s' has an unsafe path", name),
+			}
+		}
+	}
+
+	path := filepath.Join(dst, filepath.FromSlash(name))
+
+	if !withinDir(dst, path) {
+		return "", false, &extractError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  fmt.Errorf("tar file '%s' has an unsafe path", name),
+		}
+	}
+
+	return path, true, nil
+}
+
+// withinDir reports whether the clean path is dir or
+// is inside of it.
+func withinDir(dir, path string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// resolveInTree follows any symlinks in path and returns
+// the result if it is inside root, which must not itself
+// contain symlinks.
+func resolveInTree(root, path string) (string, bool) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", false
+	}
+
+	return resolved, withinDir(root, resolved)
+}
+
+// extractTarball writes the entries of the tarball r,
+// each within a single top-level directory, to dst.
+// Symlinks are kept only if they resolve inside of dst.
+func extractTarball(r io.Reader, dst string, limits extractLimits) error {
+	dst = filepath.Clean(dst)
+
+	var files, size int64
+
+	// symlinks are created last so that no file is
+	// written through one
+	var symlinks []*tar.Header
+
+	tarReader := tar.NewReader(r)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		path, ok, err := extractPath(dst, header.Name)
+		if err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
+		if files++; limits.MaxFiles != 0 && files > limits.MaxFiles {
+			return &extractError{
+				Code: http.StatusRequestEntityTooLarge,
+				Err:  fmt.Errorf("archive has more than %d files", limits.MaxFiles),
+			}
+		}
+
+		info := header.FileInfo()
+		mode := info.Mode()
+
+		if info.IsDir() {
+			if err = os.MkdirAll(path, mode.Perm()|0700); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if header.Typeflag == tar.TypeSymlink {
+			if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+
+			symlinks = append(symlinks, header)
+			continue
+		}
+
+		if !mode.IsRegular() {
+			log.Printf("tar file '%s' has invalid mode: %d", header.Name, mode)
+			continue
+		}
+
+		if limits.MaxFileSize != 0 && header.Size > limits.MaxFileSize {
+			return &extractError{
+				Code: http.StatusRequestEntityTooLarge,
+				Err:  fmt.Errorf("tar file '%s' is larger than %d bytes", header.Name, limits.MaxFileSize),
+			}
+		}
+
+		if size += header.Size; limits.MaxBytes != 0 && size > limits.MaxBytes {
+			return &extractError{
+				Code: http.StatusRequestEntityTooLarge,
+				Err:  fmt.Errorf("archive is larger than %d bytes", limits.MaxBytes),
+			}
+		}
+
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm())
+		if err != nil {
+			return err
+		}
+
+		_, err = copyBuffer(file, tarReader)
+		file.Close()
+
+		if err != nil {
+			return err
+		}
+
+		if !header.ModTime.IsZero() && !header.ModTime.Equal(unixEpochTime) {
+			access := header.AccessTime
+
+			if access.IsZero() || access.Equal(unixEpochTime) {
+				access = time.Now()
+			}
+
+			if err := os.Chtimes(path, access, header.ModTime); err != nil {
+				log.Printf("%[1]T: %[1]v", err)
+			}
+		}
+	}
+
+	if len(symlinks) == 0 {
+		return nil
+	}
+
+	root, err := filepath.EvalSymlinks(dst)
+	if err != nil {
+		return err
+	}
+
+	created := make(map[string]*tar.Header, len(symlinks))
+
+	for _, header := range symlinks {
+		if path, ok := createSymlink(root, header); ok {
+			created[path] = header
+		}
+	}
+
+	// symlinks may point at one another, so they can only
+	// be resolved once all exist
+	for path, header := range created {
+		if _, ok := resolveInTree(root, path); ok {
+			continue
+		}
+
+		log.Printf("tar file '%s' does not resolve inside of the repository: %s", header.Name, header.Linkname)
+
+		if err := os.Remove(path); err != nil {
+			log.Printf("%[1]T: %[1]v", err)
+		}
+	}
+
+	return nil
+}
+
+// createSymlink creates the symlink described by header
+// within root and returns its path. Symlinks with targets
+// that are plainly outside of root are dropped.
+func createSymlink(root string, header *tar.Header) (string, bool) {
+	path, _, _ := extractPath(root, header.Name)
+
+	if filepath.IsAbs(header.Linkname) || !withinDir(root, filepath.Join(filepath.Dir(path), header.Linkname)) {
+		log.Printf("tar file '%s' links outside of the repository: %s", header.Name, header.Linkname)
+		return "", false
+	}
+
+	dir, ok := resolveInTree(root, filepath.Dir(path))
+	if !ok {
+		log.Printf("tar file '%s' is not inside of the repository", header.Name)
+		return "", false
+	}
+
+	path = filepath.Join(dir, filepath.Base(path))
+
+	if err := os.Symlink(header.Linkname, path); err != nil {
+		log.Printf("%[1]T: %[1]v", err)
+		return "", false
+	}
+
+	return path, true
+}
+
+// checkTree applies the same limits as extractTarball to
+// a tree that was written some other way, such as by git.
+// Symlinks that do not resolve inside of dst are removed.
+// The .git metadata of dst and its submodules is ignored.
+func checkTree(dst string, limits extractLimits) error {
+	root, err := filepath.EvalSymlinks(filepath.Clean(dst))
+	if err != nil {
+		return err
+	}
+
+	var files, size int64
+
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == root {
+			return nil
+		}
+
+		if info.Name() == ".git" {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		name, _ := filepath.Rel(root, path)
+		name = filepath.ToSlash(name)
+
+		if files++; limits.MaxFiles != 0 && files > limits.MaxFiles {
+			return &extractError{
+				Code: http.StatusRequestEntityTooLarge,
+				Err:  fmt.Errorf("repository has more than %d files", limits.MaxFiles),
+			}
+		}
+
+		mode := info.Mode()
+
+		switch {
+		case mode&os.ModeSymlink != 0:
+			if _, ok := resolveInTree(root, path); ok {
+				return nil
+			}
+
+			log.Printf("file '%s' does not resolve inside of the repository", name)
+			return os.Remove(path)
+		case !mode.IsRegular():
+			return nil
+		}
+
+		if limits.MaxFileSize != 0 && info.Size() > limits.MaxFileSize {
+			return &extractError{
+				Code: http.StatusRequestEntityTooLarge,
+				Err:  fmt.Errorf("file '%s' is larger than %d bytes", name, limits.MaxFileSize),
+			}
+		}
+
+		if size += info.Size(); limits.MaxBytes != 0 && size > limits.MaxBytes {
+			return &extractError{
+				Code: http.StatusRequestEntityTooLarge,
+				Err:  fmt.Errorf("repository is larger than %d bytes", limits.MaxBytes),
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,248 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testTarEntry struct {
+	Name string
+	Body string
+	Type byte
+	Link string
+}
+
+func newTestTarball(t *testing.T, entries ...testTarEntry) *bytes.Buffer {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	for _, entry := range entries {
+		hdr := &tar.Header{
+			Name:     entry.Name,
+			Mode:     0644,
+			Size:     int64(len(entry.Body)),
+			Typeflag: entry.Type,
+			Linkname: entry.Link,
+		}
+
+		if entry.Type == tar.TypeDir {
+			hdr.Mode = 0755
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := tw.Write([]byte(entry.Body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return &buf
+}
+
+func TestExtractTarball(t *testing.T) {
+	dir, err := ioutil.TempDir("", "extract")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	dst := filepath.Join(dir, "repo")
+
+	if err := extractTarball(newTestTarball(t,
+		testTarEntry{Name: "pax_global_header", Body: "x", Type: tar.TypeReg},
+		testTarEntry{Name: "repo-abc/", Type: tar.TypeDir},
+		testTarEntry{Name: "repo-abc/_posts/", Type: tar.TypeDir},
+		testTarEntry{Name: "repo-abc/_posts/post.md", Body: "# Post", Type: tar.TypeReg},
+		testTarEntry{Name: "repo-abc/index.md", Body: "# Hello", Type: tar.TypeReg},
+	), dst, extractLimits{MaxFiles: 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, body := range map[string]string{
+		"_posts/post.md": "# Post",
+		"index.md":       "# Hello",
+	} {
+		if b, err := ioutil.ReadFile(filepath.Join(dst, name)); err != nil {
+			t.Error(err)
+		} else if string(b) != body {
+			t.Errorf("%s contains %q, expected %q", name, b, body)
+		}
+	}
+}
+
+func TestExtractTarballRejects(t *testing.T) {
+	dir, err := ioutil.TempDir("", "extract")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	for _, test := range []struct {
+		entries []testTarEntry
+		limits  extractLimits
+		code    int
+	}{
+		{[]testTarEntry{{Name: "repo/../../escape", Body: "x"}}, extractLimits{}, http.StatusUnprocessableEntity},
+		{[]testTarEntry{{Name: "repo/a/../../escape", Body: "x"}}, extractLimits{}, http.StatusUnprocessableEntity},
+		{[]testTarEntry{{Name: "repo/..", Type: tar.TypeDir}}, extractLimits{}, http.StatusUnprocessableEntity},
+		{[]testTarEntry{{Name: "repo/a", Body: "12345"}}, extractLimits{MaxFileSize: 4}, http.StatusRequestEntityTooLarge},
+		{[]testTarEntry{{Name: "repo/a", Body: "123"}, {Name: "repo/b", Body: "123"}}, extractLimits{MaxBytes: 5}, http.StatusRequestEntityTooLarge},
+		{[]testTarEntry{{Name: "repo/a"}, {Name: "repo/b"}}, extractLimits{MaxFiles: 1}, http.StatusRequestEntityTooLarge},
+	} {
+		dst := filepath.Join(dir, "repo")
+
+		err := extractTarball(newTestTarball(t, test.entries...), dst, test.limits)
+		if eerr, ok := err.(*extractError); !ok {
+			t.Errorf("extractTarball(%v) returned %v, expected *extractError", test.entries, err)
+		} else if eerr.Code != test.code {
+			t.Errorf("extractTarball(%v) returned code %d, expected %d", test.entries, eerr.Code, test.code)
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, "escape")); !os.IsNotExist(err) {
+			t.Errorf("extractTarball(%v) wrote outside of destination", test.entries)
+		}
+
+		os.RemoveAll(dst)
+	}
+}
+
+func TestExtractTarballSymlinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "extract")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "secret"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "repo")
+
+	if err := extractTarball(newTestTarball(t,
+		testTarEntry{Name: "repo/_data/site.yml", Body: "title: Site", Type: tar.TypeReg},
+		testTarEntry{Name: "repo/chain", Type: tar.TypeSymlink, Link: "data.yml"},
+		testTarEntry{Name: "repo/data.yml", Type: tar.TypeSymlink, Link: "_data/site.yml"},
+		testTarEntry{Name: "repo/_includes", Type: tar.TypeSymlink, Link: "_data"},
+		testTarEntry{Name: "repo/absolute", Type: tar.TypeSymlink, Link: filepath.Join(dir, "secret")},
+		testTarEntry{Name: "repo/relative", Type: tar.TypeSymlink, Link: "../secret"},
+		testTarEntry{Name: "repo/_data/up", Type: tar.TypeSymlink, Link: ".."},
+		testTarEntry{Name: "repo/indirect", Type: tar.TypeSymlink, Link: "_data/up/../secret"},
+		testTarEntry{Name: "repo/dangling", Type: tar.TypeSymlink, Link: "missing"},
+	), dst, extractLimits{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, body := range map[string]string{
+		"chain":              "title: Site",
+		"data.yml":           "title: Site",
+		"_includes/site.yml": "title: Site",
+	} {
+		if b, err := ioutil.ReadFile(filepath.Join(dst, name)); err != nil {
+			t.Error(err)
+		} else if string(b) != body {
+			t.Errorf("%s contains %q, expected %q", name, b, body)
+		}
+	}
+
+	for _, name := range []string{"absolute", "relative", "indirect", "dangling"} {
+		if _, err := os.Lstat(filepath.Join(dst, name)); !os.IsNotExist(err) {
+			t.Errorf("%s was not dropped", name)
+		}
+	}
+}
+
+func TestCheckTree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "check-tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "secret"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "repo")
+
+	for name, body := range map[string]string{
+		"_data/site.yml": "title: Site",
+		"index.md":       "# Hello",
+		".git/config":    "[core]",
+	} {
+		path := filepath.Join(dst, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, link := range map[string]string{
+		"data.yml": "_data/site.yml",
+		"absolute": filepath.Join(dir, "secret"),
+		"relative": "../secret",
+		"_data/up": "..",
+		"indirect": "_data/up/../secret",
+		"dangling": "missing",
+	} {
+		if err := os.Symlink(link, filepath.Join(dst, filepath.FromSlash(name))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, limits := range []extractLimits{
+		{MaxFiles: 4},
+		{MaxFileSize: 10},
+		{MaxBytes: 15},
+	} {
+		if err := checkTree(dst, limits); err == nil {
+			t.Errorf("checkTree with limits %+v succeeded", limits)
+		} else if eerr, ok := err.(*extractError); !ok || eerr.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("checkTree with limits %+v returned %v, expected an *extractError", limits, err)
+		}
+	}
+
+	if err := checkTree(dst, extractLimits{
+		MaxFiles:    10,
+		MaxFileSize: 11,
+		MaxBytes:    18,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"data.yml", "_data/up"} {
+		if _, err := os.Lstat(filepath.Join(dst, filepath.FromSlash(name))); err != nil {
+			t.Errorf("%s was dropped: %v", name, err)
+		}
+	}
+
+	for _, name := range []string{"absolute", "relative", "indirect", "dangling"} {
+		if _, err := os.Lstat(filepath.Join(dst, name)); !os.IsNotExist(err) {
+			t.Errorf("%s was not dropped", name)
+		}
+	}
+}
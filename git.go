@@ -0,0 +1,224 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// gitSource is implemented by source providers whose
+// repositories can be cloned with git.
+type gitSource interface {
+	CloneURL(user, repo string) string
+
+	// CloneAuthorization returns the Authorization header
+	// sent to the host of the clone URLs, or an empty
+	// string if none is needed.
+	CloneAuthorization() (string, error)
+}
+
+func gitCommand(ctx context.Context, dir string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	return cmd
+}
+
+// gitAuthEnv returns the environment that has git, and Git
+// LFS, send auth to the host of remote. It is passed in the
+// environment rather than the arguments so that it is not
+// seen by other users.
+func gitAuthEnv(remote, auth string) []string {
+	u, err := url.Parse(remote)
+	if len(auth) == 0 || err != nil || len(u.Host) == 0 {
+		return nil
+	}
+
+	scope := (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}).String()
+
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http." + scope + ".extraheader",
+		"GIT_CONFIG_VALUE_0=Authorization: " + auth,
+	}
+}
+
+// runGit runs git in dir with its output written to out.
+func runGit(ctx context.Context, dir string, out io.Writer, args ...string) error {
+	return runGitEnv(ctx, dir, nil, out, args...)
+}
+
+// runGitEnv is runGit with env added to the environment.
+func runGitEnv(ctx context.Context, dir string, env []string, out io.Writer, args ...string) error {
+	cmd := gitCommand(ctx, dir, args...)
+	cmd.Env = append(cmd.Env, env...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s: %v", args[0], err)
+	}
+
+	return nil
+}
+
+// gitMirror keeps a mirror clone of each remote under Dir
+// so that later builds only fetch new objects.
+type gitMirror struct {
+	Dir string
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func (m *gitMirror) lock(name string) *sync.Mutex {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.locks == nil {
+		m.locks = make(map[string]*sync.Mutex)
+	}
+
+	l, ok := m.locks[name]
+	if !ok {
+		l = new(sync.Mutex)
+		m.locks[name] = l
+	}
+
+	return l
+}
+
+// Checkout checks out commit from remote, along with its
+// submodules and any Git LFS content, into dst. auth, if
+// not empty, is sent as the Authorization header to the
+// host of remote.
+//
+// It returns a *sourceNotFoundError if the remote does not
+// contain commit.
+func (m *gitMirror) Checkout(ctx context.Context, remote, auth, commit, dst string, out io.Writer) error {
+	rawName := sha256.Sum256([]byte(remote))
+	name := hex.EncodeToString(rawName[:16]) + ".git"
+
+	l := m.lock(name)
+	l.Lock()
+	defer l.Unlock()
+
+	mirror := filepath.Join(m.Dir, name)
+
+	env := gitAuthEnv(remote, auth)
+
+	if err := m.update(ctx, remote, env, mirror, commit, out); err != nil {
+		return err
+	}
+
+	if err := runGit(ctx, "", out, "clone", "--quiet", "--shared", "--no-checkout", mirror, dst); err != nil {
+		return err
+	}
+
+	// relative submodule URLs are resolved against origin
+	if err := runGit(ctx, dst, out, "remote", "set-url", "origin", remote); err != nil {
+		return err
+	}
+
+	if err := runGit(ctx, dst, out, "checkout", "--quiet", "--detach", commit); err != nil {
+		return err
+	}
+
+	submoduleArgs := []string{"submodule", "update", "--quiet", "--init", "--recursive"}
+
+	// only allow submodules on the local filesystem when
+	// building from a local repository
+	if filepath.IsAbs(remote) {
+		submoduleArgs = append([]string{"-c", "protocol.file.allow=always"}, submoduleArgs...)
+	}
+
+	if err := runGitEnv(ctx, dst, env, out, submoduleArgs...); err != nil {
+		return err
+	}
+
+	if usesGitLFS(dst) {
+		return runGitEnv(ctx, dst, env, out, "lfs", "pull")
+	}
+
+	return nil
+}
+
+func (m *gitMirror) update(ctx context.Context, remote string, env []string, mirror, commit string, out io.Writer) error {
+	if _, err := os.Stat(mirror); os.IsNotExist(err) {
+		if err := os.MkdirAll(m.Dir, 0755); err != nil {
+			return err
+		}
+
+		tmp, err := ioutil.TempDir(m.Dir, ".clone-")
+		if err != nil {
+			return err
+		}
+
+		defer os.RemoveAll(tmp)
+
+		if err := runGitEnv(ctx, "", env, out, "clone", "--quiet", "--mirror", remote, tmp); err != nil {
+			return err
+		}
+
+		// objects must not be removed from under clones
+		// that share them
+		if err := runGit(ctx, tmp, out, "config", "gc.auto", "0"); err != nil {
+			return err
+		}
+
+		if err := os.Rename(tmp, mirror); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	} else if hasGitCommit(ctx, mirror, commit) {
+		return nil
+	} else if err := runGitEnv(ctx, mirror, env, out, "fetch", "--quiet", "--prune", "origin"); err != nil {
+		return err
+	}
+
+	if !hasGitCommit(ctx, mirror, commit) {
+		return &sourceNotFoundError{fmt.Errorf("commit %s not found in %s", commit, remote)}
+	}
+
+	return nil
+}
+
+func hasGitCommit(ctx context.Context, dir, commit string) bool {
+	return gitCommand(ctx, dir, "cat-file", "-e", commit+"^{commit}").Run() == nil
+}
+
+func usesGitLFS(dir string) bool {
+	attrs, err := ioutil.ReadFile(filepath.Join(dir, ".gitattributes"))
+	return err == nil && bytes.Contains(attrs, []byte("filter=lfs"))
+}
+
+// gitOutput runs git in dir and returns its standard output.
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+
+	cmd := gitCommand(ctx, dir, args...)
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return string(stdout), nil
+}
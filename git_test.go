@@ -0,0 +1,305 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testGit(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_NOSYSTEM=1", "HOME="+dir)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// newTestGitRepo creates a bare repository at root/user/repo.git
+// with a single commit containing files and returns the commit.
+func newTestGitRepo(t *testing.T, root, user, repo string, files map[string]string, args ...[]string) string {
+	work, err := ioutil.TempDir("", "git-work")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(work)
+
+	testGit(t, work, "init", "--quiet")
+
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(work, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, arg := range args {
+		testGit(t, work, arg...)
+	}
+
+	testGit(t, work, "add", ".")
+	testGit(t, work, "commit", "--quiet", "-m", "Initial commit")
+
+	bare := filepath.Join(root, user, repo+".git")
+	testGit(t, work, "clone", "--quiet", "--bare", work, bare)
+
+	return testGit(t, work, "rev-parse", "HEAD")
+}
+
+func TestGitMirrorCheckout(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err := ioutil.TempDir("", "git-mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	newTestGitRepo(t, root, "user", "theme", map[string]string{
+		"theme.css": "body {}",
+	})
+
+	themeRemote := filepath.Join(root, "user", "theme.git")
+
+	commit := newTestGitRepo(t, root, "user", "site", map[string]string{
+		"index.md": "# Hello",
+	}, []string{"-c", "protocol.file.allow=always", "submodule", "add", "--quiet", themeRemote, "theme"})
+
+	mirror := &gitMirror{
+		Dir: filepath.Join(root, "mirrors"),
+	}
+
+	remote := filepath.Join(root, "user", "site.git")
+
+	for i, name := range []string{"a", "b"} {
+		dst := filepath.Join(root, "checkout", name)
+
+		if err := mirror.Checkout(context.Background(), remote, "", commit, dst, ioutil.Discard); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, name := range []string{"index.md", "theme/theme.css"} {
+			if _, err := os.Stat(filepath.Join(dst, name)); err != nil {
+				t.Errorf("checkout %d: %v", i, err)
+			}
+		}
+	}
+
+	err = mirror.Checkout(context.Background(), remote, "", testSHA, filepath.Join(root, "checkout", "c"), ioutil.Discard)
+	if !isSourceNotFound(err) {
+		t.Errorf("expected sourceNotFoundError for missing commit, got %v", err)
+	}
+}
+
+func TestLocalSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err := ioutil.TempDir("", "local-source")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	commit := newTestGitRepo(t, root, "user", "site", map[string]string{
+		"index.md": "# Hello",
+	})
+
+	ls, err := newLocalSource("local", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	repos, _, err := ls.ListRepos(ctx, "user", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repos) != 1 || repos[0].Name != "site" {
+		t.Errorf("ListRepos returned %v", repos)
+	}
+
+	if _, _, err := ls.ListRepos(ctx, "..", 1); !isSourceNotFound(err) {
+		t.Errorf("expected sourceNotFoundError for invalid user, got %v", err)
+	}
+
+	sha, err := ls.ResolveCommit(ctx, "user", "site", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sha != commit {
+		t.Errorf("ResolveCommit returned %s, expected %s", sha, commit)
+	}
+
+	scommit, err := ls.GetCommit(ctx, "user", "site", commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if scommit.Message != "Initial commit" || scommit.AuthorName != "Test" {
+		t.Errorf("GetCommit returned %+v", scommit)
+	}
+
+	if len(scommit.Files) != 1 || scommit.Files[0].Filename != "index.md" || scommit.Files[0].Status != "added" {
+		t.Errorf("GetCommit returned files %+v", scommit.Files)
+	}
+
+	archive, err := ls.Archive(ctx, "user", "site", commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer archive.Close()
+
+	gzr, err := gzip.NewReader(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+
+		if hdr.Typeflag != tar.TypeXGlobalHeader {
+			names = append(names, hdr.Name)
+		}
+	}
+
+	if strings.Join(names, ",") != "site/,site/index.md" {
+		t.Errorf("Archive contained %v", names)
+	}
+
+	if url := ls.CloneURL("user", "site"); url != filepath.Join(root, "user", "site.git") {
+		t.Errorf("CloneURL returned %s", url)
+	}
+}
+
+func TestLocalSourceRefs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err := ioutil.TempDir("", "local-source")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	commit := newTestGitRepo(t, root, "user", "site", map[string]string{
+		"index.md": "# Hello",
+	})
+
+	bare := filepath.Join(root, "user", "site.git")
+	testGit(t, bare, "branch", "feature/x", commit)
+	testGit(t, bare, "tag", "-a", "-m", "Version 1", "v1", commit)
+	testGit(t, bare, "tag", "v2", commit)
+
+	ls, err := newLocalSource("local", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	for _, test := range []struct {
+		list   func(ctx context.Context, user, repo string) ([]*sourceRef, bool, error)
+		expect []string
+	}{
+		{ls.ListBranches, []string{"feature/x", "master"}},
+		{ls.ListTags, []string{"v1", "v2"}},
+	} {
+		refs, more, err := test.list(ctx, "user", "site")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if more {
+			t.Error("listing refs reported more than were listed")
+		}
+
+		sortRefs(refs)
+
+		var names []string
+
+		for _, ref := range refs {
+			names = append(names, ref.Name)
+
+			if ref.SHA != commit || ref.Date.IsZero() {
+				t.Errorf("ref %s points to %s at %s, expected %s", ref.Name, ref.SHA, ref.Date, commit)
+			}
+		}
+
+		if strings.Join(names, ",") != strings.Join(test.expect, ",") {
+			t.Errorf("listed refs %v, expected %v", names, test.expect)
+		}
+	}
+}
+
+func TestGitAuthEnv(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	gs := &githubSource{Credentials: githubToken("secret")}
+
+	auth, err := gs.CloneAuthorization()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := gitAuthEnv("https://github.com/user/repo.git", auth)
+
+	// the header is only sent to the host of the remote
+	for _, test := range []struct {
+		url, header string
+	}{
+		{"https://github.com/user/repo.git", "Authorization: " + auth},
+		{"https://github.com/other/submodule.git", "Authorization: " + auth},
+		{"https://example.com/user/repo.git", ""},
+		{"http://github.com/user/repo.git", ""},
+	} {
+		cmd := gitCommand(context.Background(), "", "config", "--get-urlmatch", "http.extraheader", test.url)
+		cmd.Env = append(cmd.Env, env...)
+
+		out, _ := cmd.Output()
+		if header := strings.TrimSpace(string(out)); header != test.header {
+			t.Errorf("git sends %q to %s, expected %q", header, test.url, test.header)
+		}
+	}
+
+	if env := gitAuthEnv(filepath.Join("srv", "git", "repo.git"), auth); env != nil {
+		t.Errorf("gitAuthEnv returned %q for a local path", env)
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// githubCredentials returns the value of the Authorization
+// header sent to GitHub.
+type githubCredentials interface {
+	Authorization() (string, error)
+}
+
+// githubToken is a personal access token.
+type githubToken string
+
+func (t githubToken) Authorization() (string, error) {
+	return "token " + string(t), nil
+}
+
+// githubApp authenticates as an installation of a GitHub
+// App. Installation tokens are requested with a JWT signed
+// by the App's private key and are refreshed before they
+// expire.
+type githubApp struct {
+	ID             int64
+	InstallationID int64
+	Key            *rsa.PrivateKey
+
+	// BaseURL is the URL of the GitHub API, with a
+	// trailing slash.
+	BaseURL string
+
+	Client *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+	refresh *githubAppRefresh
+}
+
+// githubAppTokenTimeout is how long requesting an
+// installation token may take.
+const githubAppTokenTimeout = 30 * time.Second
+
+// githubAppRefresh is a request for an installation token
+// that other callers wait on rather than making their own.
+type githubAppRefresh struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+func parseGithubAppKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("github app private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("github app private key is not an RSA key")
+	}
+
+	return rsaKey, nil
+}
+
+// jwt returns a JSON Web Token, signed with RS256, that
+// identifies the App.
+func (app *githubApp) jwt(now time.Time) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+
+	claims, err := json.Marshal(struct {
+		IssuedAt  int64 `json:"iat"`
+		ExpiresAt int64 `json:"exp"`
+		Issuer    int64 `json:"iss"`
+	}{
+		// allow for clock drift
+		IssuedAt:  now.Add(-time.Minute).Unix(),
+		ExpiresAt: now.Add(9 * time.Minute).Unix(),
+		Issuer:    app.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signed := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	hashed := sha256.Sum256([]byte(signed))
+
+	sig, err := rsa.SignPKCS1v15(rand.Reader, app.Key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func (app *githubApp) Authorization() (string, error) {
+	app.mu.Lock()
+
+	if len(app.token) != 0 && time.Now().Add(time.Minute).Before(app.expires) {
+		token := app.token
+		app.mu.Unlock()
+		return "token " + token, nil
+	}
+
+	// the lock is not held while the token is requested so
+	// that a slow response only holds up callers that need
+	// the new token
+	if refresh := app.refresh; refresh != nil {
+		app.mu.Unlock()
+		<-refresh.done
+
+		if refresh.err != nil {
+			return "", refresh.err
+		}
+
+		return "token " + refresh.token, nil
+	}
+
+	refresh := &githubAppRefresh{done: make(chan struct{})}
+	app.refresh = refresh
+	app.mu.Unlock()
+
+	var expires time.Time
+	refresh.token, expires, refresh.err = app.requestToken()
+
+	app.mu.Lock()
+	if refresh.err == nil {
+		app.token, app.expires = refresh.token, expires
+	}
+
+	app.refresh = nil
+	app.mu.Unlock()
+
+	close(refresh.done)
+
+	if refresh.err != nil {
+		return "", refresh.err
+	}
+
+	return "token " + refresh.token, nil
+}
+
+// requestToken requests a new installation token.
+func (app *githubApp) requestToken() (string, time.Time, error) {
+	now := time.Now()
+
+	jwt, err := app.jwt(now)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, app.BaseURL+"app/installations/"+strconv.FormatInt(app.InstallationID, 10)+"/access_tokens", nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.machine-man-preview+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("User-Agent", fullVersionStr)
+
+	client := app.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), githubAppTokenTimeout)
+	defer cancel()
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", time.Time{}, fmt.Errorf("github app installation token request failed with status %s", resp.Status)
+	}
+
+	var token struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token.Token, token.ExpiresAt, nil
+}
+
+// githubAuthTransport adds credentials to requests sent to
+// Hosts.
+type githubAuthTransport struct {
+	Credentials githubCredentials
+	Hosts       []string
+
+	Transport http.RoundTripper
+}
+
+func (t *githubAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if !t.sendsTo(req.URL.Host) || len(req.Header.Get("Authorization")) != 0 {
+		return transport.RoundTrip(req)
+	}
+
+	auth, err := t.Credentials.Authorization()
+	if err != nil {
+		return nil, err
+	}
+
+	// a RoundTripper must not modify the request
+	req2 := new(http.Request)
+	*req2 = *req
+
+	req2.Header = make(http.Header, len(req.Header)+1)
+	for k, v := range req.Header {
+		req2.Header[k] = v
+	}
+
+	req2.Header.Set("Authorization", auth)
+
+	return transport.RoundTrip(req2)
+}
+
+func (t *githubAuthTransport) sendsTo(host string) bool {
+	for _, h := range t.Hosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+
+	return false
+}
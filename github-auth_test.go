@@ -0,0 +1,236 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGithubApp(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requests int32
+	expiresIn := time.Hour
+	release := make(chan struct{})
+	close(release)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		<-release
+
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/42/access_tokens" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+
+		parts := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), ".")
+		if len(parts) != 3 {
+			t.Fatalf("invalid JWT %q", r.Header.Get("Authorization"))
+		}
+
+		hashed := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+
+		sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hashed[:], sig); err != nil {
+			t.Errorf("invalid JWT signature: %v", err)
+		}
+
+		var claims struct {
+			IssuedAt  int64 `json:"iat"`
+			ExpiresAt int64 `json:"exp"`
+			Issuer    int64 `json:"iss"`
+		}
+
+		data, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		if err := json.Unmarshal(data, &claims); err != nil {
+			t.Error(err)
+		}
+
+		if claims.Issuer != 7 || claims.ExpiresAt-claims.IssuedAt > 10*60 {
+			t.Errorf("unexpected JWT claims %+v", claims)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token":"v1.token%d","expires_at":%q}`, n, time.Now().Add(expiresIn).Format(time.RFC3339))
+	}))
+	defer ts.Close()
+
+	app := &githubApp{
+		ID:             7,
+		InstallationID: 42,
+		Key:            key,
+
+		BaseURL: ts.URL + "/",
+	}
+
+	for _, expect := range []string{"token v1.token1", "token v1.token1"} {
+		if auth, err := app.Authorization(); err != nil {
+			t.Fatal(err)
+		} else if auth != expect {
+			t.Errorf("Authorization returned %q, expected %q", auth, expect)
+		}
+	}
+
+	// tokens are refreshed before they expire
+	app.expires = time.Now().Add(30 * time.Second)
+
+	if auth, err := app.Authorization(); err != nil {
+		t.Fatal(err)
+	} else if auth != "token v1.token2" {
+		t.Errorf("Authorization returned %q, expected %q", auth, "token v1.token2")
+	}
+
+	// callers wait on one request for a new token
+	app.expires = time.Time{}
+	release = make(chan struct{})
+
+	var wg sync.WaitGroup
+	auths := make([]string, 4)
+
+	for i := range auths {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			var err error
+			if auths[i], err = app.Authorization(); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for _, auth := range auths {
+		if auth != "token v1.token3" {
+			t.Errorf("Authorization returned %q, expected %q", auth, "token v1.token3")
+		}
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Errorf("%d tokens were requested, expected 3", n)
+	}
+}
+
+func TestGithubAuthTransport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer ts.Close()
+
+	for _, test := range []struct {
+		hosts  []string
+		expect string
+	}{
+		{[]string{strings.TrimPrefix(ts.URL, "http://")}, "token secret"},
+		{[]string{"api.github.com", "codeload.github.com"}, ""},
+	} {
+		client := &http.Client{
+			Transport: &githubAuthTransport{
+				Credentials: githubToken("secret"),
+				Hosts:       test.hosts,
+			},
+		}
+
+		resp, err := client.Get(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(body) != test.expect {
+			t.Errorf("server received Authorization %q with hosts %v, expected %q", body, test.hosts, test.expect)
+		}
+	}
+}
+
+func TestParseGithubURLs(t *testing.T) {
+	for _, test := range []struct {
+		web, api, upload string
+
+		expectWeb, expectAPI, expectUpload string
+	}{
+		{"", "", "", "https://github.com", "https://api.github.com/", "https://uploads.github.com/"},
+		{"https://ghe.example.com/", "", "", "https://ghe.example.com", "https://ghe.example.com/api/v3/", "https://ghe.example.com/api/uploads/"},
+		{"https://ghe.example.com", "https://api.ghe.example.com", "", "https://ghe.example.com", "https://api.ghe.example.com/", "https://ghe.example.com/api/uploads/"},
+	} {
+		urls, err := parseGithubURLs(test.web, test.api, test.upload)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if urls.Web.String() != test.expectWeb || urls.API.String() != test.expectAPI || urls.Upload.String() != test.expectUpload {
+			t.Errorf("parseGithubURLs(%q, %q, %q) = %s, %s, %s", test.web, test.api, test.upload, urls.Web, urls.API, urls.Upload)
+		}
+	}
+
+	if _, err := parseGithubURLs("ghe.example.com", "", ""); err == nil {
+		t.Error("parseGithubURLs accepted a relative URL")
+	}
+}
+
+func TestGithubSourceEnterprise(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/user/repo/commits/master" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Write([]byte(testSHA))
+	}))
+	defer ts.Close()
+
+	urls, err := parseGithubURLs(ts.URL, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gs, err := getGithubSource(urls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sha, err := gs.ResolveCommit(context.Background(), "user", "repo", "master"); err != nil {
+		t.Error(err)
+	} else if sha != testSHA {
+		t.Errorf("ResolveCommit returned %s, expected %s", sha, testSHA)
+	}
+
+	if host := gs.WebHost(); host != urls.Web.Host {
+		t.Errorf("WebHost returned %s, expected %s", host, urls.Web.Host)
+	}
+
+	if u := gs.CommitURL("user", "repo", testSHA); u != ts.URL+"/user/repo/commit/"+testSHA {
+		t.Errorf("CommitURL returned %s", u)
+	}
+}
@@ -7,18 +7,162 @@ package main
 
 import (
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/google/go-github/github"
 	"github.com/gregjones/httpcache"
 )
 
-func getGithubClient() (*github.Client, error) {
+// githubURLs are the URLs of a GitHub or GitHub Enterprise
+// instance.
+type githubURLs struct {
+	Web    *url.URL
+	API    *url.URL
+	Upload *url.URL
+}
+
+// parseGithubURLs parses the URLs of a GitHub instance. An
+// empty web URL means github.com and empty API and upload
+// URLs are derived from the web URL as GitHub Enterprise
+// serves them.
+func parseGithubURLs(web, api, upload string) (*githubURLs, error) {
+	if len(web) == 0 {
+		web = "https://github.com/"
+
+		if len(api) == 0 {
+			api = "https://api.github.com/"
+		}
+
+		if len(upload) == 0 {
+			upload = "https://uploads.github.com/"
+		}
+	}
+
+	web = strings.TrimSuffix(web, "/")
+
+	if len(api) == 0 {
+		api = web + "/api/v3/"
+	}
+
+	if len(upload) == 0 {
+		upload = web + "/api/uploads/"
+	}
+
+	var urls githubURLs
+
+	for _, u := range []struct {
+		dst **url.URL
+		raw string
+	}{
+		{&urls.Web, web},
+		{&urls.API, api},
+		{&urls.Upload, upload},
+	} {
+		parsed, err := url.Parse(u.raw)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(parsed.Scheme) == 0 || len(parsed.Host) == 0 {
+			return nil, fmt.Errorf("GitHub URL '%s' is not absolute", u.raw)
+		}
+
+		// go-github requires a trailing slash
+		if u.dst != &urls.Web && !strings.HasSuffix(parsed.Path, "/") {
+			parsed.Path += "/"
+		}
+
+		*u.dst = parsed
+	}
+
+	return &urls, nil
+}
+
+// Hosts returns the hosts that GitHub credentials are
+// sent to.
+func (urls *githubURLs) Hosts() []string {
+	hosts := []string{urls.API.Host, urls.Upload.Host, urls.Web.Host}
+
+	// github.com archives are served from codeload
+	if urls.Web.Host == "github.com" {
+		hosts = append(hosts, "codeload.github.com")
+	}
+
+	return hosts
+}
+
+// getGithubCredentials returns the credentials set in the
+// environment, either a personal access token or a GitHub
+// App installation, or nil if there are none.
+func getGithubCredentials(apiURL string) (githubCredentials, error) {
+	token := os.Getenv("GITHUB_TOKEN")
+
+	appID := os.Getenv("GITHUB_APP_ID")
+	if len(appID) == 0 {
+		if len(token) == 0 {
+			return nil, nil
+		}
+
+		return githubToken(token), nil
+	}
+
+	if len(token) != 0 {
+		return nil, errors.New("only one of GITHUB_TOKEN and GITHUB_APP_ID may be set")
+	}
+
+	app := &githubApp{
+		BaseURL: apiURL,
+	}
+
+	var err error
+
+	if app.ID, err = strconv.ParseInt(appID, 10, 64); err != nil {
+		return nil, err
+	}
+
+	if app.InstallationID, err = strconv.ParseInt(os.Getenv("GITHUB_APP_INSTALLATION_ID"), 10, 64); err != nil {
+		return nil, errors.New("GITHUB_APP_INSTALLATION_ID must be set with GITHUB_APP_ID")
+	}
+
+	key := []byte(os.Getenv("GITHUB_APP_PRIVATE_KEY"))
+	if path := os.Getenv("GITHUB_APP_PRIVATE_KEY_FILE"); len(key) == 0 && len(path) != 0 {
+		if key, err = ioutil.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(key) == 0 {
+		return nil, errors.New("GITHUB_APP_PRIVATE_KEY or GITHUB_APP_PRIVATE_KEY_FILE must be set with GITHUB_APP_ID")
+	}
+
+	if app.Key, err = parseGithubAppKey(key); err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+func getGithubSource(urls *githubURLs) (*githubSource, error) {
 	transport := httpcache.NewMemoryCacheTransport()
 	transport.MarkCachedResponses = true
 
+	creds, err := getGithubCredentials(urls.API.String())
+	if err != nil {
+		return nil, err
+	}
+
 	id := os.Getenv("GITHUB_CLIENT_ID")
 	if secret := os.Getenv("GITHUB_CLIENT_SECRET"); len(id) != 0 && len(secret) != 0 {
+		if creds != nil {
+			return nil, errors.New("GITHUB_CLIENT_ID and GITHUB_CLIENT_SECRET may not be set with other GitHub credentials")
+		}
+
 		transport.Transport = &github.UnauthenticatedRateLimitedTransport{
 			ClientID:     id,
 			ClientSecret: secret,
@@ -27,8 +171,31 @@ func getGithubClient() (*github.Client, error) {
 		return nil, errors.New("both GITHUB_CLIENT_ID and GITHUB_CLIENT_SECRET must be set")
 	}
 
-	client := github.NewClient(transport.Client())
-	client.UserAgent = fullVersionStr
+	source := new(githubSource)
+
+	if creds != nil {
+		transport.Transport = &githubAuthTransport{
+			Credentials: creds,
+			Hosts:       urls.Hosts(),
+		}
+
+		source.Credentials = creds
+		source.HTTPClient = &http.Client{
+			Transport: &githubAuthTransport{
+				Credentials: creds,
+				Hosts:       urls.Hosts(),
+			},
+		}
+	}
+
+	source.Client = github.NewClient(transport.Client())
+	source.Client.UserAgent = fullVersionStr
+	source.Client.BaseURL = urls.API
+	source.Client.UploadURL = urls.Upload
+
+	if urls.Web.Host != "github.com" {
+		source.Web = urls.Web
+	}
 
-	return client, nil
+	return source, nil
 }
@@ -0,0 +1,203 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// maxHookPayload is the largest webhook payload GitHub
+// sends.
+const maxHookPayload = 25 << 20
+
+// githubHookConfig configures the GitHub webhook receiver.
+type githubHookConfig struct {
+	Secret string
+
+	// Repos lists the owner/repo patterns, as matched by
+	// path.Match, of the repositories that may trigger
+	// builds.
+	Repos []string
+}
+
+func (hc *githubHookConfig) allowed(fullName string) bool {
+	for _, pattern := range hc.Repos {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(fullName)); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verify reports whether body is signed with Secret. The
+// SHA-256 signature is preferred if GitHub sent one.
+func (hc *githubHookConfig) verify(r *http.Request, body []byte) bool {
+	var sig string
+	var hashFn func() hash.Hash
+
+	if sig = r.Header.Get("X-Hub-Signature-256"); strings.HasPrefix(sig, "sha256=") {
+		sig, hashFn = sig[len("sha256="):], sha256.New
+	} else if sig = r.Header.Get("X-Hub-Signature"); strings.HasPrefix(sig, "sha1=") {
+		sig, hashFn = sig[len("sha1="):], sha1.New
+	} else {
+		return false
+	}
+
+	expected, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(hashFn, []byte(hc.Secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+type githubHookRepo struct {
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	Owner    struct {
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	} `json:"owner"`
+}
+
+func (repo *githubHookRepo) owner() string {
+	// push events name the owner rather than give a login
+	if len(repo.Owner.Login) != 0 {
+		return repo.Owner.Login
+	}
+
+	return repo.Owner.Name
+}
+
+type githubHookBuild struct {
+	User, Repo, Commit string
+
+	// BaseUser and BaseRepo are the repository a pull
+	// request was opened on, which may differ from the
+	// fork it is built from.
+	BaseUser, BaseRepo string
+}
+
+// parseGithubHook returns the repository that sent the
+// event and the commit to build, if there is one.
+func parseGithubHook(event string, body []byte) (*githubHookRepo, *githubHookBuild, error) {
+	switch event {
+	case "push":
+		var push struct {
+			After      string         `json:"after"`
+			Deleted    bool           `json:"deleted"`
+			Repository githubHookRepo `json:"repository"`
+		}
+
+		if err := json.Unmarshal(body, &push); err != nil {
+			return nil, nil, err
+		}
+
+		if push.Deleted || !isCommitSHA(push.After) {
+			return &push.Repository, nil, nil
+		}
+
+		return &push.Repository, &githubHookBuild{push.Repository.owner(), push.Repository.Name, push.After, "", ""}, nil
+	case "pull_request":
+		var pr struct {
+			Action      string `json:"action"`
+			PullRequest struct {
+				Head struct {
+					SHA  string          `json:"sha"`
+					Repo *githubHookRepo `json:"repo"`
+				} `json:"head"`
+			} `json:"pull_request"`
+			Repository githubHookRepo `json:"repository"`
+		}
+
+		if err := json.Unmarshal(body, &pr); err != nil {
+			return nil, nil, err
+		}
+
+		switch pr.Action {
+		case "opened", "reopened", "synchronize":
+		default:
+			return &pr.Repository, nil, nil
+		}
+
+		// the head repository is gone if the fork was deleted
+		head := pr.PullRequest.Head
+		if head.Repo == nil || !isCommitSHA(head.SHA) {
+			return &pr.Repository, nil, nil
+		}
+
+		return &pr.Repository, &githubHookBuild{head.Repo.owner(), head.Repo.Name, head.SHA, pr.Repository.owner(), pr.Repository.Name}, nil
+	default:
+		return nil, nil, nil
+	}
+}
+
+func getGithubHookHandler(config *githubHookConfig, sources sourceRegistry, queue *buildQueue) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxHookPayload))
+		if err != nil {
+			log.Printf("%[1]T: %[1]v", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if !config.verify(r, body) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		event := r.Header.Get("X-GitHub-Event")
+
+		repo, build, err := parseGithubHook(event, body)
+		if err != nil {
+			log.Printf("%[1]T: %[1]v", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if repo == nil || build == nil {
+			// ping and other events are acknowledged
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if !config.allowed(repo.FullName) {
+			log.Printf("ignoring %s event from %s: repository is not allowed", event, repo.FullName)
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		source := sources[defaultSourceName]
+
+		if _, err := queue.EnqueueFor(source.Name(), build.User, build.Repo, build.Commit, build.BaseUser, build.BaseRepo); err != nil {
+			log.Println(err)
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+
+		if verbose {
+			log.Printf("queued build of %s/%s@%s from %s event", build.User, build.Repo, build.Commit, event)
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func signGithubHook(r *http.Request, secret, body string) {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	r.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestGithubHookVerify(t *testing.T) {
+	config := &githubHookConfig{Secret: "secret"}
+	body := []byte(`{"zen":"Keep it logically awesome."}`)
+
+	sha1Mac := hmac.New(sha1.New, []byte("secret"))
+	sha1Mac.Write(body)
+
+	sha256Mac := hmac.New(sha256.New, []byte("secret"))
+	sha256Mac.Write(body)
+
+	for _, test := range []struct {
+		header, value string
+		expect        bool
+	}{
+		{"X-Hub-Signature", "sha1=" + hex.EncodeToString(sha1Mac.Sum(nil)), true},
+		{"X-Hub-Signature-256", "sha256=" + hex.EncodeToString(sha256Mac.Sum(nil)), true},
+		{"X-Hub-Signature", "sha1=" + strings.Repeat("0", 40), false},
+		{"X-Hub-Signature-256", "sha256=zz", false},
+		{"X-Hub-Signature", hex.EncodeToString(sha1Mac.Sum(nil)), false},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/hooks/github", nil)
+		r.Header.Set(test.header, test.value)
+
+		if config.verify(r, body) != test.expect {
+			t.Errorf("verify with %s: %s returned %t, expected %t", test.header, test.value, !test.expect, test.expect)
+		}
+	}
+
+	if config.verify(httptest.NewRequest(http.MethodPost, "/hooks/github", nil), body) {
+		t.Error("verify accepted an unsigned payload")
+	}
+}
+
+func TestParseGithubHook(t *testing.T) {
+	for _, test := range []struct {
+		event, body string
+
+		expectRepo  string
+		expectBuild *githubHookBuild
+	}{
+		{"ping", `{"zen":"Design for failure."}`, "", nil},
+		{"push", `{"after":"` + testSHA + `","repository":{"name":"repo","full_name":"user/repo","owner":{"name":"user"}}}`,
+			"user/repo", &githubHookBuild{"user", "repo", testSHA, "", ""}},
+		{"push", `{"after":"0000000000000000000000000000000000000000","deleted":true,"repository":{"name":"repo","full_name":"user/repo","owner":{"name":"user"}}}`,
+			"user/repo", nil},
+		{"pull_request", `{"action":"synchronize","pull_request":{"head":{"sha":"` + testSHA + `","repo":{"name":"fork","full_name":"other/fork","owner":{"login":"other"}}}},"repository":{"name":"repo","full_name":"user/repo","owner":{"login":"user"}}}`,
+			"user/repo", &githubHookBuild{"other", "fork", testSHA, "user", "repo"}},
+		{"pull_request", `{"action":"closed","pull_request":{"head":{"sha":"` + testSHA + `","repo":{"name":"repo","full_name":"user/repo","owner":{"login":"user"}}}},"repository":{"name":"repo","full_name":"user/repo","owner":{"login":"user"}}}`,
+			"user/repo", nil},
+		{"pull_request", `{"action":"opened","pull_request":{"head":{"sha":"` + testSHA + `","repo":null}},"repository":{"name":"repo","full_name":"user/repo","owner":{"login":"user"}}}`,
+			"user/repo", nil},
+	} {
+		repo, build, err := parseGithubHook(test.event, []byte(test.body))
+		if err != nil {
+			t.Errorf("parseGithubHook(%q) returned error: %v", test.event, err)
+			continue
+		}
+
+		var fullName string
+		if repo != nil {
+			fullName = repo.FullName
+		}
+
+		if fullName != test.expectRepo {
+			t.Errorf("parseGithubHook(%q) returned repository %q, expected %q", test.event, fullName, test.expectRepo)
+		}
+
+		if (build == nil) != (test.expectBuild == nil) || build != nil && *build != *test.expectBuild {
+			t.Errorf("parseGithubHook(%q) returned build %+v, expected %+v", test.event, build, test.expectBuild)
+		}
+	}
+
+	if _, _, err := parseGithubHook("push", []byte("{")); err == nil {
+		t.Error("parseGithubHook accepted invalid JSON")
+	}
+}
+
+func TestGithubHookAllowed(t *testing.T) {
+	config := &githubHookConfig{
+		Repos: []string{"user/repo", "org/*"},
+	}
+
+	for fullName, expect := range map[string]bool{
+		"user/repo":  true,
+		"User/Repo":  true,
+		"user/other": false,
+		"org/site":   true,
+		"other/org":  false,
+	} {
+		if config.allowed(fullName) != expect {
+			t.Errorf("allowed(%q) returned %t, expected %t", fullName, !expect, expect)
+		}
+	}
+
+	if new(githubHookConfig).allowed("user/repo") {
+		t.Error("allowed accepted a repository with an empty allowlist")
+	}
+}
+
+func TestGithubHookHandler(t *testing.T) {
+	config := &githubHookConfig{
+		Secret: "secret",
+		Repos:  []string{"user/*"},
+	}
+
+	q := &buildQueue{
+		queue: make(chan *buildJob, 10),
+		jobs:  make(map[string]*buildJob),
+	}
+
+	handler := getGithubHookHandler(config, sourceRegistry{
+		defaultSourceName: new(githubSource),
+	}, q)
+
+	push := func(fullName string) string {
+		return fmt.Sprintf(`{"after":%q,"repository":{"name":%q,"full_name":%q,"owner":{"name":%q}}}`,
+			testSHA, fullName[strings.Index(fullName, "/")+1:], fullName, fullName[:strings.Index(fullName, "/")])
+	}
+
+	for _, test := range []struct {
+		event, body, secret string
+		expect              int
+	}{
+		{"ping", `{}`, "secret", http.StatusNoContent},
+		{"push", push("user/repo"), "wrong", http.StatusForbidden},
+		{"push", push("other/repo"), "secret", http.StatusForbidden},
+		{"push", push("user/repo"), "secret", http.StatusAccepted},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/hooks/github", strings.NewReader(test.body))
+		r.Header.Set("X-GitHub-Event", test.event)
+		signGithubHook(r, test.secret, test.body)
+
+		w := httptest.NewRecorder()
+		handler(w, r, nil)
+
+		if w.Code != test.expect {
+			t.Errorf("%s event returned status %d, expected %d", test.event, w.Code, test.expect)
+		}
+	}
+
+	tag, _ := buildKey(defaultSourceName, "user", "repo", testSHA)
+	if q.Lookup(tag) == nil {
+		t.Error("push did not enqueue a build")
+	}
+
+	if len(q.jobs) != 1 {
+		t.Errorf("%d builds enqueued, expected 1", len(q.jobs))
+	}
+}
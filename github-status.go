@@ -0,0 +1,207 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang/groupcache/lru"
+	"github.com/google/go-github/github"
+)
+
+// githubStatusTimeout is how long creating a commit status
+// may take.
+const githubStatusTimeout = 30 * time.Second
+
+// githubStatusQueueSize is the number of commit statuses
+// that may wait to be created before more are dropped.
+const githubStatusQueueSize = 64
+
+// githubStatusCacheSize is the number of commits whose last
+// status is remembered.
+const githubStatusCacheSize = 1024
+
+type githubStatusUpdate struct {
+	job    *buildJob
+	status *github.RepoStatus
+}
+
+// githubStatusNotifier reports builds of GitHub commits as
+// commit statuses that link to the built site or, if the
+// build failed, to the build log.
+type githubStatusNotifier struct {
+	Source *githubSource
+	Hosts  *hostConfig
+
+	// Scheme is the scheme of the links, it defaults to
+	// https.
+	Scheme string
+
+	// Context identifies the statuses, it defaults to
+	// jekyll-history.
+	Context string
+
+	once    sync.Once
+	updates chan githubStatusUpdate
+
+	mu     sync.Mutex
+	states *lru.Cache
+}
+
+func (sn *githubStatusNotifier) url(path string) *url.URL {
+	scheme := sn.Scheme
+	if len(scheme) == 0 {
+		scheme = "https"
+	}
+
+	return &url.URL{
+		Scheme: scheme,
+		Host:   sn.Hosts.Primary,
+		Path:   path,
+	}
+}
+
+func (sn *githubStatusNotifier) status(job *buildJob, state buildState, resp *BuildJekyllResponse) *github.RepoStatus {
+	commitURL := commitPath(job.Source, job.User, job.Repo, job.Commit)
+
+	var status, target, description string
+
+	switch state {
+	case buildRunning:
+		status, description = "pending", "Building with Jekyll"
+		target = sn.url(commitURL + "b/").String()
+	case buildSucceeded:
+		status, description = "success", "Built with Jekyll"
+		target = sn.Hosts.siteURL(&http.Request{
+			Host: sn.Hosts.Primary,
+			URL:  sn.url("/"),
+		}, job.Tag, "/", "")
+
+		if resp != nil && resp.DurationMs != 0 {
+			description = fmt.Sprintf("Built with Jekyll in %s", time.Duration(resp.DurationMs)*time.Millisecond)
+		}
+	case buildFailed:
+		status, description = "failure", "Jekyll build failed"
+		target = sn.url(commitURL + "log").String()
+
+		if resp != nil && resp.TimedOut {
+			description = "Jekyll build timed out"
+		} else if resp != nil && resp.Transient {
+			description = "Jekyll build could not be run"
+		}
+
+		if resp != nil && resp.Transient {
+			status = "error"
+		}
+	default:
+		return nil
+	}
+
+	context := sn.Context
+	if len(context) == 0 {
+		context = "jekyll-history"
+	}
+
+	return &github.RepoStatus{
+		State:       &status,
+		TargetURL:   &target,
+		Description: &description,
+		Context:     &context,
+	}
+}
+
+// Notify queues the status of job to be created, unless it
+// is the same as the last one created for the commit. The
+// statuses are created in order by another goroutine so
+// that builds are not held up by GitHub.
+func (sn *githubStatusNotifier) Notify(job *buildJob, state buildState, resp *BuildJekyllResponse) {
+	if job.Source != sn.Source.Name() || !isCommitSHA(job.Commit) {
+		return
+	}
+
+	// there is no commit to report on
+	if resp != nil && resp.Code == http.StatusNotFound {
+		return
+	}
+
+	status := sn.status(job, state, resp)
+	if status == nil {
+		return
+	}
+
+	sn.once.Do(func() {
+		sn.updates = make(chan githubStatusUpdate, githubStatusQueueSize)
+		sn.states = lru.New(githubStatusCacheSize)
+
+		go sn.run()
+	})
+
+	key := statusKey(job)
+
+	sn.mu.Lock()
+	last, ok := sn.states.Get(key)
+	if ok && last.(string) == status.GetState() {
+		sn.mu.Unlock()
+		return
+	}
+
+	sn.states.Add(key, status.GetState())
+	sn.mu.Unlock()
+
+	select {
+	case sn.updates <- githubStatusUpdate{job, status}:
+	default:
+		sn.forget(key, status.GetState())
+
+		log.Printf("dropping %s status for %s: too many statuses waiting", status.GetState(), key)
+	}
+}
+
+func statusKey(job *buildJob) string {
+	user, repo := job.StatusRepo()
+	return user + "/" + repo + "@" + job.Commit
+}
+
+// forget removes the last status of the commit at key, if
+// it is state, so that it may be created again.
+func (sn *githubStatusNotifier) forget(key, state string) {
+	sn.mu.Lock()
+	if last, ok := sn.states.Get(key); ok && last.(string) == state {
+		sn.states.Remove(key)
+	}
+	sn.mu.Unlock()
+}
+
+func (sn *githubStatusNotifier) run() {
+	for update := range sn.updates {
+		sn.create(update.job, update.status)
+	}
+}
+
+func (sn *githubStatusNotifier) create(job *buildJob, status *github.RepoStatus) {
+	ctx, cancel := context.WithTimeout(context.Background(), githubStatusTimeout)
+	defer cancel()
+
+	// statuses of pull requests from forks are created on
+	// the base repository, where they are shown
+	user, repo := job.StatusRepo()
+
+	_, ghResp, err := sn.Source.Client.Repositories.CreateStatus(ctx, user, repo, job.Commit, status)
+	if err != nil {
+		sn.forget(statusKey(job), status.GetState())
+
+		log.Printf("%[1]T: %[1]v", err)
+		return
+	}
+
+	sn.Source.logRate(ghResp)
+}
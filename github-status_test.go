@@ -0,0 +1,202 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/groupcache"
+	"github.com/google/go-github/github"
+)
+
+func TestGithubStatusNotifier(t *testing.T) {
+	statuses := make(chan github.RepoStatus, 8)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/user/repo/statuses/"+testSHA {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+			return
+		}
+
+		var status github.RepoStatus
+		if err := json.NewDecoder(r.Body).Decode(&status); err != nil {
+			t.Error(err)
+		}
+
+		statuses <- status
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	gs := &githubSource{
+		Client: github.NewClient(nil),
+	}
+	gs.Client.BaseURL, _ = url.Parse(ts.URL + "/")
+
+	tag, _ := buildKey(defaultSourceName, "user", "repo", testSHA)
+	job := &buildJob{
+		Tag: tag,
+
+		Source: defaultSourceName,
+		User:   "user",
+		Repo:   "repo",
+		Commit: testSHA,
+	}
+
+	sn := &githubStatusNotifier{
+		Source: gs,
+		Hosts: &hostConfig{
+			Primary: "example.com",
+		},
+	}
+
+	sn.Notify(job, buildRunning, nil)
+	sn.Notify(job, buildRunning, nil)
+	sn.Notify(job, buildSucceeded, &BuildJekyllResponse{})
+	sn.Notify(job, buildSucceeded, &BuildJekyllResponse{DurationMs: 1000})
+	sn.Notify(job, buildFailed, &BuildJekyllResponse{Error: "failed", Code: http.StatusUnprocessableEntity})
+	sn.Notify(job, buildFailed, &BuildJekyllResponse{Error: "failed", Code: http.StatusBadGateway, Transient: true})
+
+	// not found commits and other sources are ignored
+	sn.Notify(job, buildFailed, &BuildJekyllResponse{Error: "not found", Code: http.StatusNotFound})
+	sn.Notify(&buildJob{Source: "gitlab", User: "user", Repo: "repo", Commit: testSHA}, buildRunning, nil)
+
+	for i, expect := range []struct {
+		state, target string
+	}{
+		{"pending", "https://example.com/u/user/r/repo/c/" + testSHA + "/b/"},
+		{"success", "https://" + tag + ".example.com/"},
+		{"failure", "https://example.com/u/user/r/repo/c/" + testSHA + "/log"},
+		{"error", "https://example.com/u/user/r/repo/c/" + testSHA + "/log"},
+	} {
+		var status github.RepoStatus
+
+		select {
+		case status = <-statuses:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("missing %s status", expect.state)
+		}
+
+		if status.GetState() != expect.state || status.GetTargetURL() != expect.target || status.GetContext() != "jekyll-history" {
+			t.Errorf("status %d was %s %s with context %s, expected %s %s", i, status.GetState(), status.GetTargetURL(), status.GetContext(), expect.state, expect.target)
+		}
+	}
+
+	// unchanged states are not created again
+	select {
+	case status := <-statuses:
+		t.Errorf("unexpected %s status created", status.GetState())
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	sn.Hosts.SitePaths = true
+
+	if status := sn.status(job, buildSucceeded, nil); !strings.HasSuffix(status.GetTargetURL(), "example.com/s/"+tag+"/") {
+		t.Errorf("status linked to %s with SitePaths set", status.GetTargetURL())
+	}
+}
+
+func TestGithubStatusNotifierFork(t *testing.T) {
+	paths := make(chan string, 1)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	gs := &githubSource{
+		Client: github.NewClient(nil),
+	}
+	gs.Client.BaseURL, _ = url.Parse(ts.URL + "/")
+
+	q := newBuildQueue(nil, 0, 1)
+
+	// the fork was already built before the pull request
+	if _, err := q.Enqueue(defaultSourceName, "other", "fork", testSHA); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := q.EnqueueFor(defaultSourceName, "other", "fork", testSHA, "user", "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sn := &githubStatusNotifier{
+		Source: gs,
+		Hosts: &hostConfig{
+			Primary: "example.com",
+		},
+	}
+	sn.Notify(job, buildRunning, nil)
+
+	select {
+	case path := <-paths:
+		if expect := "/repos/user/repo/statuses/" + testSHA; path != expect {
+			t.Errorf("status for pull request from fork was created at %s, expected %s", path, expect)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("missing status")
+	}
+}
+
+type recordingNotifier struct {
+	mu     sync.Mutex
+	states []buildState
+	done   chan struct{}
+}
+
+func (rn *recordingNotifier) Notify(job *buildJob, state buildState, resp *BuildJekyllResponse) {
+	rn.mu.Lock()
+	rn.states = append(rn.states, state)
+	rn.mu.Unlock()
+
+	if state != buildRunning {
+		close(rn.done)
+	}
+}
+
+func TestBuildQueueNotifier(t *testing.T) {
+	group := groupcache.NewGroup("test-build-queue-notifier", 1<<20, groupcache.GetterFunc(func(_ groupcache.Context, key string, dest groupcache.Sink) error {
+		return dest.SetProto(&BuildJekyllResponse{})
+	}))
+
+	rn := &recordingNotifier{
+		done: make(chan struct{}),
+	}
+
+	q := newBuildQueue(group, 1, 10)
+	q.Notifier = rn
+
+	if _, err := q.Enqueue(defaultSourceName, "user", "repo", testSHA); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-rn.done:
+	case <-time.After(time.Second):
+		t.Fatal("notifier was not told the build finished")
+	}
+
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+
+	if len(rn.states) != 2 || rn.states[0] != buildRunning || rn.states[1] != buildSucceeded {
+		t.Errorf("notifier was told %v, expected [%s %s]", rn.states, buildRunning, buildSucceeded)
+	}
+}
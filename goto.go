@@ -22,78 +22,153 @@ func gotoNotFoundHandler(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, newURL.String(), http.StatusFound)
 }
 
-func gotoUserHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	user := ps.ByName("user")
-
-	if len(user) == 0 {
-		gotoNotFoundHandler(w, r)
-		return
-	}
-
+func gotoRedirect(w http.ResponseWriter, r *http.Request, path string) {
 	newURL := *r.URL
-	newURL.Path = "/u/" + url.QueryEscape(user) + "/"
+	newURL.Path = path
 	newURL.RawQuery = ""
 
 	http.Redirect(w, r, newURL.String(), http.StatusFound)
 }
 
-func gotoRepoHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	user, repo := ps.ByName("user"), ps.ByName("repo")
+func gotoUserHandler(source string) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		user := ps.ByName("user")
+
+		if len(user) == 0 {
+			gotoNotFoundHandler(w, r)
+			return
+		}
 
-	if len(user) == 0 || len(repo) == 0 {
-		gotoNotFoundHandler(w, r)
-		return
+		gotoRedirect(w, r, userPath(source, user))
 	}
+}
 
-	newURL := *r.URL
-	newURL.Path = "/u/" + url.QueryEscape(user) + "/r/" + url.QueryEscape(repo) + "/"
-	newURL.RawQuery = ""
+func gotoRepoHandler(source string) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		user, repo := ps.ByName("user"), ps.ByName("repo")
 
-	http.Redirect(w, r, newURL.String(), http.StatusFound)
+		if len(user) == 0 || len(repo) == 0 {
+			gotoNotFoundHandler(w, r)
+			return
+		}
+
+		gotoRedirect(w, r, repoPath(source, user, repo))
+	}
 }
 
-func gotoCommitHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	user, repo, commit := ps.ByName("user"), ps.ByName("repo"), ps.ByName("commit")
+func gotoCommitHandler(source string) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		user, repo, commit := ps.ByName("user"), ps.ByName("repo"), ps.ByName("commit")
+
+		if len(user) == 0 || len(repo) == 0 || len(commit) == 0 {
+			gotoNotFoundHandler(w, r)
+			return
+		}
 
-	if len(user) == 0 || len(repo) == 0 || len(commit) == 0 {
-		gotoNotFoundHandler(w, r)
-		return
+		gotoRedirect(w, r, commitPath(source, user, repo, commit))
 	}
+}
 
-	newURL := *r.URL
-	newURL.Path = "/u/" + url.QueryEscape(user) + "/r/" + url.QueryEscape(repo) + "/c/" + url.QueryEscape(commit) + "/"
-	newURL.RawQuery = ""
+func gotoTreeHandler(source string) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		user, repo, tree := ps.ByName("user"), ps.ByName("repo"), ps.ByName("tree")
 
-	http.Redirect(w, r, newURL.String(), http.StatusFound)
+		if len(user) == 0 || len(repo) == 0 || len(tree) == 0 {
+			gotoNotFoundHandler(w, r)
+			return
+		}
+
+		gotoRedirect(w, r, repoPath(source, user, repo)+"t/"+url.QueryEscape(tree)+"/")
+	}
 }
 
-func gotoTreeHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	user, repo, tree := ps.ByName("user"), ps.ByName("repo"), ps.ByName("tree")
+func gotoPullHandler(source string) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		user, repo, number := ps.ByName("user"), ps.ByName("repo"), ps.ByName("number")
+
+		if len(user) == 0 || len(repo) == 0 || len(number) == 0 {
+			gotoNotFoundHandler(w, r)
+			return
+		}
 
-	if len(user) == 0 || len(repo) == 0 || len(tree) == 0 {
-		gotoNotFoundHandler(w, r)
-		return
+		gotoRedirect(w, r, repoPath(source, user, repo)+"pull/"+url.QueryEscape(number)+"/")
 	}
+}
 
-	newURL := *r.URL
-	newURL.Path = "/u/" + url.QueryEscape(user) + "/r/" + url.QueryEscape(repo) + "/t/" + url.QueryEscape(tree) + "/"
-	newURL.RawQuery = ""
+// gotoRepoPageHandler redirects to page, such as pulls/,
+// of a repository.
+func gotoRepoPageHandler(source, page string) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		user, repo := ps.ByName("user"), ps.ByName("repo")
 
-	http.Redirect(w, r, newURL.String(), http.StatusFound)
+		if len(user) == 0 || len(repo) == 0 {
+			gotoNotFoundHandler(w, r)
+			return
+		}
+
+		gotoRedirect(w, r, repoPath(source, user, repo)+page)
+	}
 }
 
-func getGotoHandler() func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+// newGotoRouter returns a router that recognises the web
+// URLs of GitHub, GitLab and Gitea and redirects to the
+// pages for source.
+func newGotoRouter(source string) *httprouter.Router {
 	router := new(httprouter.Router)
 
 	router.NotFound = http.HandlerFunc(gotoNotFoundHandler)
-	router.GET("/:user", gotoUserHandler)
-	router.GET("/:user/", gotoUserHandler)
-	router.GET("/:user/:repo", gotoRepoHandler)
-	router.GET("/:user/:repo/", gotoRepoHandler)
-	router.GET("/:user/:repo/commit/:commit", gotoCommitHandler)
-	router.GET("/:user/:repo/commit/:commit/", gotoCommitHandler)
-	router.GET("/:user/:repo/tree/:tree", gotoTreeHandler)
-	router.GET("/:user/:repo/tree/:tree/", gotoTreeHandler)
+
+	for _, route := range [...]struct {
+		path   string
+		handle httprouter.Handle
+	}{
+		{"/:user", gotoUserHandler(source)},
+		{"/:user/:repo", gotoRepoHandler(source)},
+		{"/:user/:repo/commit/:commit", gotoCommitHandler(source)},
+		{"/:user/:repo/tree/:tree", gotoTreeHandler(source)},
+		{"/:user/:repo/pulls", gotoRepoPageHandler(source, "pulls/")},
+		{"/:user/:repo/branches", gotoRepoPageHandler(source, "branches/")},
+		{"/:user/:repo/tags", gotoRepoPageHandler(source, "tags/")},
+		{"/:user/:repo/releases/tag/:tree", gotoTreeHandler(source)},
+		{"/:user/:repo/pull/:number", gotoPullHandler(source)},
+		{"/:user/:repo/pull/:number/commits", gotoPullHandler(source)},
+		{"/:user/:repo/pull/:number/files", gotoPullHandler(source)},
+
+		// GitLab
+		{"/:user/:repo/-/commit/:commit", gotoCommitHandler(source)},
+		{"/:user/:repo/-/tree/:tree", gotoTreeHandler(source)},
+		{"/:user/:repo/-/branches", gotoRepoPageHandler(source, "branches/")},
+		{"/:user/:repo/-/tags", gotoRepoPageHandler(source, "tags/")},
+		{"/:user/:repo/-/tags/:tree", gotoTreeHandler(source)},
+
+		// Gitea
+		{"/:user/:repo/src/branch/:tree", gotoTreeHandler(source)},
+		{"/:user/:repo/src/tag/:tree", gotoTreeHandler(source)},
+		{"/:user/:repo/src/commit/:commit", gotoCommitHandler(source)},
+	} {
+		router.GET(route.path, route.handle)
+		router.GET(route.path+"/", route.handle)
+	}
+
+	return router
+}
+
+func getGotoHandler(sources sourceRegistry) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	routers := make(map[string]*httprouter.Router, 2*len(sources))
+
+	for name, source := range sources {
+		host := strings.ToLower(source.WebHost())
+		if len(host) == 0 {
+			continue
+		}
+
+		router := newGotoRouter(name)
+		routers[host] = router
+
+		if !strings.HasPrefix(host, "www.") {
+			routers["www."+host] = router
+		}
+	}
 
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		w.Header().Set("Cache-Control", "max-age=0")
@@ -121,7 +196,8 @@ func getGotoHandler() func(w http.ResponseWriter, r *http.Request, ps httprouter
 			return
 		}
 
-		if host := strings.ToLower(parsedURL.Host); host != "github.com" && host != "www.github.com" {
+		router, ok := routers[strings.ToLower(parsedURL.Host)]
+		if !ok {
 			gotoNotFoundHandler(w, r)
 			return
 		}
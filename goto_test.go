@@ -7,6 +7,7 @@ package main
 
 import (
 	"net/http"
+	"net/url"
 	"testing"
 )
 
@@ -28,7 +29,9 @@ func (rw *fakeResponseWriter) WriteHeader(code int) {
 }
 
 func TestGotoHandler(t *testing.T) {
-	gotoHandler := getGotoHandler()
+	gotoHandler := getGotoHandler(sourceRegistry{
+		defaultSourceName: new(githubSource),
+	})
 
 	for url, expect := range map[string]string{
 		// no url
@@ -103,3 +106,45 @@ func TestGotoHandler(t *testing.T) {
 		}
 	}
 }
+
+func TestGotoHandlerSources(t *testing.T) {
+	gitlabURL, _ := url.Parse("https://gitlab.example.com")
+	giteaURL, _ := url.Parse("https://gitea.example.com")
+
+	gotoHandler := getGotoHandler(sourceRegistry{
+		defaultSourceName: new(githubSource),
+		"gitlab":          newGitlabSource("gitlab", gitlabURL, ""),
+		"gitea":           newGiteaSource("gitea", giteaURL, ""),
+	})
+
+	for u, expect := range map[string]string{
+		"https://gitlab.example.com/example":                           "http://example.com/p/gitlab/u/example/",
+		"https://gitlab.example.com/example/example/":                  "http://example.com/p/gitlab/u/example/r/example/",
+		"https://gitlab.example.com/example/example/-/commit/abcdef":   "http://example.com/p/gitlab/u/example/r/example/c/abcdef/",
+		"https://gitlab.example.com/example/example/-/tree/master":     "http://example.com/p/gitlab/u/example/r/example/t/master/",
+		"https://gitea.example.com/example/example/commit/abcdef":      "http://example.com/p/gitea/u/example/r/example/c/abcdef/",
+		"https://gitea.example.com/example/example/src/branch/master":  "http://example.com/p/gitea/u/example/r/example/t/master/",
+		"https://gitea.example.com/example/example/src/commit/abcdef/": "http://example.com/p/gitea/u/example/r/example/c/abcdef/",
+		"https://github.com/example/example":                           "http://example.com/u/example/r/example/",
+		"https://github.com/example/example/pulls":                     "http://example.com/u/example/r/example/pulls/",
+		"https://github.com/example/example/pull/42":                   "http://example.com/u/example/r/example/pull/42/",
+		"https://github.com/example/example/pull/42/files":             "http://example.com/u/example/r/example/pull/42/",
+		"https://github.com/example/example/branches":                  "http://example.com/u/example/r/example/branches/",
+		"https://github.com/example/example/releases/tag/v1.0":         "http://example.com/u/example/r/example/t/v1.0/",
+		"https://gitlab.example.com/example/example/-/tags":            "http://example.com/p/gitlab/u/example/r/example/tags/",
+		"https://gitlab.example.com/example/example/-/tags/v1.0":       "http://example.com/p/gitlab/u/example/r/example/t/v1.0/",
+		"https://unknown.example.com/example/example":                  "http://example.com/",
+	} {
+		req, err := http.NewRequest(http.MethodGet, "http://example.com/?url="+url.QueryEscape(u), nil)
+		if err != nil {
+			t.Error(err)
+		}
+
+		rw := &fakeResponseWriter{make(http.Header), -1}
+		gotoHandler(rw, req, nil)
+
+		if loc := rw.Headers.Get("Location"); loc != expect {
+			t.Errorf("unexpected redirect for %s, expected %s, got %s", u, expect, loc)
+		}
+	}
+}
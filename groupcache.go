@@ -12,7 +12,7 @@ import (
 	"github.com/golang/groupcache"
 )
 
-func getGroupcache(getter *buildJekyllGetter) (*groupcache.Group, *groupcache.HTTPPool, *groupcache.HTTPPoolOptions) {
+func getGroupcache(getter *buildJekyllGetter, self string) (*groupcache.Group, *groupcache.HTTPPool, *groupcache.HTTPPoolOptions) {
 	buildJekyll := groupcache.NewGroup("build-jekyll", 1<<20, getter)
 
 	castagnoli := crc32.MakeTable(crc32.Castagnoli)
@@ -28,7 +28,7 @@ func getGroupcache(getter *buildJekyllGetter) (*groupcache.Group, *groupcache.HT
 			return crc32.Checksum(data, castagnoli)
 		},
 	}
-	httpPool := groupcache.NewHTTPPoolOpts("http://jekyllhistory.org:8080", poolOpts)
+	httpPool := groupcache.NewHTTPPoolOpts(self, poolOpts)
 
 	return buildJekyll, httpPool, poolOpts
 }
@@ -23,8 +23,20 @@ var _ = fmt.Errorf
 var _ = math.Inf
 
 type BuildJekyllResponse struct {
-	Error string `protobuf:"bytes,1,opt,name=error" json:"error,omitempty"`
-	Code  int32  `protobuf:"varint,2,opt,name=code" json:"code,omitempty"`
+	Error           string   `protobuf:"bytes,1,opt,name=error" json:"error,omitempty"`
+	Code            int32    `protobuf:"varint,2,opt,name=code" json:"code,omitempty"`
+	Transient       bool     `protobuf:"varint,3,opt,name=transient" json:"transient,omitempty"`
+	RetryAfter      int64    `protobuf:"varint,4,opt,name=retry_after,json=retryAfter" json:"retry_after,omitempty"`
+	Attempts        int32    `protobuf:"varint,5,opt,name=attempts" json:"attempts,omitempty"`
+	TimedOut        bool     `protobuf:"varint,6,opt,name=timed_out,json=timedOut" json:"timed_out,omitempty"`
+	Commit          string   `protobuf:"bytes,7,opt,name=commit" json:"commit,omitempty"`
+	Started         int64    `protobuf:"varint,8,opt,name=started" json:"started,omitempty"`
+	DurationMs      int64    `protobuf:"varint,9,opt,name=duration_ms,json=durationMs" json:"duration_ms,omitempty"`
+	JekyllVersion   string   `protobuf:"bytes,10,opt,name=jekyll_version,json=jekyllVersion" json:"jekyll_version,omitempty"`
+	Files           int64    `protobuf:"varint,11,opt,name=files" json:"files,omitempty"`
+	Bytes           int64    `protobuf:"varint,12,opt,name=bytes" json:"bytes,omitempty"`
+	CompressedBytes int64    `protobuf:"varint,13,opt,name=compressed_bytes,json=compressedBytes" json:"compressed_bytes,omitempty"`
+	Warnings        []string `protobuf:"bytes,14,rep,name=warnings" json:"warnings,omitempty"`
 }
 
 func (m *BuildJekyllResponse) Reset()                    { *m = BuildJekyllResponse{} }
@@ -37,12 +49,25 @@ func init() {
 }
 
 var fileDescriptor0 = []byte{
-	// 110 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0x12, 0x48, 0x2f, 0xca, 0x2f,
-	0x2d, 0x48, 0x4e, 0x4c, 0xce, 0x48, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0xc9, 0x4d,
-	0xcc, 0xcc, 0x53, 0xb2, 0xe7, 0x12, 0x76, 0x2a, 0xcd, 0xcc, 0x49, 0xf1, 0x4a, 0xcd, 0xae, 0xcc,
-	0xc9, 0x09, 0x4a, 0x2d, 0x2e, 0xc8, 0xcf, 0x2b, 0x4e, 0x15, 0x12, 0xe1, 0x62, 0x4d, 0x2d, 0x2a,
-	0xca, 0x2f, 0x92, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x0c, 0x82, 0x70, 0x84, 0x84, 0xb8, 0x58, 0x92,
-	0xf3, 0x53, 0x52, 0x25, 0x98, 0x80, 0x82, 0xac, 0x41, 0x60, 0x76, 0x12, 0x1b, 0xd8, 0x34, 0x63,
-	0x40, 0x00, 0x00, 0x00, 0xff, 0xff, 0xb2, 0x82, 0xa0, 0xc8, 0x61, 0x00, 0x00, 0x00,
+	// 305 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x45, 0x91, 0xcf, 0x4a, 0x03, 0x31,
+	0x10, 0xc6, 0xa9, 0xdb, 0x3f, 0xdb, 0xd4, 0xd6, 0x12, 0x45, 0x82, 0x0a, 0x16, 0x41, 0xd0, 0x8b,
+	0x97, 0x3e, 0x81, 0x3d, 0x0a, 0x22, 0xec, 0xc1, 0xeb, 0x92, 0xee, 0x4e, 0x6b, 0x74, 0x37, 0x59,
+	0x26, 0xb3, 0x4a, 0xdf, 0xc7, 0x07, 0x35, 0x3b, 0xb1, 0xed, 0x2d, 0xdf, 0x6f, 0x86, 0x6f, 0xbe,
+	0x99, 0x88, 0xf9, 0x16, 0x5d, 0xdb, 0x14, 0xba, 0xf8, 0x80, 0xa7, 0x06, 0x1d, 0x39, 0xd9, 0xaf,
+	0xb5, 0xb1, 0x77, 0xbf, 0x89, 0x38, 0x5f, 0xb5, 0xa6, 0x2a, 0x5f, 0xe0, 0x6b, 0x57, 0x55, 0x19,
+	0xf8, 0xc6, 0x59, 0x0f, 0xf2, 0x42, 0x0c, 0x00, 0xd1, 0xa1, 0xea, 0x2d, 0x7a, 0x0f, 0xe3, 0x2c,
+	0x0a, 0x29, 0x45, 0xbf, 0x70, 0x25, 0xa8, 0x93, 0x00, 0x07, 0x19, 0xbf, 0xe5, 0x8d, 0x18, 0x13,
+	0x6a, 0xeb, 0x0d, 0x58, 0x52, 0x49, 0x28, 0xa4, 0xd9, 0x11, 0xc8, 0x5b, 0x31, 0x41, 0x20, 0xdc,
+	0xe5, 0x7a, 0x43, 0x80, 0xaa, 0x1f, 0xea, 0x49, 0x26, 0x18, 0x3d, 0x77, 0x44, 0x5e, 0x89, 0x54,
+	0x13, 0x41, 0xdd, 0x90, 0x57, 0x03, 0xb6, 0x3d, 0x68, 0x79, 0x1d, 0xac, 0x4d, 0x0d, 0x65, 0xee,
+	0x5a, 0x52, 0x43, 0xb6, 0x4e, 0x19, 0xbc, 0xb5, 0x24, 0x2f, 0xc5, 0xb0, 0x70, 0x75, 0x6d, 0x48,
+	0x8d, 0x38, 0xe2, 0xbf, 0x92, 0x4a, 0x8c, 0x3c, 0x69, 0x24, 0x28, 0x55, 0xca, 0xd3, 0xf6, 0xb2,
+	0xcb, 0x52, 0xb6, 0xa8, 0xc9, 0x38, 0x9b, 0xd7, 0x5e, 0x8d, 0x63, 0x96, 0x3d, 0x7a, 0xf5, 0xf2,
+	0x5e, 0xcc, 0x3e, 0xf9, 0x0c, 0xf9, 0x37, 0xa0, 0x0f, 0x4c, 0x09, 0xb6, 0x9e, 0x46, 0xfa, 0x1e,
+	0x61, 0x77, 0x9b, 0x8d, 0xa9, 0xc0, 0xab, 0x09, 0x3b, 0x44, 0xd1, 0xd1, 0xf5, 0x8e, 0x02, 0x3d,
+	0x8d, 0x94, 0x85, 0x7c, 0x14, 0xf3, 0x90, 0xab, 0x41, 0xf0, 0x3e, 0xec, 0x11, 0x1b, 0xa6, 0xdc,
+	0x70, 0x76, 0xe4, 0x2b, 0x6e, 0x0d, 0x97, 0xf8, 0xd1, 0x68, 0x8d, 0xdd, 0x7a, 0x35, 0x5b, 0x24,
+	0x61, 0xee, 0x41, 0xaf, 0x87, 0xfc, 0x67, 0xcb, 0x3f, 0x91, 0xbc, 0xc0, 0xd4, 0xc7, 0x01, 0x00,
+	0x00,
 }
@@ -0,0 +1,167 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/golang/groupcache"
+)
+
+const peerHelperEnv = "JEKYLL_HISTORY_PEER_HELPER"
+
+func peerTestKey(i int) (tag, key string) {
+	return buildKey(defaultSourceName, "user", fmt.Sprintf("repo%d", i), "0123456789abcdef0123456789abcdef01234567")
+}
+
+func newPeerTestRouter(store storage) (*groupcache.Group, *groupcache.HTTPPool, *httptest.Server) {
+	srv := httptest.NewUnstartedServer(nil)
+	self := "http://" + srv.Listener.Addr().String()
+
+	group, pool, poolOpts := getGroupcache(&buildJekyllGetter{
+		Storage: store,
+	}, self)
+
+	srv.Config.Handler = getRouter(pool, poolOpts, self, nil, "", nil, store, &hostConfig{
+		Primary: "jekyllhistory.org",
+	}, nil)
+	srv.Start()
+
+	return group, pool, srv
+}
+
+// TestGroupcachePeerHelper is run in a child process by
+// TestGroupcachePeers as the second peer, as groupcache
+// only allows one pool in each process.
+func TestGroupcachePeerHelper(t *testing.T) {
+	if os.Getenv(peerHelperEnv) != "1" {
+		t.Skip("run by TestGroupcachePeers")
+	}
+
+	store := newMemoryStorage()
+
+	for i := 0; i < 64; i++ {
+		tag, _ := peerTestKey(i)
+
+		if err := store.Put(tagPrefix(tag)+"/index.html", bytes.NewReader([]byte("index")), 5, http.Header{
+			"Content-Type": {"text/html; charset=utf-8"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := saveBuildManifest(store, tagPrefix(tag), newBuildManifest(defaultSourceName, "user", "repo", &BuildJekyllResponse{
+			Commit:        "helper",
+			JekyllVersion: "3.0.0",
+		})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, _, srv := newPeerTestRouter(store)
+	defer srv.Close()
+
+	fmt.Println(srv.URL)
+	select {}
+}
+
+func TestGroupcachePeers(t *testing.T) {
+	if os.Getenv(peerHelperEnv) == "1" {
+		t.Skip("running as a peer")
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestGroupcachePeerHelper$")
+	cmd.Env = append(os.Environ(), peerHelperEnv+"=1")
+	cmd.Stderr = os.Stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	peer, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	peer = peer[:len(peer)-1]
+
+	if _, _, err := net.SplitHostPort(peer[len("http://"):]); err != nil {
+		t.Fatalf("helper printed %q, expected a URL", peer)
+	}
+
+	group, pool, srv := newPeerTestRouter(newMemoryStorage())
+	defer srv.Close()
+
+	pool.Set(srv.URL, peer)
+
+	for i := 0; i < 64; i++ {
+		_, key := peerTestKey(i)
+
+		if _, remote := pool.PickPeer(key); !remote {
+			continue
+		}
+
+		var resp BuildJekyllResponse
+		if err := group.Get(context.Background(), key, groupcache.ProtoSink(&resp)); err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.Commit != "helper" || len(resp.Error) != 0 {
+			t.Errorf("groupcache returned %+v, expected the build from the peer", &resp)
+		}
+
+		return
+	}
+
+	t.Fatal("no key is owned by the peer")
+}
+
+func TestGroupcachePoolHosts(t *testing.T) {
+	pool := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pool"))
+	})
+
+	router := getRouter(pool, &groupcache.HTTPPoolOptions{
+		BasePath: "/_groupcache/",
+	}, "http://10.0.0.1:8080", nil, "", nil, newMemoryStorage(), &hostConfig{
+		Primary: "jekyllhistory.org",
+	}, nil)
+
+	for _, test := range []struct {
+		host string
+		pool bool
+	}{
+		{"jekyllhistory.org", true},
+		{"10.0.0.1:8080", true},
+		{"10.0.0.2:8080", false},
+		{testTag + ".jekyllhistory.org", false},
+	} {
+		rw := httptest.NewRecorder()
+		router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://"+test.host+"/_groupcache/build-jekyll/key", nil))
+
+		if pool := rw.Body.String() == "pool"; pool != test.pool {
+			t.Errorf("GET /_groupcache/ on %s served the pool: %t, expected %t", test.host, pool, test.pool)
+		}
+	}
+}
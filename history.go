@@ -10,9 +10,13 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
 
 	_ "github.com/joho/godotenv/autoload"
 )
@@ -36,11 +40,94 @@ func main() {
 	flag.StringVar(&work, "work", "/tmp/jklhstry.${random}", "the working directory")
 
 	var jekyll string
-	flag.StringVar(&jekyll, "jekyll", "shell", "the method to run jekyll (shell, docker)")
+	flag.StringVar(&jekyll, "jekyll", "shell", "the method to run jekyll ("+strings.Join(jekyllExecutorNames(), ", ")+")")
 
 	var jekyllOpts string
 	flag.StringVar(&jekyllOpts, "jekyll-opts", "", "option string to use when running jekyll")
 
+	var extractLimits extractLimits
+	flag.Int64Var(&extractLimits.MaxBytes, "max-archive-size", 1<<30, "the most bytes a repository archive may extract to, 0 for no limit")
+	flag.Int64Var(&extractLimits.MaxFiles, "max-archive-files", 100000, "the most files a repository archive may contain, 0 for no limit")
+	flag.Int64Var(&extractLimits.MaxFileSize, "max-archive-file-size", 100<<20, "the most bytes any file in a repository archive may hold, 0 for no limit")
+
+	var buildTimeout time.Duration
+	flag.DurationVar(&buildTimeout, "build-timeout", 10*time.Minute, "the longest jekyll may run for a build, 0 for no limit")
+
+	var fetchTimeout time.Duration
+	flag.DurationVar(&fetchTimeout, "fetch-timeout", 5*time.Minute, "the longest downloading or cloning a repository may take, 0 for no limit")
+
+	var storageKind string
+	flag.StringVar(&storageKind, "storage", "s3", "the storage backend for built sites (s3, file, memory)")
+
+	var storageOpts string
+	flag.StringVar(&storageOpts, "storage-opts", "", "option string to use for the storage backend")
+
+	var buildWorkers int
+	flag.IntVar(&buildWorkers, "build-workers", runtime.NumCPU(), "the number of builds to run concurrently")
+
+	var buildQueueSize int
+	flag.IntVar(&buildQueueSize, "build-queue", 100, "the maximum number of builds waiting to run")
+
+	var failureTTL time.Duration
+	flag.DurationVar(&failureTTL, "failure-ttl", defaultFailureTTL, "how long a failed build is remembered before it may be retried")
+
+	var sourceOpts string
+	flag.StringVar(&sourceOpts, "sources", "", "a JSON list of GitLab and Gitea instances to build from")
+
+	var fetch string
+	flag.StringVar(&fetch, "fetch", "archive", "the method to fetch repositories (archive, git)")
+
+	var mirrorDir string
+	flag.StringVar(&mirrorDir, "mirror-dir", "", "the directory to keep git mirrors in, defaults to ${work}/mirrors if -work is set")
+
+	var host string
+	flag.StringVar(&host, "host", "jekyllhistory.org", "the host the service is served from")
+
+	var hostAliases string
+	flag.StringVar(&hostAliases, "host-aliases", "jekyllhistory.com", "a comma separated list of hosts to redirect to -host")
+
+	var wwwRedirect bool
+	flag.BoolVar(&wwwRedirect, "www-redirect", true, "redirect the www. subdomain of -host and -host-aliases")
+
+	var siteSuffix string
+	flag.StringVar(&siteSuffix, "site-host", "", "the domain built sites are served from as subdomains, defaults to -host")
+
+	var sitePaths bool
+	flag.BoolVar(&sitePaths, "site-paths", false, "serve built sites from /s/${tag}/ instead of from subdomains")
+
+	var groupcacheSelf string
+	flag.StringVar(&groupcacheSelf, "groupcache-self", "", "the base URL of this groupcache peer, defaults to http://${host}:${port}")
+
+	var groupcachePeers string
+	flag.StringVar(&groupcachePeers, "groupcache-peers", "", "a comma separated list of the base URLs of other groupcache peers")
+
+	var groupcachePeersFile string
+	flag.StringVar(&groupcachePeersFile, "groupcache-peers-file", "", "a file listing the base URLs of groupcache peers, one per line")
+
+	var groupcachePeersDNS string
+	flag.StringVar(&groupcachePeersDNS, "groupcache-peers-dns", "", "a comma separated list of DNS names to find groupcache peers with, as host:port for A records or _service._proto.domain for SRV records")
+
+	var groupcachePeersInterval time.Duration
+	flag.DurationVar(&groupcachePeersInterval, "groupcache-peers-interval", 30*time.Second, "how often to check -groupcache-peers-file and -groupcache-peers-dns")
+
+	var githubWeb string
+	flag.StringVar(&githubWeb, "github-url", "", "the URL of a GitHub Enterprise instance, defaults to https://github.com")
+
+	var githubAPI string
+	flag.StringVar(&githubAPI, "github-api-url", "", "the URL of the GitHub API, defaults to https://api.github.com/ or ${github-url}/api/v3/")
+
+	var githubUpload string
+	flag.StringVar(&githubUpload, "github-upload-url", "", "the URL of the GitHub upload API, defaults to https://uploads.github.com/ or ${github-url}/api/uploads/")
+
+	var githubStatuses bool
+	flag.BoolVar(&githubStatuses, "github-statuses", false, "report builds of GitHub commits as commit statuses, requires GitHub credentials")
+
+	var statusScheme string
+	flag.StringVar(&statusScheme, "status-scheme", "https", "the scheme of the links in commit statuses")
+
+	var hookRepos string
+	flag.StringVar(&hookRepos, "hook-repos", "", "a comma separated list of owner/repo patterns that may trigger builds with GitHub webhooks")
+
 	var highlightStyle string
 	flag.StringVar(&highlightStyle, "highlight-style", "https://cdnjs.cloudflare.com/ajax/libs/highlight.js/9.4.0/styles/github-gist.min.css", "the highlight.js stylesheet")
 
@@ -71,43 +158,146 @@ func main() {
 		fmt.Printf("using work directory '%s'\n", work)
 	}
 
-	s3Bucket, s3BucketNoGzip, err := getS3Buckets()
+	var store storage
+	var err error
+
+	switch storageKind {
+	case "s3":
+		store, err = getS3Storage()
+	case "file":
+		store, err = getFileStorage(storageOpts)
+	case "memory":
+		store = newMemoryStorage()
+	default:
+		panic(fmt.Errorf("invalid -storage flag value of '%s'", storageKind))
+	}
+
 	if err != nil {
 		panic(err)
 	}
 
-	githubClient, err := getGithubClient()
+	githubURLs, err := parseGithubURLs(githubWeb, githubAPI, githubUpload)
 	if err != nil {
 		panic(err)
 	}
 
-	var executeJekyll func(src, dst string) error
+	github, err := getGithubSource(githubURLs)
+	if err != nil {
+		panic(err)
+	}
 
-	switch jekyll {
-	case "shell":
-		executeJekyll, err = getExecuteShellJekyll(jekyllOpts)
-	case "docker":
-		executeJekyll, err = getExecuteDockerJekyll(jekyllOpts)
-	default:
-		panic(fmt.Errorf("invalid -jekyll flag value of '%s'", jekyll))
+	sources, err := getSources(sourceOpts, github)
+	if err != nil {
+		panic(err)
 	}
 
+	executor, err := newJekyllExecutor(jekyll, jekyllOpts)
 	if err != nil {
 		panic(err)
 	}
 
+	var mirror *gitMirror
+
+	switch fetch {
+	case "archive":
+	case "git":
+		if len(mirrorDir) == 0 {
+			// the default working directory is removed on
+			// exit, which would throw the mirrors away
+			if !hasWork {
+				panic("-fetch=git requires -mirror-dir or -work to be set")
+			}
+
+			mirrorDir = filepath.Join(work, "mirrors")
+		}
+
+		mirror = &gitMirror{
+			Dir: mirrorDir,
+		}
+	default:
+		panic(fmt.Errorf("invalid -fetch flag value of '%s'", fetch))
+	}
+
+	hosts := &hostConfig{
+		Primary:    host,
+		WWW:        wwwRedirect,
+		SiteSuffix: siteSuffix,
+		SitePaths:  sitePaths,
+	}
+
+	hosts.Aliases = splitList(hostAliases)
+
+	if len(groupcacheSelf) == 0 {
+		_, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			panic(err)
+		}
+
+		groupcacheSelf = "http://" + net.JoinHostPort(host, port)
+	}
+
 	buildJekyll, httpPool, poolOpts := getGroupcache(&buildJekyllGetter{
 		WorkingDirectory: work,
 
-		ExecuteJekyll: executeJekyll,
+		Executor: executor,
+		Timeout:  buildTimeout,
 
-		S3Bucket: s3Bucket,
+		FetchTimeout:  fetchTimeout,
+		ExtractLimits: extractLimits,
 
-		GithubClient: githubClient,
-	})
+		Storage: store,
+
+		FailureTTL: failureTTL,
 
-	router := getRouter(httpPool, poolOpts, githubClient, highlightStyle, buildJekyll, s3BucketNoGzip)
+		Sources: sources,
+		Hosts:   hosts,
+
+		Mirror: mirror,
+	}, groupcacheSelf)
+
+	(&peerDiscovery{
+		Pool: httpPool,
+
+		Self:   groupcacheSelf,
+		Static: splitList(groupcachePeers),
+		File:   groupcachePeersFile,
+		DNS:    splitList(groupcachePeersDNS),
+
+		Interval: groupcachePeersInterval,
+	}).Run()
+
+	queue := newBuildQueue(buildJekyll, buildWorkers, buildQueueSize)
+	queue.Storage = store
+
+	if githubStatuses {
+		if !github.authenticated() {
+			panic("-github-statuses requires GITHUB_TOKEN or GITHUB_APP_ID to be set")
+		}
+
+		queue.Notifier = &githubStatusNotifier{
+			Source: github,
+			Hosts:  hosts,
+			Scheme: statusScheme,
+		}
+	}
+
+	var hooks *githubHookConfig
+
+	if secret := os.Getenv("GITHUB_WEBHOOK_SECRET"); len(secret) != 0 {
+		hooks = &githubHookConfig{
+			Secret: secret,
+			Repos:  splitList(hookRepos),
+		}
+	}
+
+	router := getRouter(httpPool, poolOpts, groupcacheSelf, sources, highlightStyle, queue, store, hosts, hooks)
 
 	fmt.Printf("Listening on %s\n", addr)
-	log.Fatal(http.ListenAndServe(addr, router))
+	err = http.ListenAndServe(addr, router)
+
+	if err := executor.Close(); err != nil {
+		log.Printf("%[1]T: %[1]v", err)
+	}
+
+	log.Fatal(err)
 }
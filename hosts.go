@@ -0,0 +1,137 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// sitePathPrefix is the path that built sites are served
+// from when hostConfig.SitePaths is set.
+const sitePathPrefix = "/s/"
+
+// hostConfig describes the domains that the service is
+// reachable on.
+type hostConfig struct {
+	// Primary is the host the service is served from.
+	Primary string
+
+	// Aliases are redirected to Primary.
+	Aliases []string
+
+	// WWW redirects the www. subdomain of Primary and
+	// each alias to the bare domain.
+	WWW bool
+
+	// SiteSuffix is the domain that built sites are served
+	// from as <tag>.<SiteSuffix>. It defaults to Primary.
+	SiteSuffix string
+
+	// SitePaths serves built sites from /s/<tag>/ on Primary
+	// instead of from subdomains.
+	SitePaths bool
+}
+
+func (hc *hostConfig) siteSuffix() string {
+	if len(hc.SiteSuffix) != 0 {
+		return hc.SiteSuffix
+	}
+
+	return hc.Primary
+}
+
+// siteTag returns the tag of the built site served from
+// host, which must not include a port.
+func (hc *hostConfig) siteTag(host string) (tag string, ok bool) {
+	host = strings.ToLower(host)
+
+	suffix := "." + strings.ToLower(hc.siteSuffix())
+	if !strings.HasSuffix(host, suffix) {
+		return "", false
+	}
+
+	tag = host[:len(host)-len(suffix)]
+	if !isTag(tag) {
+		return "", false
+	}
+
+	return tag, true
+}
+
+func isTag(tag string) bool {
+	if len(tag) != 32 {
+		return false
+	}
+
+	_, err := hex.DecodeString(tag)
+	return err == nil
+}
+
+// baseURL returns the path that the built site for tag is
+// served from, which it is built with as its baseurl. Sites
+// served from subdomains have an empty baseurl, replacing
+// that of GitHub Pages project sites, such as /repo. A nil
+// config serves sites from subdomains.
+func (hc *hostConfig) baseURL(tag string) string {
+	if hc == nil || !hc.SitePaths {
+		return ""
+	}
+
+	return sitePathPrefix + tag
+}
+
+// siteURL returns the URL that the built site for tag
+// is served from.
+func (hc *hostConfig) siteURL(r *http.Request, tag, path, rawQuery string) string {
+	if hc.SitePaths {
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+
+		url := *r.URL
+		url.Path = sitePathPrefix + tag + path
+		url.RawQuery = rawQuery
+		return url.String()
+	}
+
+	host := tag + "." + hc.siteSuffix()
+
+	if _, port, err := net.SplitHostPort(r.Host); err == nil {
+		host = net.JoinHostPort(host, port)
+	}
+
+	url := *r.URL
+	url.Host = host
+	url.Path = path
+	url.RawQuery = rawQuery
+	return url.String()
+}
+
+// addHandlers registers the service, alias and www
+// handlers on hs.
+func (hc *hostConfig) addHandlers(hs *hostSwitch, handler http.Handler) {
+	hs.Add(hc.Primary, handler)
+
+	for _, alias := range hc.Aliases {
+		hs.Add(alias, hostRedirector{
+			Host: hc.Primary,
+			Code: http.StatusFound,
+		})
+	}
+
+	if !hc.WWW {
+		return
+	}
+
+	for _, host := range append([]string{hc.Primary}, hc.Aliases...) {
+		hs.Add("www."+host, hostRedirector{
+			Host: host,
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHostConfigSiteTag(t *testing.T) {
+	hc := &hostConfig{Primary: "example.com"}
+
+	for _, test := range []struct {
+		host string
+		tag  string
+		ok   bool
+	}{
+		{testTag + ".example.com", testTag, true},
+		{"0123456789ABCDEF0123456789ABCDEF.Example.COM", testTag, true},
+		{testTag + ".example.org", "", false},
+		{testTag + "example.com", "", false},
+		{"0123456789abcdef.example.com", "", false},
+		{"0123456789abcdefghijklmnopqrstuv.example.com", "", false},
+		{"example.com", "", false},
+	} {
+		if tag, ok := hc.siteTag(test.host); tag != test.tag || ok != test.ok {
+			t.Errorf("siteTag(%q) = (%q, %v), expected (%q, %v)", test.host, tag, ok, test.tag, test.ok)
+		}
+	}
+
+	hc.SiteSuffix = "sites.example.net"
+
+	if _, ok := hc.siteTag(testTag + ".example.com"); ok {
+		t.Error("siteTag accepted Primary when SiteSuffix was set")
+	}
+
+	if tag, ok := hc.siteTag(testTag + ".sites.example.net"); !ok || tag != testTag {
+		t.Errorf("siteTag did not accept SiteSuffix")
+	}
+}
+
+func TestHostConfigSiteURL(t *testing.T) {
+	hc := &hostConfig{
+		Primary:    "example.com",
+		SiteSuffix: "sites.example.net",
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com:8080/u/user/r/repo/c/commit/b/", nil)
+
+	if url, expect := hc.siteURL(req, testTag, "/about/", "a=b"), "http://"+testTag+".sites.example.net:8080/about/?a=b"; url != expect {
+		t.Errorf("siteURL returned %q, expected %q", url, expect)
+	}
+}
+
+func TestHostConfigAddHandlers(t *testing.T) {
+	hc := &hostConfig{
+		Primary: "example.com",
+		Aliases: []string{"example.org"},
+		WWW:     true,
+	}
+
+	hs := new(hostSwitch)
+	hc.addHandlers(hs, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, test := range []struct {
+		host     string
+		code     int
+		location string
+	}{
+		{"example.com", http.StatusOK, ""},
+		{"example.org", http.StatusFound, "http://example.com/"},
+		{"www.example.com", http.StatusMovedPermanently, "http://example.com/"},
+		{"www.example.org", http.StatusMovedPermanently, "http://example.org/"},
+		{"other.example.com", http.StatusForbidden, ""},
+	} {
+		rw := httptest.NewRecorder()
+		hs.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://"+test.host+"/", nil))
+
+		if rw.Code != test.code {
+			t.Errorf("%s returned wrong status code, expected %d, got %d", test.host, test.code, rw.Code)
+		}
+
+		if location := rw.HeaderMap.Get("Location"); location != test.location {
+			t.Errorf("%s returned wrong Location, expected %q, got %q", test.host, test.location, location)
+		}
+	}
+}
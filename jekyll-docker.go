@@ -6,7 +6,6 @@
 package main
 
 import (
-	"bytes"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
@@ -14,6 +13,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"sync"
 
 	"github.com/docker/engine-api/client"
@@ -24,7 +24,32 @@ import (
 	"golang.org/x/net/context"
 )
 
-func getExecuteDockerJekyll(optsflag string) (func(src, dst string) error, error) {
+func init() {
+	registerJekyllExecutor("docker", newDockerJekyllExecutor)
+}
+
+// dockerContainerConfig configures the containers builds
+// run in.
+type dockerContainerConfig struct {
+	container.Config
+	Host    container.HostConfig
+	Network network.NetworkingConfig
+}
+
+// dockerJekyllExecutor runs jekyll in a docker container.
+type dockerJekyllExecutor struct {
+	api       *client.Client
+	transport *http.Transport
+
+	config  dockerContainerConfig
+	args    []string
+	version string
+
+	seenWarnings   map[string]struct{}
+	seenWarningsMu sync.Mutex
+}
+
+func newDockerJekyllExecutor(optsflag string) (jekyllExecutor, error) {
 	opts := struct {
 		Host string
 
@@ -40,11 +65,7 @@ func getExecuteDockerJekyll(optsflag string) (func(src, dst string) error, error
 			Verify bool
 		}
 
-		Config struct {
-			container.Config
-			Host    container.HostConfig
-			Network network.NetworkingConfig
-		}
+		Config dockerContainerConfig
 	}{
 		Host: client.DefaultDockerHost,
 
@@ -63,11 +84,7 @@ func getExecuteDockerJekyll(optsflag string) (func(src, dst string) error, error
 			Verify: true,
 		},
 
-		Config: struct {
-			container.Config
-			Host    container.HostConfig
-			Network network.NetworkingConfig
-		}{
+		Config: dockerContainerConfig{
 			Config: container.Config{
 				Image: "jekyll/jekyll",
 
@@ -113,6 +130,7 @@ func getExecuteDockerJekyll(optsflag string) (func(src, dst string) error, error
 	}
 
 	var httpClient *http.Client
+	var transport *http.Transport
 
 	if opts.TLS.Use {
 		tlsc, err := tlsconfig.Client(tlsconfig.Options{
@@ -125,10 +143,11 @@ func getExecuteDockerJekyll(optsflag string) (func(src, dst string) error, error
 			return nil, err
 		}
 
+		transport = &http.Transport{
+			TLSClientConfig: tlsc,
+		}
 		httpClient = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: tlsc,
-			},
+			Transport: transport,
 		}
 	}
 
@@ -139,18 +158,19 @@ func getExecuteDockerJekyll(optsflag string) (func(src, dst string) error, error
 		return nil, err
 	}
 
-	if _, _, err := api.ImageInspectWithRaw(context.Background(), opts.Config.Image); err != nil {
+	image, _, err := api.ImageInspectWithRaw(context.Background(), opts.Config.Image)
+	if err != nil {
 		return nil, err
 	}
 
-	cmd := []string{"jekyll", "build", "--no-watch", "-s", "/srv/src", "-d", "/srv/dst"}
-
-	if debug {
-		cmd = append(cmd, "--trace", "--verbose")
-	}
+	var version string
 
-	if !verbose {
-		cmd = append(cmd, "--quiet")
+	if image.Config != nil {
+		for _, env := range image.Config.Env {
+			if strings.HasPrefix(env, "JEKYLL_VERSION=") {
+				version = strings.TrimPrefix(env, "JEKYLL_VERSION=")
+			}
+		}
 	}
 
 	opts.Config.AttachStdin = false
@@ -160,121 +180,157 @@ func getExecuteDockerJekyll(optsflag string) (func(src, dst string) error, error
 	opts.Config.OpenStdin = false
 
 	opts.Config.Env = opts.Env
-	opts.Config.Cmd = append(cmd, opts.Args...)
 
-	seenWarnings := make(map[string]struct{})
-	var seenWarningsMu sync.Mutex
+	return &dockerJekyllExecutor{
+		api:       api,
+		transport: transport,
 
-	return func(src, dst string) error {
-		if err := os.MkdirAll(dst, 0755); err != nil {
-			return err
-		}
+		config:  opts.Config,
+		args:    opts.Args,
+		version: version,
 
-		host := opts.Config.Host
-		host.Binds = append([]string{
-			fmt.Sprintf("%s:/srv/src:ro", src),
-			fmt.Sprintf("%s:/srv/dst", dst),
-		}, host.Binds...)
+		seenWarnings: make(map[string]struct{}),
+	}, nil
+}
 
-		resp, err := api.ContainerCreate(context.Background(), &opts.Config.Config, &host, &opts.Config.Network, "")
-		if err != nil {
-			return err
-		}
+func (e *dockerJekyllExecutor) Build(ctx context.Context, src, dst, baseURL string, out io.Writer) error {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
 
-		if !debug {
-			defer api.ContainerRemove(context.Background(), resp.ID, types.ContainerRemoveOptions{})
-		}
+	// the version is read back from the build log
+	if len(e.version) != 0 {
+		fmt.Fprintln(out, "jekyll "+e.version)
+	}
 
-		if len(resp.Warnings) != 0 {
-			seenWarningsMu.Lock()
+	host := e.config.Host
+	host.Binds = append([]string{
+		fmt.Sprintf("%s:/srv/src:ro", src),
+		fmt.Sprintf("%s:/srv/dst", dst),
+	}, host.Binds...)
 
-			var hadSeen int
+	cmd := []string{"jekyll", "build", "--no-watch", "-s", "/srv/src", "-d", "/srv/dst", "--baseurl", baseURL}
 
-			for _, warn := range resp.Warnings {
-				if _, seen := seenWarnings[warn]; seen {
-					hadSeen++
-					continue
-				}
+	if debug {
+		cmd = append(cmd, "--trace", "--verbose")
+	}
 
-				seenWarnings[warn] = struct{}{}
+	config := e.config.Config
+	config.Cmd = append(cmd, e.args...)
 
-				log.Printf("warning from docker: %s", warn)
-			}
+	resp, err := e.api.ContainerCreate(context.Background(), &config, &host, &e.config.Network, "")
+	if err != nil {
+		return err
+	}
+
+	if !debug {
+		defer e.api.ContainerRemove(context.Background(), resp.ID, types.ContainerRemoveOptions{})
+	}
+
+	if len(resp.Warnings) != 0 {
+		e.seenWarningsMu.Lock()
+
+		var hadSeen int
 
-			if hadSeen != 0 {
-				log.Printf("saw %d already seen warnings", hadSeen)
+		for _, warn := range resp.Warnings {
+			if _, seen := e.seenWarnings[warn]; seen {
+				hadSeen++
+				continue
 			}
 
-			seenWarningsMu.Unlock()
+			e.seenWarnings[warn] = struct{}{}
+
+			log.Printf("warning from docker: %s", warn)
 		}
 
-		if err = api.ContainerStart(context.Background(), resp.ID, types.ContainerStartOptions{}); err != nil {
-			return err
+		if hadSeen != 0 {
+			log.Printf("saw %d already seen warnings", hadSeen)
 		}
 
-		if logs, err := api.ContainerLogs(context.Background(), resp.ID, types.ContainerLogsOptions{
-			ShowStdout: verbose,
-			ShowStderr: true,
+		e.seenWarningsMu.Unlock()
+	}
 
-			Timestamps: true,
-			Follow:     true,
-		}); err != nil {
-			log.Printf("%[1]T: %[1]v", err)
-		} else {
-			go func() {
-				defer logs.Close()
-
-				logHeader := bytes.NewReader([]byte(fmt.Sprintf("log from %s: ", resp.ID[:12])))
-
-				var hdr [8]byte
-
-				for {
-					if _, err := io.ReadFull(logs, hdr[:]); err == io.EOF {
-						break
-					} else if err != nil {
-						log.Printf("%[1]T: %[1]v", err)
-						return
-					}
-
-					var out io.Writer
-
-					switch hdr[0] {
-					case 0: /* stdin */
-						panic("unreachable")
-					case 1: /* stdout */
-						out = os.Stdout
-					case 2: /* stderr */
-						out = os.Stderr
-					default:
-						panic("unreachable")
-					}
-
-					size := binary.BigEndian.Uint32(hdr[4:])
-
-					if _, err := io.Copy(out, io.MultiReader(logHeader, &io.LimitedReader{
-						R: logs,
-						N: int64(size),
-					})); err != nil {
-						log.Printf("%[1]T: %[1]v", err)
-						return
-					}
-
-					if _, err := logHeader.Seek(0, 0); err != nil {
-						log.Printf("%[1]T: %[1]v", err)
-					}
+	if err = e.api.ContainerStart(context.Background(), resp.ID, types.ContainerStartOptions{}); err != nil {
+		return err
+	}
+
+	logsDone := make(chan struct{})
+
+	if logs, err := e.api.ContainerLogs(context.Background(), resp.ID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+
+		Follow: true,
+	}); err != nil {
+		log.Printf("%[1]T: %[1]v", err)
+		close(logsDone)
+	} else {
+		go func() {
+			defer close(logsDone)
+			defer logs.Close()
+
+			var hdr [8]byte
+
+			for {
+				if _, err := io.ReadFull(logs, hdr[:]); err == io.EOF {
+					break
+				} else if err != nil {
+					log.Printf("%[1]T: %[1]v", err)
+					return
 				}
-			}()
-		}
 
-		code, err := api.ContainerWait(context.Background(), resp.ID)
-		if err != nil {
-			return err
-		}
+				switch hdr[0] {
+				case 1: /* stdout */
+				case 2: /* stderr */
+				default:
+					panic("unreachable")
+				}
+
+				size := binary.BigEndian.Uint32(hdr[4:])
 
-		if code != 0 {
-			return fmt.Errorf("exit status %d", code)
+				if _, err := io.Copy(out, &io.LimitedReader{
+					R: logs,
+					N: int64(size),
+				}); err != nil {
+					log.Printf("%[1]T: %[1]v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	code, err := e.api.ContainerWait(ctx, resp.ID)
+	if ctx.Err() != nil {
+		if err := e.api.ContainerKill(context.Background(), resp.ID, "KILL"); err != nil {
+			log.Printf("%[1]T: %[1]v", err)
 		}
 
-		return nil
-	}, nil
+		<-logsDone
+		return ctx.Err()
+	} else if err != nil {
+		return err
+	}
+
+	<-logsDone
+
+	if code != 0 {
+		return &jekyllExitError{int(code)}
+	}
+
+	return nil
+}
+
+func (e *dockerJekyllExecutor) Capabilities() jekyllCapabilities {
+	return jekyllCapabilities{
+		Version:  e.version,
+		Isolated: e.config.NetworkDisabled,
+	}
+}
+
+func (e *dockerJekyllExecutor) Close() error {
+	if e.transport != nil {
+		e.transport.CloseIdleConnections()
+	}
+
+	return nil
 }
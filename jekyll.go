@@ -6,21 +6,142 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
-	"os"
+	"fmt"
+	"io"
+	"log"
 	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
-var defaultExecuteJekyll func(src, dst string) error
+// jekyllExecutor runs jekyll to build sites.
+type jekyllExecutor interface {
+	// Build builds the site at src into dst, to be served
+	// from baseURL, writing the build log to out. It returns
+	// ctx.Err() if the build was stopped because ctx was
+	// done.
+	Build(ctx context.Context, src, dst, baseURL string, out io.Writer) error
+
+	// Capabilities describes the builds the executor runs.
+	Capabilities() jekyllCapabilities
+
+	// Close releases any resources held by the executor.
+	Close() error
+}
+
+// jekyllCapabilities describes the builds an executor runs.
+type jekyllCapabilities struct {
+	// Version is the version of jekyll used, if known.
+	Version string
+
+	// Isolated is set if builds have no access to the
+	// network or to the host beyond src and dst.
+	Isolated bool
+}
+
+// jekyllExecutorFunc adapts a function to a jekyllExecutor
+// with no known capabilities.
+type jekyllExecutorFunc func(ctx context.Context, src, dst, baseURL string, out io.Writer) error
+
+func (fn jekyllExecutorFunc) Build(ctx context.Context, src, dst, baseURL string, out io.Writer) error {
+	return fn(ctx, src, dst, baseURL, out)
+}
+
+func (jekyllExecutorFunc) Capabilities() jekyllCapabilities {
+	return jekyllCapabilities{}
+}
+
+func (jekyllExecutorFunc) Close() error {
+	return nil
+}
+
+// jekyllExecutorFactory creates an executor from the JSON
+// option string given with -jekyll-opts.
+type jekyllExecutorFactory func(optsflag string) (jekyllExecutor, error)
+
+var jekyllExecutors = make(map[string]jekyllExecutorFactory)
+
+// registerJekyllExecutor makes an executor available to
+// the -jekyll flag as name. It is meant to be called from
+// init and panics if name is already registered.
+func registerJekyllExecutor(name string, factory jekyllExecutorFactory) {
+	if _, dup := jekyllExecutors[name]; dup {
+		panic(fmt.Errorf("jekyll executor '%s' registered twice", name))
+	}
+
+	jekyllExecutors[name] = factory
+}
+
+// jekyllExecutorNames returns the names of the registered
+// executors in order.
+func jekyllExecutorNames() []string {
+	names := make([]string, 0, len(jekyllExecutors))
+	for name := range jekyllExecutors {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// newJekyllExecutor creates the executor registered as name.
+func newJekyllExecutor(name, optsflag string) (jekyllExecutor, error) {
+	factory, ok := jekyllExecutors[name]
+	if !ok {
+		return nil, fmt.Errorf("invalid -jekyll flag value of '%s'", name)
+	}
+
+	return factory(optsflag)
+}
+
+var defaultJekyllExecutor jekyllExecutor
+
+// jekyllExitError is returned when jekyll ran to completion
+// but exited with a non-zero status.
+type jekyllExitError struct {
+	Status int
+}
+
+func (e *jekyllExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Status)
+}
+
+// isJekyllExitError reports whether err means that jekyll
+// failed to build the site, rather than failed to run.
+func isJekyllExitError(err error) bool {
+	switch err.(type) {
+	case *exec.ExitError, *jekyllExitError:
+		return true
+	default:
+		return false
+	}
+}
 
 func init() {
+	registerJekyllExecutor("shell", newShellJekyllExecutor)
+
 	var err error
-	if defaultExecuteJekyll, err = getExecuteShellJekyll(""); err != nil {
+	if defaultJekyllExecutor, err = newShellJekyllExecutor(""); err != nil {
 		panic(err)
 	}
 }
 
-func getExecuteShellJekyll(optsflag string) (func(src, dst string) error, error) {
+// shellJekyllExecutor runs jekyll from the PATH.
+type shellJekyllExecutor struct {
+	env  []string
+	args []string
+
+	versionMu    sync.Mutex
+	version      string
+	versionRetry time.Time
+	versionProbe chan struct{}
+}
+
+func newShellJekyllExecutor(optsflag string) (jekyllExecutor, error) {
 	opts := struct {
 		Env  []string
 		Args []string
@@ -41,18 +162,121 @@ func getExecuteShellJekyll(optsflag string) (func(src, dst string) error, error)
 		args = append(args, "--trace", "--verbose")
 	}
 
-	if !verbose {
-		args = append(args, "--quiet")
+	return &shellJekyllExecutor{
+		env:  opts.Env,
+		args: append(args, opts.Args...),
+	}, nil
+}
+
+// jekyllVersionTimeout is how long jekyll --version may
+// take, and jekyllVersionRetry is how long to wait before
+// running it again if it failed.
+const (
+	jekyllVersionTimeout = 10 * time.Second
+	jekyllVersionRetry   = time.Minute
+)
+
+// detectVersion returns the output of jekyll --version.
+// It is run when first needed and is remembered once it
+// succeeds. Builds that need it while it runs wait for it
+// rather than running it again.
+func (e *shellJekyllExecutor) detectVersion(ctx context.Context) string {
+	e.versionMu.Lock()
+
+	if len(e.version) != 0 || time.Now().Before(e.versionRetry) {
+		version := e.version
+		e.versionMu.Unlock()
+		return version
 	}
 
-	args = append(args, opts.Args...)
+	if probe := e.versionProbe; probe != nil {
+		e.versionMu.Unlock()
 
-	return func(src, dst string) error {
-		cmd := exec.Command("jekyll", append([]string{"build", "-s", src, "-d", dst}, args...)...)
-		cmd.Dir = src
-		cmd.Env = opts.Env
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		return cmd.Run()
-	}, nil
+		select {
+		case <-probe:
+		case <-ctx.Done():
+			return ""
+		}
+
+		e.versionMu.Lock()
+		defer e.versionMu.Unlock()
+		return e.version
+	}
+
+	probe := make(chan struct{})
+	e.versionProbe = probe
+	e.versionMu.Unlock()
+
+	// the lock is not held while jekyll runs, and it is not
+	// stopped early for the build that happened to start it
+	probeCtx, cancel := context.WithTimeout(context.Background(), jekyllVersionTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(probeCtx, "jekyll", "--version")
+	cmd.Env = e.env
+
+	v, err := cmd.Output()
+
+	e.versionMu.Lock()
+	if err != nil {
+		log.Printf("%[1]T: %[1]v", err)
+
+		e.versionRetry = time.Now().Add(jekyllVersionRetry)
+	} else {
+		e.version = strings.TrimSpace(string(v))
+	}
+
+	version := e.version
+	e.versionProbe = nil
+	e.versionMu.Unlock()
+
+	close(probe)
+	return version
+}
+
+func (e *shellJekyllExecutor) Build(ctx context.Context, src, dst, baseURL string, out io.Writer) error {
+	// the version is read back from the build log
+	if version := e.detectVersion(ctx); len(version) != 0 {
+		fmt.Fprintln(out, version)
+	}
+
+	cmd := exec.Command("jekyll", append([]string{"build", "-s", src, "-d", dst, "--baseurl", baseURL}, e.args...)...)
+	cmd.Dir = src
+	cmd.Env = e.env
+	cmd.Stdout = out
+	cmd.Stderr = out
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		// jekyll may have started child processes that
+		// hold stdout open, so kill the whole group
+		if err := killProcessGroup(cmd); err != nil {
+			log.Printf("%[1]T: %[1]v", err)
+		}
+
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (e *shellJekyllExecutor) Capabilities() jekyllCapabilities {
+	return jekyllCapabilities{
+		Version: strings.TrimPrefix(e.detectVersion(context.Background()), "jekyll "),
+	}
+}
+
+func (*shellJekyllExecutor) Close() error {
+	return nil
 }
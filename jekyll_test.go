@@ -0,0 +1,200 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExecuteShellJekyllTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	dir, err := ioutil.TempDir("", "jekyll-timeout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	// the child holds stdout open after the shell is killed
+	if err := ioutil.WriteFile(filepath.Join(dir, "jekyll"), []byte("#!/bin/sh\n[ \"$1\" = --version ] && echo jekyll 3.1.6 && exit\nsleep 60 &\nwait\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", dir)
+
+	executor, err := newShellJekyllExecutor("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	var out bytes.Buffer
+
+	if err := executor.Build(ctx, dir, filepath.Join(dir, "site"), "", &out); err != context.DeadlineExceeded {
+		t.Errorf("Build returned %v, expected %v", err, context.DeadlineExceeded)
+	}
+
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Build took %s to return after timeout", elapsed)
+	}
+
+	if version, _ := parseBuildLog(out.Bytes()); version != "3.1.6" {
+		t.Errorf("build log has version %q, expected %q", version, "3.1.6")
+	}
+
+	if caps := executor.Capabilities(); caps.Version != "3.1.6" || caps.Isolated {
+		t.Errorf("Capabilities returned %+v, expected version %q and not isolated", caps, "3.1.6")
+	}
+}
+
+func TestShellJekyllVersionRetry(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	dir, err := ioutil.TempDir("", "jekyll-version")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	// the first run fails, as if jekyll were still being
+	// installed
+	marker := filepath.Join(dir, "ran")
+	if err := ioutil.WriteFile(filepath.Join(dir, "jekyll"), []byte("#!/bin/sh\n[ -e "+marker+" ] && echo jekyll 3.1.6 && exit\n: > "+marker+"\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", dir)
+
+	executor, err := newShellJekyllExecutor("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := executor.(*shellJekyllExecutor)
+
+	if version := e.detectVersion(context.Background()); len(version) != 0 {
+		t.Fatalf("detectVersion returned %q, expected nothing", version)
+	}
+
+	// a failure is not retried straight away
+	if version := e.detectVersion(context.Background()); len(version) != 0 {
+		t.Fatalf("detectVersion returned %q before the retry, expected nothing", version)
+	}
+
+	e.versionRetry = time.Time{}
+
+	if version := e.detectVersion(context.Background()); version != "jekyll 3.1.6" {
+		t.Errorf("detectVersion returned %q after the retry, expected %q", version, "jekyll 3.1.6")
+	}
+}
+
+func TestShellJekyllVersionOnce(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	dir, err := ioutil.TempDir("", "jekyll-version")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	// each run is counted, and is slow enough for the
+	// builds to overlap
+	count := filepath.Join(dir, "count")
+	if err := ioutil.WriteFile(filepath.Join(dir, "jekyll"), []byte("#!/bin/sh\necho >> "+count+"\nsleep 0.2\necho jekyll 3.1.6\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	executor, err := newShellJekyllExecutor("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := executor.(*shellJekyllExecutor)
+
+	var wg sync.WaitGroup
+	versions := make([]string, 4)
+
+	for i := range versions {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			versions[i] = e.detectVersion(context.Background())
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, version := range versions {
+		if version != "jekyll 3.1.6" {
+			t.Errorf("detectVersion returned %q, expected %q", version, "jekyll 3.1.6")
+		}
+	}
+
+	if runs, err := ioutil.ReadFile(count); err != nil {
+		t.Error(err)
+	} else if len(runs) != 1 {
+		t.Errorf("jekyll --version was run %d times, expected once", len(runs))
+	}
+}
+
+func TestJekyllExecutorRegistry(t *testing.T) {
+	if names := jekyllExecutorNames(); !reflect.DeepEqual(names, []string{"docker", "shell"}) {
+		t.Errorf("jekyllExecutorNames returned %v, expected [docker shell]", names)
+	}
+
+	if _, err := newJekyllExecutor("missing", ""); err == nil {
+		t.Error("newJekyllExecutor did not fail for an unregistered executor")
+	}
+
+	executor, err := newJekyllExecutor("shell", `{"Args":[]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if se, ok := executor.(*shellJekyllExecutor); !ok || len(se.args) != 1 {
+		t.Errorf("newJekyllExecutor returned %#v, expected a shell executor without --safe", executor)
+	}
+
+	if _, err := newJekyllExecutor("shell", "{"); err == nil {
+		t.Error("newJekyllExecutor did not fail for invalid options")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registerJekyllExecutor did not panic for a duplicate name")
+		}
+	}()
+
+	registerJekyllExecutor("shell", newShellJekyllExecutor)
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 	"sync"
 )
 
@@ -31,6 +32,33 @@ func copyBuffer(dst io.Writer, src io.Reader) (written int64, err error) {
 	return
 }
 
+// limitWriter writes at most N bytes to W and silently
+// discards the remainder.
+type limitWriter struct {
+	W io.Writer
+	N int64
+}
+
+func (l *limitWriter) Write(p []byte) (n int, err error) {
+	if l.N <= 0 {
+		return len(p), nil
+	}
+
+	q := p
+	if int64(len(q)) > l.N {
+		q = q[:l.N]
+	}
+
+	n, err = l.W.Write(q)
+	l.N -= int64(n)
+
+	if err != nil {
+		return n, err
+	}
+
+	return len(p), nil
+}
+
 func parsePageString(page string) (int, bool, error) {
 	if len(page) == 0 {
 		return 1, false, nil
@@ -46,3 +74,17 @@ func parsePageString(page string) (int, bool, error) {
 		return num, false, nil
 	}
 }
+
+// splitList splits a comma separated list, ignoring empty
+// entries.
+func splitList(list string) []string {
+	var items []string
+
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); len(item) != 0 {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -118,6 +118,21 @@ func BenchmarkIoCopy100m(b *testing.B) {
 	benchmarkCopyFunc(b, io.Copy, 100*1024*1024)
 }
 
+func TestLimitWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &limitWriter{W: &buf, N: 8}
+
+	for _, s := range [...]string{"hello", ", world", "."} {
+		if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+			t.Errorf("limitWriter.Write(%q) = (%d, %v), expected (%d, <nil>)", s, n, err, len(s))
+		}
+	}
+
+	if buf.String() != "hello, w" {
+		t.Errorf("limitWriter wrote %q, expected %q", buf.String(), "hello, w")
+	}
+}
+
 func TestParsePageString(t *testing.T) {
 	if n, redirect, err := parsePageString(""); n != 1 || redirect || err != nil {
 		t.Errorf(`parsePageString("") = (%d, %t, %v), expected (1, false, <nil>)`, n, redirect, err)
@@ -0,0 +1,192 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"log"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type peerSetter interface {
+	Set(peers ...string)
+}
+
+// peerDiscovery keeps the groupcache peers up to date from
+// a static list, a peers file and DNS.
+type peerDiscovery struct {
+	Pool peerSetter
+
+	// Self is the URL of this peer. It must be written the
+	// same way other peers are discovered.
+	Self string
+
+	Static []string
+
+	// File lists one peer URL per line. Blank lines and
+	// lines starting with # are ignored.
+	File string
+
+	// DNS names either host:port, to be resolved to A and
+	// AAAA records, or _service._proto.name, to be resolved
+	// to SRV records. Peers found this way use http.
+	DNS []string
+
+	// Interval is how often File and DNS are checked.
+	Interval time.Duration
+
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+	lookupSRV  func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+
+	// found holds the last good result of each lookup so
+	// that a failed lookup does not drop peers.
+	found map[string][]string
+	peers string
+}
+
+func (pd *peerDiscovery) readFile() ([]string, error) {
+	f, err := os.Open(pd.File)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var peers []string
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); len(line) != 0 && line[0] != '#' {
+			peers = append(peers, line)
+		}
+	}
+
+	return peers, s.Err()
+}
+
+func (pd *peerDiscovery) lookup(ctx context.Context, name string) ([]string, error) {
+	var peers []string
+
+	if strings.HasPrefix(name, "_") {
+		parts := strings.SplitN(name, ".", 3)
+		if len(parts) != 3 {
+			return nil, &net.DNSError{Err: "invalid SRV name", Name: name}
+		}
+
+		lookupSRV := pd.lookupSRV
+		if lookupSRV == nil {
+			lookupSRV = net.DefaultResolver.LookupSRV
+		}
+
+		_, addrs, err := lookupSRV(ctx, parts[0][1:], parts[1][1:], parts[2])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, addr := range addrs {
+			host := strings.TrimSuffix(addr.Target, ".")
+			peers = append(peers, "http://"+net.JoinHostPort(host, strconv.Itoa(int(addr.Port))))
+		}
+
+		return peers, nil
+	}
+
+	host, port, err := net.SplitHostPort(name)
+	if err != nil {
+		return nil, err
+	}
+
+	lookupHost := pd.lookupHost
+	if lookupHost == nil {
+		lookupHost = net.DefaultResolver.LookupHost
+	}
+
+	addrs, err := lookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range addrs {
+		peers = append(peers, "http://"+net.JoinHostPort(addr, port))
+	}
+
+	return peers, nil
+}
+
+// update sets the peers of Pool if they have changed.
+func (pd *peerDiscovery) update(ctx context.Context) {
+	if pd.found == nil {
+		pd.found = make(map[string][]string)
+	}
+
+	if len(pd.File) != 0 {
+		if peers, err := pd.readFile(); err != nil {
+			log.Printf("%[1]T: %[1]v", err)
+		} else {
+			pd.found["file:"+pd.File] = peers
+		}
+	}
+
+	for _, name := range pd.DNS {
+		if peers, err := pd.lookup(ctx, name); err != nil {
+			log.Printf("%[1]T: %[1]v", err)
+		} else {
+			pd.found["dns:"+name] = peers
+		}
+	}
+
+	set := map[string]struct{}{
+		pd.Self: {},
+	}
+
+	for _, peer := range pd.Static {
+		set[peer] = struct{}{}
+	}
+
+	for _, peers := range pd.found {
+		for _, peer := range peers {
+			set[peer] = struct{}{}
+		}
+	}
+
+	peers := make([]string, 0, len(set))
+	for peer := range set {
+		peers = append(peers, peer)
+	}
+
+	sort.Strings(peers)
+
+	if joined := strings.Join(peers, ","); joined != pd.peers {
+		pd.peers = joined
+		pd.Pool.Set(peers...)
+
+		log.Printf("groupcache peers: %s", joined)
+	}
+}
+
+// Run updates the peers now and then every Interval if
+// there is a peers file or DNS names to check.
+func (pd *peerDiscovery) Run() {
+	pd.update(context.Background())
+
+	if len(pd.File) == 0 && len(pd.DNS) == 0 {
+		return
+	}
+
+	go func() {
+		for range time.Tick(pd.Interval) {
+			ctx, cancel := context.WithTimeout(context.Background(), pd.Interval)
+			pd.update(ctx)
+			cancel()
+		}
+	}()
+}
@@ -0,0 +1,104 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"reflect"
+	"testing"
+)
+
+type fakePeerPool struct {
+	Peers []string
+	Sets  int
+}
+
+func (p *fakePeerPool) Set(peers ...string) {
+	p.Peers = peers
+	p.Sets++
+}
+
+func TestPeerDiscovery(t *testing.T) {
+	f, err := ioutil.TempFile("", "peers")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+
+	f.WriteString("# peers\nhttp://10.0.0.3:8080\n\nhttp://10.0.0.1:8080\n")
+	f.Close()
+
+	dnsFails := false
+
+	pool := new(fakePeerPool)
+	pd := &peerDiscovery{
+		Pool: pool,
+
+		Self:   "http://10.0.0.1:8080",
+		Static: []string{"http://10.0.0.2:8080"},
+		File:   f.Name(),
+		DNS:    []string{"peers.example.com:8080", "_groupcache._tcp.example.com"},
+
+		lookupHost: func(ctx context.Context, host string) ([]string, error) {
+			if dnsFails {
+				return nil, errors.New("lookup failed")
+			}
+
+			if host != "peers.example.com" {
+				t.Errorf("lookupHost called with %s", host)
+			}
+
+			return []string{"10.0.0.4", "fd00::5"}, nil
+		},
+		lookupSRV: func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+			if service != "groupcache" || proto != "tcp" || name != "example.com" {
+				t.Errorf("lookupSRV called with %s, %s, %s", service, proto, name)
+			}
+
+			return "", []*net.SRV{{Target: "peer6.example.com.", Port: 9000}}, nil
+		},
+	}
+
+	expected := []string{
+		"http://10.0.0.1:8080",
+		"http://10.0.0.2:8080",
+		"http://10.0.0.3:8080",
+		"http://10.0.0.4:8080",
+		"http://[fd00::5]:8080",
+		"http://peer6.example.com:9000",
+	}
+
+	pd.update(context.Background())
+
+	if !reflect.DeepEqual(pool.Peers, expected) {
+		t.Errorf("peers were %v, expected %v", pool.Peers, expected)
+	}
+
+	// failed lookups keep the last good result
+	dnsFails = true
+	pd.update(context.Background())
+
+	if pool.Sets != 1 {
+		t.Errorf("Set was called %d times, expected 1", pool.Sets)
+	}
+
+	if err := ioutil.WriteFile(f.Name(), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pd.update(context.Background())
+
+	expected = append(expected[:2], expected[3:]...)
+
+	if !reflect.DeepEqual(pool.Peers, expected) {
+		t.Errorf("peers were %v, expected %v", pool.Peers, expected)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func getPullsHandler(sources sourceRegistry) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var cacheControl = fmt.Sprintf("public, max-age=%d", time.Minute/time.Second)
+
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		h := w.Header()
+		h.Set("Cache-Control", cacheControl)
+
+		if checkLastModified(w, r, time.Now(), time.Minute) {
+			return
+		}
+
+		source, ok := sources.fromParams(ps)
+		pulls, hasPulls := source.(pullRequestSource)
+		if !ok || !hasPulls {
+			h.Del("Cache-Control")
+
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		page, redirect, err := parsePageString(ps.ByName("page"))
+		if err != nil {
+			h.Del("Cache-Control")
+
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		} else if redirect {
+			localRedirect(w, r, "../../")
+			return
+		}
+
+		user, repo := ps.ByName("user"), ps.ByName("repo")
+
+		list, resp, err := pulls.ListPulls(context.Background(), user, repo, page)
+		if err != nil {
+			h.Del("Cache-Control")
+
+			code := sourceErrorCode(err)
+			if code != http.StatusNotFound {
+				log.Printf("%[1]T %[1]v", err)
+			}
+
+			http.Error(w, http.StatusText(code), code)
+			return
+		}
+
+		if wrote, err := executeTemplate(pullsTemplate, struct {
+			Source sourceProvider
+			Prefix string
+
+			User  string
+			Repo  string
+			Pulls []*sourcePull
+			Resp  *sourcePage
+		}{
+			Source: source,
+			Prefix: sourcePrefix(source.Name()),
+
+			User:  user,
+			Repo:  repo,
+			Pulls: list,
+			Resp:  resp,
+		}, w); err != nil {
+			log.Printf("%[1]T %[1]v", err)
+
+			if !wrote {
+				h.Del("Cache-Control")
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}
+	}
+}
+
+func getPullHandler(sources sourceRegistry) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var cacheControl = fmt.Sprintf("public, max-age=%d", time.Minute/time.Second)
+
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		h := w.Header()
+		h.Set("Cache-Control", cacheControl)
+
+		if checkLastModified(w, r, time.Now(), time.Minute) {
+			return
+		}
+
+		source, ok := sources.fromParams(ps)
+		pulls, hasPulls := source.(pullRequestSource)
+		if !ok || !hasPulls {
+			h.Del("Cache-Control")
+
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		number, err := strconv.Atoi(ps.ByName("number"))
+		if err != nil || number <= 0 {
+			h.Del("Cache-Control")
+
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		user, repo := ps.ByName("user"), ps.ByName("repo")
+
+		pull, err := pulls.GetPull(context.Background(), user, repo, number)
+		if err != nil {
+			h.Del("Cache-Control")
+
+			code := sourceErrorCode(err)
+			if code != http.StatusNotFound {
+				log.Printf("%[1]T %[1]v", err)
+			}
+
+			http.Error(w, http.StatusText(code), code)
+			return
+		}
+
+		if wrote, err := executeTemplate(pullTemplate, struct {
+			Source sourceProvider
+			Prefix string
+
+			User string
+			Repo string
+			Pull *sourcePull
+		}{
+			Source: source,
+			Prefix: sourcePrefix(source.Name()),
+
+			User: user,
+			Repo: repo,
+			Pull: pull,
+		}, w); err != nil {
+			log.Printf("%[1]T %[1]v", err)
+
+			if !wrote {
+				h.Del("Cache-Control")
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}
+	}
+}
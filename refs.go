@@ -0,0 +1,108 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// getRefsHandler returns a handler that lists the branches
+// of a repository, or its tags if tags is set.
+func getRefsHandler(sources sourceRegistry, tags bool) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var cacheControl = fmt.Sprintf("public, max-age=%d", time.Minute/time.Second)
+
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		h := w.Header()
+		h.Set("Cache-Control", cacheControl)
+
+		if checkLastModified(w, r, time.Now(), time.Minute) {
+			return
+		}
+
+		source, ok := sources.fromParams(ps)
+		rs, hasRefs := source.(refSource)
+		if !ok || !hasRefs {
+			h.Del("Cache-Control")
+
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		user, repo := ps.ByName("user"), ps.ByName("repo")
+
+		listRefs, kind := rs.ListBranches, "branches"
+		if tags {
+			listRefs, kind = rs.ListTags, "tags"
+		}
+
+		refs, more, err := listRefs(context.Background(), user, repo)
+		if err != nil {
+			h.Del("Cache-Control")
+
+			code := sourceErrorCode(err)
+			if code != http.StatusNotFound {
+				log.Printf("%[1]T %[1]v", err)
+			}
+
+			http.Error(w, http.StatusText(code), code)
+			return
+		}
+
+		sortRefs(refs)
+
+		type ref struct {
+			*sourceRef
+
+			// Tree is the ref name, or the commit if the
+			// name cannot be used in a route.
+			Tree string
+		}
+
+		entries := make([]ref, len(refs))
+
+		for i, r := range refs {
+			entries[i] = ref{r, r.Name}
+
+			if strings.Contains(r.Name, "/") {
+				entries[i].Tree = r.SHA
+			}
+		}
+
+		if wrote, err := executeTemplate(refsTemplate, struct {
+			Source sourceProvider
+			Prefix string
+
+			User string
+			Repo string
+			Kind string
+			Refs []ref
+			More bool
+		}{
+			Source: source,
+			Prefix: sourcePrefix(source.Name()),
+
+			User: user,
+			Repo: repo,
+			Kind: kind,
+			Refs: entries,
+			More: more,
+		}, w); err != nil {
+			log.Printf("%[1]T %[1]v", err)
+
+			if !wrote {
+				h.Del("Cache-Control")
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}
+	}
+}
@@ -12,11 +12,10 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/google/go-github/github"
 	"github.com/julienschmidt/httprouter"
 )
 
-func getRepoHandler(githubClient *github.Client) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+func getRepoHandler(sources sourceRegistry) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	var cacheControl = fmt.Sprintf("public, max-age=%d", time.Minute/time.Second)
 
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
@@ -27,6 +26,14 @@ func getRepoHandler(githubClient *github.Client) func(w http.ResponseWriter, r *
 			return
 		}
 
+		source, ok := sources.fromParams(ps)
+		if !ok {
+			h.Del("Cache-Control")
+
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
 		page, redirect, err := parsePageString(ps.ByName("page"))
 		if err != nil {
 			h.Del("Cache-Control")
@@ -40,42 +47,42 @@ func getRepoHandler(githubClient *github.Client) func(w http.ResponseWriter, r *
 
 		user, repo, tree := ps.ByName("user"), ps.ByName("repo"), ps.ByName("tree")
 
-		commits, resp, err := githubClient.Repositories.ListCommits(context.Background(), user, repo, &github.CommitsListOptions{
-			SHA: tree,
+		_, hasRefs := source.(refSource)
+		_, hasPulls := source.(pullRequestSource)
 
-			ListOptions: github.ListOptions{
-				Page: page,
-
-				PerPage: 50,
-			},
-		})
+		commits, resp, err := source.ListCommits(context.Background(), user, repo, tree, page)
 		if err != nil {
 			h.Del("Cache-Control")
 
-			if gerr, ok := err.(*github.ErrorResponse); ok && gerr.Response.StatusCode == http.StatusNotFound {
-				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
-			} else {
+			code := sourceErrorCode(err)
+			if code != http.StatusNotFound {
 				log.Printf("%[1]T %[1]v", err)
-				http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
 			}
 
+			http.Error(w, http.StatusText(code), code)
 			return
 		}
 
-		if verbose {
-			log.Printf("GitHub API Rate Limit is %d remaining of %d, to be reset at %s\n", resp.Remaining, resp.Limit, resp.Reset)
-		}
-
 		if wrote, err := executeTemplate(repoTemplate, struct {
+			Source sourceProvider
+			Prefix string
+
 			User    string
 			Repo    string
 			Tree    string
-			Commits []*github.RepositoryCommit
-			Resp    *github.Response
+			Refs    bool
+			Pulls   bool
+			Commits []*sourceCommit
+			Resp    *sourcePage
 		}{
+			Source: source,
+			Prefix: sourcePrefix(source.Name()),
+
 			User:    user,
 			Repo:    repo,
 			Tree:    tree,
+			Refs:    hasRefs,
+			Pulls:   hasPulls,
 			Commits: commits,
 			Resp:    resp,
 		}, w); err != nil {
@@ -0,0 +1,53 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func isCommitSHA(commit string) bool {
+	if len(commit) != 40 {
+		return false
+	}
+
+	for _, c := range commit {
+		switch {
+		case '0' <= c && c <= '9':
+		case 'a' <= c && c <= 'f':
+		case 'A' <= c && c <= 'F':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// resolveCommit resolves a branch, tag or abbreviated commit to
+// its full, lower case, commit SHA.
+//
+// The returned code is the HTTP status code that should be
+// returned to the client if err is non-nil.
+func resolveCommit(source sourceProvider, user, repo, commit string) (sha string, code int, err error) {
+	if isCommitSHA(commit) {
+		return strings.ToLower(commit), http.StatusOK, nil
+	}
+
+	sha, err = source.ResolveCommit(context.Background(), user, repo, commit)
+	if err != nil {
+		return "", sourceErrorCode(err), err
+	}
+
+	if !isCommitSHA(sha) {
+		return "", http.StatusBadGateway, fmt.Errorf("invalid commit SHA '%s' returned for '%s'", sha, commit)
+	}
+
+	return strings.ToLower(sha), http.StatusOK, nil
+}
@@ -0,0 +1,25 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestIsCommitSHA(t *testing.T) {
+	for commit, expect := range map[string]bool{
+		"e83c5163316f89bfbde7d9ab23ca2e25604af290":  true,
+		"E83C5163316F89BFBDE7D9AB23CA2E25604AF290":  true,
+		"e83c5163316f89bfbde7d9ab23ca2e25604af29":   false,
+		"e83c5163316f89bfbde7d9ab23ca2e25604af2900": false,
+		"g83c5163316f89bfbde7d9ab23ca2e25604af290":  false,
+		"e83c516": false,
+		"master":  false,
+		"":        false,
+	} {
+		if got := isCommitSHA(commit); got != expect {
+			t.Errorf("isCommitSHA(%q) = %t, expected %t", commit, got, expect)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"mime"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// rewriteAttrs are the attributes that hold a URL.
+var rewriteAttrs = map[string]bool{
+	"action":     true,
+	"background": true,
+	"cite":       true,
+	"data":       true,
+	"formaction": true,
+	"href":       true,
+	"icon":       true,
+	"manifest":   true,
+	"poster":     true,
+	"src":        true,
+}
+
+func isHTML(contentType string) bool {
+	mediatype, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediatype == "text/html" || mediatype == "application/xhtml+xml")
+}
+
+// rewriteURL prefixes root-relative URLs with prefix.
+func rewriteURL(prefix, u string) string {
+	trimmed := strings.TrimSpace(u)
+
+	if !strings.HasPrefix(trimmed, "/") || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/\\") {
+		return u
+	}
+
+	return prefix + trimmed
+}
+
+func rewriteSrcset(prefix, srcset string) string {
+	candidates := strings.Split(srcset, ",")
+
+	for i, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+
+		if idx := strings.IndexAny(candidate, " \t\n\f\r"); idx != -1 {
+			candidates[i] = rewriteURL(prefix, candidate[:idx]) + candidate[idx:]
+		} else {
+			candidates[i] = rewriteURL(prefix, candidate)
+		}
+	}
+
+	return strings.Join(candidates, ", ")
+}
+
+// rewriteRefresh rewrites the URL in the content of a
+// <meta http-equiv="refresh"> tag.
+func rewriteRefresh(prefix, content string) string {
+	idx := strings.Index(strings.ToLower(content), "url=")
+	if idx == -1 {
+		return content
+	}
+
+	idx += len("url=")
+
+	u := content[idx:]
+	if len(u) != 0 && (u[0] == '\'' || u[0] == '"') {
+		return content[:idx+1] + rewriteURL(prefix, u[1:])
+	}
+
+	return content[:idx] + rewriteURL(prefix, u)
+}
+
+// rewriteHTML copies the HTML document from r to w with
+// root-relative URLs prefixed with prefix.
+func rewriteHTML(w io.Writer, r io.Reader, prefix string) error {
+	z := html.NewTokenizer(r)
+
+	for {
+		tt := z.Next()
+
+		switch tt {
+		case html.ErrorToken:
+			if err := z.Err(); err != io.EOF {
+				return err
+			}
+
+			return nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			raw := z.Raw()
+
+			tok := z.Token()
+			if !rewriteToken(&tok, prefix) {
+				if _, err := w.Write(raw); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if _, err := io.WriteString(w, tok.String()); err != nil {
+				return err
+			}
+		default:
+			if _, err := w.Write(z.Raw()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func rewriteToken(tok *html.Token, prefix string) (rewrote bool) {
+	isRefresh := false
+
+	if tok.Data == "meta" {
+		for _, attr := range tok.Attr {
+			if attr.Key == "http-equiv" && strings.EqualFold(strings.TrimSpace(attr.Val), "refresh") {
+				isRefresh = true
+			}
+		}
+	}
+
+	for i, attr := range tok.Attr {
+		var val string
+
+		switch {
+		case rewriteAttrs[attr.Key]:
+			val = rewriteURL(prefix, attr.Val)
+		case attr.Key == "srcset":
+			val = rewriteSrcset(prefix, attr.Val)
+		case attr.Key == "content" && isRefresh:
+			val = rewriteRefresh(prefix, attr.Val)
+		default:
+			continue
+		}
+
+		if val != attr.Val {
+			tok.Attr[i].Val = val
+			rewrote = true
+		}
+	}
+
+	return
+}
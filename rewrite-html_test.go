@@ -0,0 +1,39 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRewriteHTML(t *testing.T) {
+	for _, test := range []struct {
+		in, out string
+	}{
+		{`<a href="/about/">`, `<a href="/s/tag/about/">`},
+		{`<a href="about/">`, `<a href="about/">`},
+		{`<a href="//example.com/">`, `<a href="//example.com/">`},
+		{`<a href="https://example.com/">`, `<a href="https://example.com/">`},
+		{`<img src="/a.png" srcset="/a.png 1x, /b.png 2x">`, `<img src="/s/tag/a.png" srcset="/s/tag/a.png 1x, /s/tag/b.png 2x">`},
+		{`<meta http-equiv="refresh" content="0; url=/new/">`, `<meta http-equiv="refresh" content="0; url=/s/tag/new/">`},
+		{`<meta name="description" content="/not/a/url">`, `<meta name="description" content="/not/a/url">`},
+		{`<script>if (a < b) location = "/x";</script>`, `<script>if (a < b) location = "/x";</script>`},
+		{`<!DOCTYPE html><p class=x>text &amp; more</p>`, `<!DOCTYPE html><p class=x>text &amp; more</p>`},
+	} {
+		var buf bytes.Buffer
+
+		if err := rewriteHTML(&buf, strings.NewReader(test.in), "/s/tag"); err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if buf.String() != test.out {
+			t.Errorf("rewriteHTML(%q) = %q, expected %q", test.in, buf.String(), test.out)
+		}
+	}
+}
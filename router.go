@@ -6,36 +6,58 @@
 package main
 
 import (
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/elazarl/go-bindata-assetfs"
 	"github.com/golang/groupcache"
-	"github.com/google/go-github/github"
 	"github.com/julienschmidt/httprouter"
 	"github.com/keep94/weblogs"
-	"github.com/mitchellh/goamz/s3"
 )
 
-func getRouter(httpPool http.Handler, poolOpts *groupcache.HTTPPoolOptions, githubClient *github.Client, highlightStyle string, buildJekyll *groupcache.Group, s3BucketNoGzip *s3.Bucket) http.Handler {
+func getRouter(httpPool http.Handler, poolOpts *groupcache.HTTPPoolOptions, poolSelf string, sources sourceRegistry, highlightStyle string, queue *buildQueue, store storage, hosts *hostConfig, hooks *githubHookConfig) http.Handler {
 	baseRouter := httprouter.New()
 
-	baseRouter.Handler(http.MethodGet, poolOpts.BasePath, httpPool)
-
 	baseRouter.HEAD("/", indexHandler)
 	baseRouter.GET("/", indexHandler)
-	baseRouter.GET("/goto/", getGotoHandler())
-	user := getUserHandler(githubClient)
-	baseRouter.GET("/u/:user/", user)
-	baseRouter.GET("/u/:user/p/:page/", user)
-	repo := getRepoHandler(githubClient)
-	baseRouter.GET("/u/:user/r/:repo/", repo)
-	baseRouter.GET("/u/:user/r/:repo/p/:page/", repo)
-	baseRouter.GET("/u/:user/r/:repo/t/:tree/", repo)
-	baseRouter.GET("/u/:user/r/:repo/t/:tree/p/:page/", repo)
-	baseRouter.GET("/u/:user/r/:repo/c/:commit/", getCommitHandler(githubClient, highlightStyle))
-	buildCommit := getBuildCommitHandler(buildJekyll)
-	baseRouter.GET("/u/:user/r/:repo/c/:commit/b", buildCommit)
-	baseRouter.GET("/u/:user/r/:repo/c/:commit/b/*path", buildCommit)
+	baseRouter.GET("/goto/", getGotoHandler(sources))
+
+	if hooks != nil {
+		baseRouter.POST("/hooks/github", getGithubHookHandler(hooks, sources, queue))
+	}
+
+	user := getUserHandler(sources)
+	repo := getRepoHandler(sources)
+	branches := getRefsHandler(sources, false)
+	tags := getRefsHandler(sources, true)
+	pulls := getPullsHandler(sources)
+	pull := getPullHandler(sources)
+	commit := getCommitHandler(sources, store, highlightStyle)
+	buildCommit := getBuildCommitHandler(sources, queue, store, hosts)
+	buildStatus := getBuildStatusHandler(sources, queue, store, hosts)
+	buildLog := getBuildLogHandler(sources, store)
+
+	// GitHub is served without a provider prefix
+	for _, prefix := range [...]string{"", "/p/:provider"} {
+		baseRouter.GET(prefix+"/u/:user/", user)
+		baseRouter.GET(prefix+"/u/:user/p/:page/", user)
+		baseRouter.GET(prefix+"/u/:user/r/:repo/", repo)
+		baseRouter.GET(prefix+"/u/:user/r/:repo/p/:page/", repo)
+		baseRouter.GET(prefix+"/u/:user/r/:repo/t/:tree/", repo)
+		baseRouter.GET(prefix+"/u/:user/r/:repo/t/:tree/p/:page/", repo)
+		baseRouter.GET(prefix+"/u/:user/r/:repo/branches/", branches)
+		baseRouter.GET(prefix+"/u/:user/r/:repo/tags/", tags)
+		baseRouter.GET(prefix+"/u/:user/r/:repo/pulls/", pulls)
+		baseRouter.GET(prefix+"/u/:user/r/:repo/pulls/p/:page/", pulls)
+		baseRouter.GET(prefix+"/u/:user/r/:repo/pull/:number/", pull)
+		baseRouter.GET(prefix+"/u/:user/r/:repo/c/:commit/", commit)
+		baseRouter.GET(prefix+"/u/:user/r/:repo/c/:commit/b", buildCommit)
+		baseRouter.GET(prefix+"/u/:user/r/:repo/c/:commit/b/*path", buildCommit)
+		baseRouter.GET(prefix+"/u/:user/r/:repo/c/:commit/status", buildStatus)
+		baseRouter.GET(prefix+"/u/:user/r/:repo/c/:commit/log", buildLog)
+	}
 
 	assetsRouter := http.FileServer(&assetfs.AssetFS{
 		Asset:     Asset,
@@ -57,28 +79,51 @@ func getRouter(httpPool http.Handler, poolOpts *groupcache.HTTPPoolOptions, gith
 		Prefix: "assets",
 	})
 
-	hs := new(hostSwitch)
-	hs.NotFound = &repoSwitch{
-		S3Bucket: s3BucketNoGzip,
+	rs := &repoSwitch{
+		Storage: store,
+		Hosts:   hosts,
+		Sites:   new(siteCache),
 	}
 
-	hs.Add("jekyllhistory.com", hostRedirector{
-		Host: "jekyllhistory.org",
-		Code: http.StatusFound,
-	})
-	hs.Add("jekyllhistory.org", errorHandler{
+	var service http.Handler = errorHandler{
 		Handler: baseRouter,
-	})
+	}
 
-	hs.Add("www.jekyllhistory.com", hostRedirector{
-		Host: "jekyllhistory.com",
-	})
-	hs.Add("www.jekyllhistory.org", hostRedirector{
-		Host: "jekyllhistory.org",
-	})
+	hs := new(hostSwitch)
+
+	if hosts.SitePaths {
+		// built sites are served outside of errorHandler
+		// so that their own 404.html pages are used
+		baseService := service
+		service = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, sitePathPrefix) {
+				rs.ServePath(w, r)
+			} else {
+				baseService.ServeHTTP(w, r)
+			}
+		})
+	} else {
+		hs.NotFound = rs
+	}
+
+	hosts.addHandlers(hs, service)
+
+	// peers are reached by the address in their base URL,
+	// which may not be the primary host, but the pool must
+	// not be served from built sites
+	poolHosts := []string{hosts.Primary}
+	if u, err := url.Parse(poolSelf); err == nil && len(u.Host) != 0 {
+		poolHosts = append(poolHosts, u.Hostname())
+	}
 
 	var router http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Server", fullVersionStr)
+
+		if strings.HasPrefix(r.URL.Path, poolOpts.BasePath) && isPoolHost(poolHosts, r.Host) {
+			httpPool.ServeHTTP(w, r)
+			return
+		}
+
 		hs.ServeHTTP(w, r)
 	})
 
@@ -90,3 +135,19 @@ func getRouter(httpPool http.Handler, poolOpts *groupcache.HTTPPoolOptions, gith
 
 	return router
 }
+
+// isPoolHost reports whether the groupcache pool is served
+// from host, which may include a port.
+func isPoolHost(poolHosts []string, host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	for _, poolHost := range poolHosts {
+		if strings.EqualFold(host, poolHost) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -7,6 +7,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 
@@ -15,7 +16,11 @@ import (
 	"github.com/mitchellh/goamz/s3"
 )
 
-func getS3Buckets() (s3Bucket *s3.Bucket, s3BucketNoGzip *s3.Bucket, err error) {
+type s3Storage struct {
+	Bucket *s3.Bucket
+}
+
+func getS3Storage() (storage, error) {
 	endpoint := os.Getenv("S3_ENDPOINT")
 	if len(endpoint) == 0 {
 		endpoint = "us-east-1"
@@ -23,41 +28,124 @@ func getS3Buckets() (s3Bucket *s3.Bucket, s3BucketNoGzip *s3.Bucket, err error)
 
 	bucket := os.Getenv("S3_BUCKET")
 	if len(bucket) == 0 {
-		return nil, nil, fmt.Errorf("S3_BUCKET must be set")
+		return nil, fmt.Errorf("S3_BUCKET must be set")
 	}
 
 	region, ok := aws.Regions[endpoint]
 	if !ok {
-		return nil, nil, fmt.Errorf("invalid S3_ENDPOINT value of %s", endpoint)
+		return nil, fmt.Errorf("invalid S3_ENDPOINT value of %s", endpoint)
 	}
 
 	auth, err := aws.EnvAuth()
 	if err != nil {
-		return
+		return nil, err
 	}
 
-	s3Bucket = s3.New(auth, region).Bucket(bucket)
-	s3BucketNoGzip = s3.New(auth, region).Bucket(bucket)
+	s3Bucket := s3.New(auth, region).Bucket(bucket)
 
 	clientTr := httpcache.NewMemoryCacheTransport()
 	clientTr.MarkCachedResponses = true
 
+	// objects are stored gzipped and must be returned as is
+	noGzipTransport := *http.DefaultTransport.(*http.Transport)
+	noGzipTransport.DisableCompression = true
+	clientTr.Transport = &noGzipTransport
+
 	client := clientTr.Client()
 	s3Bucket.S3.HTTPClient = func() *http.Client {
 		return client
 	}
 
-	noGzipClientTr := httpcache.NewMemoryCacheTransport()
-	noGzipClientTr.MarkCachedResponses = true
+	return s3Storage{s3Bucket}, nil
+}
 
-	noGzipTransport := *http.DefaultTransport.(*http.Transport)
-	noGzipTransport.DisableCompression = true
-	noGzipClientTr.Transport = &noGzipTransport
+func (s s3Storage) Put(name string, r io.Reader, size int64, header http.Header) error {
+	headers := make(map[string][]string, len(header)+1)
+	for k, v := range header {
+		headers[k] = v
+	}
+
+	headers["x-amz-storage-class"] = []string{"REDUCED_REDUNDANCY"}
+
+	return s.Bucket.PutReaderHeader(name, r, size, headers, "")
+}
+
+func (s s3Storage) Get(name string) (*storageObject, error) {
+	resp, err := s.Bucket.GetResponse(name)
+	if err != nil {
+		return nil, s.convertError("get", name, err)
+	}
+
+	return &storageObject{
+		Header: resp.Header,
+		Body:   resp.Body,
+	}, nil
+}
+
+func (s s3Storage) GetRange(name string, start, length int64) (*storageObject, error) {
+	resp, err := s.Bucket.GetResponseWithHeaders(name, map[string][]string{
+		"Range": {fmt.Sprintf("bytes=%d-%d", start, start+length-1)},
+	})
+	if err != nil {
+		return nil, s.convertError("get", name, err)
+	}
+
+	return &storageObject{
+		Header: resp.Header,
+		Body:   resp.Body,
+	}, nil
+}
+
+func (s s3Storage) Head(name string) (*storageObject, error) {
+	resp, err := s.Bucket.Head(name)
+	if err != nil {
+		return nil, s.convertError("head", name, err)
+	}
+
+	if resp.Body != nil {
+		resp.Body.Close()
+	}
+
+	return &storageObject{
+		Header: resp.Header,
+	}, nil
+}
+
+func (s s3Storage) List(prefix string, max int) ([]string, error) {
+	var names []string
+	var marker string
+
+	for {
+		listMax := 1000
+		if max > 0 && max-len(names) < listMax {
+			listMax = max - len(names)
+		}
+
+		list, err := s.Bucket.List(prefix, "", marker, listMax)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range list.Contents {
+			names = append(names, key.Key)
+		}
+
+		if !list.IsTruncated || len(list.Contents) == 0 || max > 0 && len(names) >= max {
+			return names, nil
+		}
+
+		marker = list.Contents[len(list.Contents)-1].Key
+	}
+}
+
+func (s s3Storage) Delete(name string) error {
+	return s.Bucket.Del(name)
+}
 
-	noGzipClient := noGzipClientTr.Client()
-	s3BucketNoGzip.S3.HTTPClient = func() *http.Client {
-		return noGzipClient
+func (s3Storage) convertError(op, name string, err error) error {
+	if s3err, ok := err.(*s3.Error); ok && s3err.StatusCode == http.StatusNotFound {
+		return storageNotExist(op, name)
 	}
 
-	return
+	return err
 }
@@ -0,0 +1,307 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRanges is the most ranges sent in one response, once
+// they are coalesced. The whole object is sent for
+// requests with more.
+const maxRanges = 16
+
+var errNoOverlap = errors.New("invalid range: failed to overlap")
+
+// httpRange is a byte range of an object.
+type httpRange struct {
+	start, length int64
+}
+
+func (r httpRange) contentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.start, r.start+r.length-1, size)
+}
+
+// parseRange parses a Range header of an object of size
+// bytes. It returns errNoOverlap if no range is satisfiable.
+func parseRange(s string, size int64) ([]httpRange, error) {
+	const b = "bytes="
+	if !strings.HasPrefix(s, b) {
+		return nil, errors.New("invalid range")
+	}
+
+	var ranges []httpRange
+	noOverlap := false
+
+	for _, ra := range strings.Split(s[len(b):], ",") {
+		ra = strings.TrimSpace(ra)
+		if len(ra) == 0 {
+			continue
+		}
+
+		i := strings.Index(ra, "-")
+		if i < 0 {
+			return nil, errors.New("invalid range")
+		}
+
+		start, end := strings.TrimSpace(ra[:i]), strings.TrimSpace(ra[i+1:])
+
+		var r httpRange
+
+		if len(start) == 0 {
+			// a suffix of the object
+			i, err := strconv.ParseInt(end, 10, 64)
+			if err != nil || i < 0 {
+				return nil, errors.New("invalid range")
+			}
+
+			if i == 0 {
+				noOverlap = true
+				continue
+			}
+
+			if i > size {
+				i = size
+			}
+
+			r.start, r.length = size-i, i
+		} else {
+			i, err := strconv.ParseInt(start, 10, 64)
+			if err != nil || i < 0 {
+				return nil, errors.New("invalid range")
+			}
+
+			if i >= size {
+				noOverlap = true
+				continue
+			}
+
+			r.start = i
+
+			if len(end) == 0 {
+				r.length = size - r.start
+			} else {
+				i, err := strconv.ParseInt(end, 10, 64)
+				if err != nil || r.start > i {
+					return nil, errors.New("invalid range")
+				}
+
+				if i >= size {
+					i = size - 1
+				}
+
+				r.length = i - r.start + 1
+			}
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	if noOverlap && len(ranges) == 0 {
+		return nil, errNoOverlap
+	}
+
+	return ranges, nil
+}
+
+// coalesceRanges sorts ranges and merges those that
+// overlap or are adjacent.
+func coalesceRanges(ranges []httpRange) []httpRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start < ranges[j].start
+	})
+
+	merged := ranges[:1]
+
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+
+		if end := last.start + last.length; r.start > end {
+			merged = append(merged, r)
+		} else if rend := r.start + r.length; rend > end {
+			last.length = rend - last.start
+		}
+	}
+
+	return merged
+}
+
+// checkIfRange reports whether the Range header should be
+// used. It is ignored if If-Range names another version.
+func checkIfRange(r *http.Request, etag string, modtime time.Time) bool {
+	ir := r.Header.Get("If-Range")
+	if len(ir) == 0 {
+		return true
+	}
+
+	// weak entity tags never match
+	if strings.HasPrefix(ir, `"`) {
+		return ir == etag
+	}
+
+	t, err := http.ParseTime(ir)
+	return err == nil && !modtime.IsZero() && t.Equal(modtime)
+}
+
+// openRange returns rng of the named object, from storage
+// if it supports ranges.
+func (rs repoSwitch) openRange(name string, rng httpRange) (io.ReadCloser, error) {
+	if store, ok := rs.Storage.(rangeStorage); ok {
+		obj, err := store.GetRange(name, rng.start, rng.length)
+		if err != nil {
+			return nil, err
+		}
+
+		return obj.Body, nil
+	}
+
+	obj, err := rs.Storage.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.CopyN(ioutil.Discard, obj.Body, rng.start); err != nil {
+		obj.Body.Close()
+		return nil, err
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(obj.Body, rng.length), obj.Body}, nil
+}
+
+// serveRange serves the ranges of obj requested by r. It
+// reports whether it handled the request; if not, the
+// whole object should be sent.
+func (rs repoSwitch) serveRange(w http.ResponseWriter, r *http.Request, obj *storageObject, name string) (bool, error) {
+	rangeHeader := r.Header.Get("Range")
+	if len(rangeHeader) == 0 {
+		return false, nil
+	}
+
+	size, err := strconv.ParseInt(obj.Header.Get("Content-Length"), 10, 64)
+	if err != nil || size < 0 {
+		return false, nil
+	}
+
+	h := w.Header()
+
+	modtime, _ := time.Parse(http.TimeFormat, obj.Header.Get("Last-Modified"))
+	if !checkIfRange(r, h.Get("Etag"), modtime) {
+		return false, nil
+	}
+
+	ranges, err := parseRange(rangeHeader, size)
+	if err == errNoOverlap {
+		h.Del("Content-Length")
+		h.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+		http.Error(w, http.StatusText(http.StatusRequestedRangeNotSatisfiable), http.StatusRequestedRangeNotSatisfiable)
+		return true, nil
+	} else if err != nil || len(ranges) == 0 {
+		// invalid ranges are ignored
+		return false, nil
+	}
+
+	// overlapping ranges could otherwise be used to
+	// amplify requests
+	if ranges = coalesceRanges(ranges); len(ranges) > maxRanges {
+		return false, nil
+	}
+
+	if len(ranges) == 1 {
+		body, err := rs.openRange(name, ranges[0])
+		if err != nil {
+			return false, err
+		}
+
+		defer body.Close()
+
+		h.Set("Content-Range", ranges[0].contentRange(size))
+		h.Set("Content-Length", strconv.FormatInt(ranges[0].length, 10))
+		w.WriteHeader(http.StatusPartialContent)
+
+		copyBuffer(w, body)
+		return true, nil
+	}
+
+	// without support for ranges, the object is read once
+	// as the ranges are in order
+	var whole io.ReadCloser
+	var offset int64
+
+	if _, ok := rs.Storage.(rangeStorage); !ok {
+		wholeObj, err := rs.Storage.Get(name)
+		if err != nil {
+			return false, err
+		}
+
+		whole = wholeObj.Body
+		defer whole.Close()
+	}
+
+	contentType := obj.Header.Get("Content-Type")
+
+	mw := multipart.NewWriter(w)
+
+	h.Del("Content-Length")
+	h.Set("Content-Type", "multipart/byteranges; boundary="+mw.Boundary())
+	w.WriteHeader(http.StatusPartialContent)
+
+	for _, rng := range ranges {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Range": {rng.contentRange(size)},
+			"Content-Type":  {contentType},
+		})
+		if err != nil {
+			return true, nil
+		}
+
+		if whole != nil {
+			if _, err := io.CopyN(ioutil.Discard, whole, rng.start-offset); err != nil {
+				// the status has already been sent
+				log.Printf("%[1]T: %[1]v", err)
+				return true, nil
+			}
+
+			offset = rng.start + rng.length
+
+			if _, err := io.CopyN(part, whole, rng.length); err != nil {
+				return true, nil
+			}
+
+			continue
+		}
+
+		body, err := rs.openRange(name, rng)
+		if err != nil {
+			// the status has already been sent
+			log.Printf("%[1]T: %[1]v", err)
+			return true, nil
+		}
+
+		_, err = copyBuffer(part, body)
+		body.Close()
+
+		if err != nil {
+			return true, nil
+		}
+	}
+
+	mw.Close()
+	return true, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	for _, test := range []struct {
+		header string
+		expect []httpRange
+		err    bool
+	}{
+		{"bytes=0-4", []httpRange{{0, 5}}, false},
+		{"bytes=5-", []httpRange{{5, 5}}, false},
+		{"bytes=-3", []httpRange{{7, 3}}, false},
+		{"bytes=-20", []httpRange{{0, 10}}, false},
+		{"bytes=8-20", []httpRange{{8, 2}}, false},
+		{"bytes=0-0, 2-3", []httpRange{{0, 1}, {2, 2}}, false},
+		{"bytes=0-0, 20-30", []httpRange{{0, 1}}, false},
+		{"bytes=20-30", nil, true},
+		{"bytes=4-2", nil, true},
+		{"bytes=a-b", nil, true},
+		{"items=0-1", nil, true},
+	} {
+		ranges, err := parseRange(test.header, 10)
+		if (err != nil) != test.err {
+			t.Errorf("parseRange(%q) returned error %v", test.header, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(ranges, test.expect) {
+			t.Errorf("parseRange(%q) returned %v, expected %v", test.header, ranges, test.expect)
+		}
+	}
+
+	if _, err := parseRange("bytes=10-", 10); err != errNoOverlap {
+		t.Errorf("parseRange of an unsatisfiable range returned %v, expected %v", err, errNoOverlap)
+	}
+}
+
+// noRangeStorage hides the GetRange method of a storage.
+type noRangeStorage struct {
+	storage
+}
+
+func TestCoalesceRanges(t *testing.T) {
+	for _, test := range []struct {
+		ranges, expect []httpRange
+	}{
+		{[]httpRange{{0, 1}}, []httpRange{{0, 1}}},
+		{[]httpRange{{5, 2}, {0, 2}}, []httpRange{{0, 2}, {5, 2}}},
+		{[]httpRange{{0, 2}, {2, 2}}, []httpRange{{0, 4}}},
+		{[]httpRange{{0, 5}, {1, 2}}, []httpRange{{0, 5}}},
+		{[]httpRange{{4, 4}, {0, 5}, {10, 1}}, []httpRange{{0, 8}, {10, 1}}},
+	} {
+		in := fmt.Sprint(test.ranges)
+
+		if ranges := coalesceRanges(test.ranges); !reflect.DeepEqual(ranges, test.expect) {
+			t.Errorf("coalesceRanges(%s) returned %v, expected %v", in, ranges, test.expect)
+		}
+	}
+}
+
+// manyRanges returns a Range header with n ranges that
+// cannot be coalesced.
+func manyRanges(n int) string {
+	ranges := make([]string, n)
+	for i := range ranges {
+		ranges[i] = fmt.Sprintf("%d-%d", 2*i, 2*i)
+	}
+
+	return "bytes=" + strings.Join(ranges, ",")
+}
+
+func TestRepoSwitchRange(t *testing.T) {
+	rs := newTestRepoSwitch(t)
+
+	body := "0123456789"
+	if err := rs.Storage.Put(tagPrefix(testTag)+"/video.mp4", bytes.NewReader([]byte(body)), int64(len(body)), http.Header{
+		"Content-Type": {"video/mp4"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	large := strings.Repeat("0123456789", 10)
+	if err := rs.Storage.Put(tagPrefix(testTag)+"/large.bin", strings.NewReader(large), int64(len(large)), http.Header{
+		"Content-Type": {"application/octet-stream"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, store := range []storage{rs.Storage, noRangeStorage{rs.Storage}} {
+		rs := &repoSwitch{
+			Storage: store,
+			Hosts:   rs.Hosts,
+		}
+
+		for _, test := range []struct {
+			path, rng, ifRange, accept string
+
+			code         int
+			body         string
+			contentRange string
+			acceptRanges string
+		}{
+			{"/video.mp4", "", "", "", http.StatusOK, body, "", "bytes"},
+			{"/video.mp4", "bytes=2-5", "", "", http.StatusPartialContent, "2345", "bytes 2-5/10", "bytes"},
+			{"/video.mp4", "bytes=-3", "", "", http.StatusPartialContent, "789", "bytes 7-9/10", "bytes"},
+			{"/video.mp4", "bytes=2-5", `"` + testTag + `"`, "", http.StatusPartialContent, "2345", "bytes 2-5/10", "bytes"},
+			{"/video.mp4", "bytes=2-5", `"other"`, "", http.StatusOK, body, "", "bytes"},
+			{"/video.mp4", "bytes=20-", "", "", http.StatusRequestedRangeNotSatisfiable, "", "bytes */10", "bytes"},
+			{"/video.mp4", "bytes=0-0,0-9", "", "", http.StatusPartialContent, body, "bytes 0-9/10", "bytes"},
+			{"/video.mp4", "bytes=4-5,2-3", "", "", http.StatusPartialContent, "2345", "bytes 2-5/10", "bytes"},
+			{"/large.bin", manyRanges(maxRanges), "", "", http.StatusPartialContent, "", "", "bytes"},
+			{"/large.bin", manyRanges(maxRanges + 1), "", "", http.StatusOK, large, "", "bytes"},
+			{"/gzip.txt", "bytes=0-1", "", "", http.StatusOK, "compressed", "", "none"},
+			{"/gzip.txt", "bytes=0-1", "", "gzip", http.StatusPartialContent, "\x1f\x8b", "", "bytes"},
+			{"/missing", "bytes=0-1", "", "", http.StatusNotFound, "not found", "", ""},
+		} {
+			req := httptest.NewRequest(http.MethodGet, "http://"+testTag+".jekyllhistory.org"+test.path, nil)
+
+			for k, v := range map[string]string{
+				"Range":           test.rng,
+				"If-Range":        test.ifRange,
+				"Accept-Encoding": test.accept,
+			} {
+				if len(v) != 0 {
+					req.Header.Set(k, v)
+				}
+			}
+
+			rw := httptest.NewRecorder()
+			rs.ServeHTTP(rw, req)
+
+			if rw.Code != test.code {
+				t.Errorf("GET %s with Range %q returned wrong status code, expected %d, got %d", test.path, test.rng, test.code, rw.Code)
+			}
+
+			if len(test.body) != 0 && rw.Body.String() != test.body {
+				t.Errorf("GET %s with Range %q returned wrong body, expected %q, got %q", test.path, test.rng, test.body, rw.Body.String())
+			}
+
+			if cr := rw.HeaderMap.Get("Content-Range"); len(test.contentRange) != 0 && cr != test.contentRange {
+				t.Errorf("GET %s with Range %q returned wrong Content-Range, expected %q, got %q", test.path, test.rng, test.contentRange, cr)
+			}
+
+			if ar := rw.HeaderMap.Get("Accept-Ranges"); ar != test.acceptRanges {
+				t.Errorf("GET %s with Range %q returned wrong Accept-Ranges, expected %q, got %q", test.path, test.rng, test.acceptRanges, ar)
+			}
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "http://"+testTag+".jekyllhistory.org/video.mp4", nil)
+		req.Header.Set("Range", "bytes=8-,0-1,1-2")
+
+		rw := httptest.NewRecorder()
+		rs.ServeHTTP(rw, req)
+
+		if rw.Code != http.StatusPartialContent {
+			t.Fatalf("multiple ranges returned wrong status code, expected %d, got %d", http.StatusPartialContent, rw.Code)
+		}
+
+		mediaType, params, err := mime.ParseMediaType(rw.HeaderMap.Get("Content-Type"))
+		if err != nil || mediaType != "multipart/byteranges" {
+			t.Fatalf("multiple ranges returned wrong Content-Type %q", rw.HeaderMap.Get("Content-Type"))
+		}
+
+		mr := multipart.NewReader(rw.Body, params["boundary"])
+
+		for _, expect := range []struct {
+			contentRange, body string
+		}{
+			{"bytes 0-2/10", "012"},
+			{"bytes 8-9/10", "89"},
+		} {
+			part, err := mr.NextPart()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := ioutil.ReadAll(part)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if cr := part.Header.Get("Content-Range"); cr != expect.contentRange || string(data) != expect.body || part.Header.Get("Content-Type") != "video/mp4" {
+				t.Errorf("part returned %q with Content-Range %q, expected %q with %q", data, cr, expect.body, expect.contentRange)
+			}
+		}
+	}
+}
@@ -8,28 +8,30 @@ package main
 import (
 	"compress/gzip"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/http"
+	"net/url"
+	"os"
 	"path"
-	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
-
-	"github.com/mitchellh/goamz/s3"
 )
 
 var (
 	builtRepoCacheControl = fmt.Sprintf("public, max-age=%d", (10*365*24*time.Hour)/time.Second)
 
 	timeZero time.Time
-
-	hostRegex = regexp.MustCompile(`(?i)^([0-9a-fA-F]{32}).jekyllhistory.org$`)
 )
 
 type repoSwitch struct {
-	S3Bucket *s3.Bucket
+	Storage storage
+	Hosts   *hostConfig
+
+	// Sites caches the rules and paths of sites, they are
+	// loaded for every request if nil.
+	Sites *siteCache
 }
 
 func (rs repoSwitch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -38,184 +40,384 @@ func (rs repoSwitch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		host = r.Host
 	}
 
-	m := hostRegex.FindStringSubmatch(host)
-	if m == nil {
+	tag, ok := rs.Hosts.siteTag(host)
+	if !ok {
 		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
 		return
 	}
 
-	tag := strings.ToLower(m[1])
+	rs.serveTag(w, r, tag, r.URL.Path, "")
+}
+
+// ServePath serves built sites from /s/<tag>/ on the
+// service host.
+func (rs repoSwitch) ServePath(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, sitePathPrefix)
+
+	tag, name := rest, ""
+	if idx := strings.IndexByte(rest, '/'); idx != -1 {
+		tag, name = rest[:idx], rest[idx:]
+	}
+
+	if !isTag(tag) {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	prefix := sitePathPrefix + tag
+
+	if len(name) == 0 {
+		localRedirect(w, r, prefix+"/")
+		return
+	}
+
+	rs.serveTag(w, r, strings.ToLower(tag), name, prefix)
+}
 
-	if strings.HasSuffix(r.URL.Path, "/index.html") {
+// serveTag serves name from the built site for tag. If
+// prefix is not empty, redirects are made relative to it
+// and, unless the site was built with it as its baseurl,
+// root-relative URLs in HTML pages are rewritten to begin
+// with it.
+func (rs repoSwitch) serveTag(w http.ResponseWriter, r *http.Request, tag, name, prefix string) {
+	if strings.HasSuffix(name, "/index.html") {
 		localRedirect(w, r, "./")
 		return
 	}
 
+	etag := `"` + tag + `"`
+
 	h := w.Header()
 	h.Set("Cache-Control", builtRepoCacheControl)
-	h.Set("Etag", `"`+tag+`"`)
+	h.Set("Etag", etag)
 
-	if checkETag(w, r) {
+	if strings.Contains(name, "\x00") {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	if filepath.Separator != '/' && strings.IndexRune(r.URL.Path, filepath.Separator) >= 0 || strings.Contains(r.URL.Path, "\x00") {
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-		return
+	basePath := tagPrefix(tag)
+
+	site, err := rs.Sites.load(rs.Storage, basePath)
+	if err != nil {
+		// the site is still served, without its rules
+		log.Printf("%[1]T: %[1]v", err)
+
+		site = new(siteInfo)
 	}
 
-	name := r.URL.Path
+	rules, paths := site.Rules, site.Paths
 
-	if strings.HasSuffix(name, "/") {
-		name += "/index.html"
+	rewrite := prefix
+	if strings.EqualFold(rules.baseURL(), prefix) {
+		rewrite = ""
+	}
+
+	// sites served from paths share the origin of the
+	// service
+	for k, v := range rules.header(name, len(prefix) != 0) {
+		h[k] = []string{strings.Join(v, ", ")}
 	}
 
-	basePath := filepath.Join(tag[0:1], tag[1:2], tag[2:])
-	fullPath := filepath.Join(basePath, filepath.FromSlash(path.Clean("/"+name)))
+	// the variants of a file have their own tags, which
+	// are checked once the variant is known, see serveObject
+	if checkETag(w, r) {
+		return
+	}
 
 	switch r.Method {
-	case http.MethodGet:
-		resp, err := rs.S3Bucket.GetResponse(fullPath)
-
-		if err == nil {
-			for _, k := range [...]string{"Content-Length", "Content-Type", "X-From-Cache"} {
-				for _, v := range resp.Header[k] {
-					h.Add(k, v)
-				}
-			}
+	case http.MethodGet, http.MethodHead:
+		// the first rule that matches wins, but unless it is
+		// forced it is shadowed by any file that exists
+		rule, to := rules.redirect(name)
+		if rule != nil && rule.Force {
+			rs.serveRule(w, r, rule, basePath, to, prefix, rewrite, paths)
+			return
+		}
 
-			if modtime, err := time.Parse(http.TimeFormat, resp.Header.Get("Last-Modified")); err == nil && checkLastModified(w, r, modtime, 0) {
-				resp.Body.Close()
+		if paths == nil {
+			if rs.serveUnindexed(w, r, basePath, name, prefix, rewrite) {
 				return
 			}
-
-			if err = rs.serveS3Response(w, r, resp, resp.StatusCode); err != nil {
-				log.Printf("%[1]T: %[1]v", err)
-
-				h.Del("Etag")
-				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-			}
-
+		} else if file, redirect := paths.resolve(name); len(redirect) != 0 {
+			localRedirect(w, r, (&url.URL{Path: prefix + redirect}).EscapedPath())
+			return
+		} else if len(file) != 0 && rs.serveFile(w, r, basePath, file, http.StatusOK, rewrite, paths) {
 			return
 		}
 
-		s3err, ok := err.(*s3.Error)
-		if !ok {
-			log.Printf("%[1]T: %[1]v", err)
-
-			h.Del("Etag")
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		if rule != nil {
+			rs.serveRule(w, r, rule, basePath, to, prefix, rewrite, paths)
 			return
 		}
 
-		if s3err.StatusCode != 404 {
-			log.Printf("%[1]T: %[1]v", err)
-
-			h.Del("Etag")
-			http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		if r.Method == http.MethodGet && (paths == nil || paths.has("/404.html")) &&
+			rs.serveFile(w, r, basePath, "/404.html", http.StatusNotFound, rewrite, paths) {
 			return
 		}
 
-		resp, err = rs.S3Bucket.GetResponse(filepath.Join(basePath, "/404.html"))
-		if err != nil {
-			if s3err, ok := err.(*s3.Error); ok && s3err.StatusCode != 404 {
-				log.Printf("%[1]T: %[1]v", err)
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	case http.MethodOptions:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodHead+", "+http.MethodOptions)
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
 
-				h.Del("Etag")
-			}
+// serveUnindexed serves name from a site built without an
+// index of its paths. It tries the same paths as resolve
+// does, except for those that differ in case. It reports
+// whether it wrote a response.
+func (rs repoSwitch) serveUnindexed(w http.ResponseWriter, r *http.Request, basePath, name, prefix, rewrite string) bool {
+	if rs.serveFile(w, r, basePath, name, http.StatusOK, rewrite, nil) {
+		return true
+	}
 
-			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
-			return
+	if strings.HasSuffix(name, "/") {
+		return false
+	}
+
+	if rs.serveFile(w, r, basePath, name+".html", http.StatusOK, rewrite, nil) {
+		return true
+	}
+
+	if _, err := rs.Storage.Head(path.Join(basePath, path.Clean("/"+name), "index.html")); err == nil {
+		localRedirect(w, r, (&url.URL{Path: prefix + path.Clean("/"+name) + "/"}).EscapedPath())
+		return true
+	} else if !os.IsNotExist(err) {
+		log.Printf("%[1]T: %[1]v", err)
+	}
+
+	return false
+}
+
+// serveRule serves the target of rule, a redirect or a
+// file of the site, which is to once expanded.
+func (rs repoSwitch) serveRule(w http.ResponseWriter, r *http.Request, rule *redirectRule, basePath, to, prefix, rewrite string, paths *sitePaths) {
+	if isRedirectStatus(rule.Status) {
+		if strings.HasPrefix(to, "/") {
+			to = prefix + to
 		}
 
-		for _, k := range [...]string{"Content-Length", "Content-Type", "X-From-Cache"} {
-			for _, v := range resp.Header[k] {
-				h.Add(k, v)
-			}
+		if q := r.URL.RawQuery; len(q) != 0 && !strings.Contains(to, "?") {
+			to += "?" + q
 		}
 
-		if err = rs.serveS3Response(w, r, resp, http.StatusNotFound); err != nil {
-			log.Printf("%[1]T: %[1]v", err)
+		w.Header().Set("Location", to)
+		w.WriteHeader(rule.Status)
+		return
+	}
+
+	if !rs.serveFile(w, r, basePath, to, rule.Status, rewrite, paths) {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}
+}
+
+// serveFile serves name from the built site stored at
+// basePath with code, rewriting HTML pages to be served
+// from the rewrite prefix if it is not empty. The variant
+// to serve is chosen from paths, or if it is nil, from the
+// stored file. It reports whether it wrote a response; if
+// not, the file does not exist.
+func (rs repoSwitch) serveFile(w http.ResponseWriter, r *http.Request, basePath, name string, code int, rewrite string, paths *sitePaths) bool {
+	if strings.HasSuffix(name, "/") {
+		name += "/index.html"
+	}
+
+	fullPath := path.Join(basePath, path.Clean("/"+name))
+	objPath := fullPath
 
-			h.Del("Etag")
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	// rewritten pages must be decoded, which only works
+	// for gzip
+	if paths != nil && len(rewrite) == 0 {
+		if best := paths.variant(r, path.Clean("/"+name)); len(best) != 0 {
+			objPath = variantPath(basePath, best, path.Clean("/"+name))
 		}
-	case http.MethodHead:
-		resp, err := rs.S3Bucket.Head(fullPath)
+	}
 
-		if err == nil {
-			for _, k := range [...]string{"Content-Encoding", "Content-Length", "Content-Type", "X-From-Cache"} {
-				for _, v := range resp.Header[k] {
-					h.Add(k, v)
-				}
-			}
+	// the body is fetched once the ranges, or the variant
+	// of files that are not indexed, are known
+	head := r.Method == http.MethodHead || len(r.Header.Get("Range")) != 0 ||
+		paths == nil && (acceptsEncoding(r, "br") > 0 || acceptsEncoding(r, "zstd") > 0)
 
-			if modtime, err := time.Parse(http.TimeFormat, resp.Header.Get("Last-Modified")); err == nil && checkLastModified(w, r, modtime, 0) {
-				return
-			}
+	obj, err := rs.getObject(objPath, head)
+	if err != nil && objPath != fullPath {
+		// the variant may not have been stored yet
+		log.Printf("%[1]T: %[1]v", err)
 
-			if err = rs.serveS3Response(w, r, resp, resp.StatusCode); err != nil {
-				log.Printf("%[1]T: %[1]v", err)
+		objPath = fullPath
+		obj, err = rs.getObject(objPath, head)
+	}
 
-				h.Del("Etag")
-				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-			}
+	h := w.Header()
 
-			return
+	if err != nil {
+		code := storageErrorCode(err)
+		if code == http.StatusNotFound {
+			return false
 		}
 
-		if s3err, ok := err.(*s3.Error); ok {
-			if s3err.StatusCode == 404 {
-				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
-			} else {
-				log.Printf("%[1]T: %[1]v", err)
-				http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
-			}
-		} else {
-			log.Printf("%[1]T: %[1]v", err)
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		log.Printf("%[1]T: %[1]v", err)
+
+		h.Del("Etag")
+		http.Error(w, http.StatusText(code), code)
+		return true
+	}
+
+	if modtime, err := time.Parse(http.TimeFormat, obj.Header.Get("Last-Modified")); err == nil && code == http.StatusOK && checkLastModified(w, r, modtime, 0) {
+		if obj.Body != nil {
+			obj.Body.Close()
 		}
-	case http.MethodOptions:
-		w.Header().Set("Allow", http.MethodGet+", "+http.MethodHead+", "+http.MethodOptions)
-		w.WriteHeader(http.StatusOK)
-	default:
-		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return true
+	}
+
+	if paths == nil {
+		obj, objPath = rs.variant(r, obj, basePath, name, fullPath, rewrite)
+	}
+
+	for _, k := range [...]string{"Content-Length", "Content-Type", "X-From-Cache"} {
+		for _, v := range obj.Header[k] {
+			h.Add(k, v)
+		}
+	}
+
+	if err = rs.serveObject(w, r, obj, code, objPath, rewrite); err != nil {
+		log.Printf("%[1]T: %[1]v", err)
+
+		h.Del("Etag")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 	}
+
+	return true
 }
 
-func (repoSwitch) serveS3Response(w http.ResponseWriter, r *http.Request, resp *http.Response, code int) error {
-	if encoding := strings.TrimSpace(resp.Header.Get("Content-Encoding")); strings.ToLower(encoding) == "gzip" {
-		h := w.Header()
-		h.Set("Vary", "Accept-Encoding")
+// getObject gets the named object, or only its headers if
+// head is set.
+func (rs repoSwitch) getObject(name string, head bool) (*storageObject, error) {
+	if head {
+		return rs.Storage.Head(name)
+	}
+
+	return rs.Storage.Get(name)
+}
 
-		canGzip := false
+// variant returns the stored variant of obj, which was
+// read from fullPath, that is best for r, along with its
+// name. obj is returned if it is the best. It is used for
+// sites built without an index of their files' encodings.
+func (rs repoSwitch) variant(r *http.Request, obj *storageObject, basePath, name, fullPath, prefix string) (*storageObject, string) {
+	variants := splitList(obj.Header.Get(variantsHeader))
+	if len(variants) == 0 {
+		return obj, fullPath
+	}
 
-		if accept := r.Header.Get("Accept-Encoding"); len(accept) != 0 {
-			for _, v := range strings.Split(accept, ",") {
-				if idx := strings.Index(v, ";"); idx != -1 {
-					v = v[:idx]
-				}
+	// rewritten pages must be decoded, which only works
+	// for gzip
+	if len(prefix) != 0 && isHTML(obj.Header.Get("Content-Type")) {
+		return obj, fullPath
+	}
 
-				if strings.ToLower(strings.TrimSpace(v)) == "gzip" {
-					canGzip = true
-					break
-				}
-			}
+	encoding := strings.ToLower(strings.TrimSpace(obj.Header.Get("Content-Encoding")))
+
+	best := chooseVariant(r, encoding, variants)
+	if len(best) == 0 {
+		return obj, fullPath
+	}
+
+	variantName := variantPath(basePath, best, path.Clean("/"+name))
+
+	var vobj *storageObject
+	var err error
+
+	if r.Method == http.MethodGet && len(r.Header.Get("Range")) == 0 {
+		vobj, err = rs.Storage.Get(variantName)
+	} else {
+		vobj, err = rs.Storage.Head(variantName)
+	}
+
+	if err != nil {
+		log.Printf("%[1]T: %[1]v", err)
+		return obj, fullPath
+	}
+
+	if obj.Body != nil {
+		obj.Body.Close()
+	}
+
+	return vobj, variantName
+}
+
+// loadBody fetches the body of obj if it was returned from
+// Head and the request needs it.
+func (rs repoSwitch) loadBody(r *http.Request, obj *storageObject, name string) error {
+	if obj.Body != nil || r.Method == http.MethodHead {
+		return nil
+	}
+
+	full, err := rs.Storage.Get(name)
+	if err != nil {
+		return err
+	}
+
+	obj.Body = full.Body
+	return nil
+}
+
+// serveObject serves obj, which was read from name. Ranges
+// are only served from objects that are sent unchanged.
+func (rs repoSwitch) serveObject(w http.ResponseWriter, r *http.Request, obj *storageObject, code int, name, prefix string) error {
+	defer func() {
+		if obj.Body != nil {
+			obj.Body.Close()
 		}
+	}()
+
+	h := w.Header()
+
+	encoding := strings.TrimSpace(obj.Header.Get("Content-Encoding"))
+	isGzip := strings.ToLower(encoding) == "gzip"
 
-		if canGzip {
+	// encoded objects are chosen by Accept-Encoding
+	if len(encoding) != 0 {
+		h.Set("Vary", "Accept-Encoding")
+	}
+
+	if len(prefix) != 0 && (isGzip || len(encoding) == 0) && isHTML(obj.Header.Get("Content-Type")) {
+		if code == http.StatusOK {
+			h.Set("Accept-Ranges", "none")
+		}
+
+		if err := rs.loadBody(r, obj, name); err != nil {
+			return err
+		}
+
+		return rs.serveRewrittenHTML(w, r, obj, code, prefix, isGzip)
+	}
+
+	if isGzip {
+		if negotiateEncoding(r, []string{"gzip"}) == "gzip" {
 			h.Set("Content-Encoding", "gzip")
 		} else {
+			h.Del("Content-Encoding")
 			h.Del("Content-Length")
 
+			// the decompressed length is unknown
+			if code == http.StatusOK {
+				h.Set("Accept-Ranges", "none")
+			}
+
 			if r.Method == http.MethodHead {
 				w.WriteHeader(code)
-
-				resp.Body.Close()
 				return nil
 			}
 
-			gr, err := gzip.NewReader(resp.Body)
+			if err := rs.loadBody(r, obj, name); err != nil {
+				return err
+			}
+
+			gr, err := gzip.NewReader(obj.Body)
 			if err != nil {
 				return err
 			}
@@ -224,22 +426,87 @@ func (repoSwitch) serveS3Response(w http.ResponseWriter, r *http.Request, resp *
 
 			copyBuffer(w, gr)
 			gr.Close()
-			resp.Body.Close()
 			return nil
 		}
 	} else if len(encoding) != 0 {
-		// unkown encoding
-		w.Header().Set("Content-Encoding", encoding)
+		// a variant, or an unknown encoding
+		h.Set("Content-Encoding", encoding)
+
+		if etag := h.Get("Etag"); len(etag) != 0 {
+			h.Set("Etag", variantETag(etag, strings.ToLower(encoding)))
+
+			if code == http.StatusOK && checkETag(w, r) {
+				return nil
+			}
+		}
+	}
+
+	if code == http.StatusOK {
+		h.Set("Accept-Ranges", "bytes")
+
+		if r.Method == http.MethodGet {
+			if served, err := rs.serveRange(w, r, obj, name); served || err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := rs.loadBody(r, obj, name); err != nil {
+		return err
+	}
+
+	w.WriteHeader(code)
+
+	if r.Method == http.MethodHead {
+		return nil
+	}
+
+	copyBuffer(w, obj.Body)
+	return nil
+}
+
+func (repoSwitch) serveRewrittenHTML(w http.ResponseWriter, r *http.Request, obj *storageObject, code int, prefix string, isGzip bool) error {
+	var body io.Reader = obj.Body
+
+	if isGzip && r.Method != http.MethodHead {
+		gr, err := gzip.NewReader(obj.Body)
+		if err != nil {
+			return err
+		}
+
+		defer gr.Close()
+		body = gr
+	}
+
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Vary", "Accept-Encoding")
+
+	canGzip := negotiateEncoding(r, []string{"gzip"}) == "gzip"
+	if canGzip {
+		h.Set("Content-Encoding", "gzip")
+	} else {
+		h.Del("Content-Encoding")
 	}
 
 	w.WriteHeader(code)
 
 	if r.Method == http.MethodHead {
-		resp.Body.Close()
 		return nil
 	}
 
-	copyBuffer(w, resp.Body)
-	resp.Body.Close()
+	out := io.Writer(w)
+
+	if canGzip {
+		gzw := gzip.NewWriter(w)
+		defer gzw.Close()
+
+		out = gzw
+	}
+
+	if err := rewriteHTML(out, body, prefix); err != nil {
+		log.Printf("%[1]T: %[1]v", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,158 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testTag = "0123456789abcdef0123456789abcdef"
+
+func newTestRepoSwitch(t *testing.T) *repoSwitch {
+	store := newMemoryStorage()
+
+	put := func(name, body string, header http.Header) {
+		if err := store.Put(tagPrefix(testTag)+"/"+name, bytes.NewReader([]byte(body)), int64(len(body)), header); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	put("index.html", "index", http.Header{"Content-Type": {"text/html; charset=utf-8"}})
+	put("404.html", "not found", http.Header{"Content-Type": {"text/html; charset=utf-8"}})
+	put("links.html", `<a href="/about/">about</a>`, http.Header{"Content-Type": {"text/html; charset=utf-8"}})
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	gzw.Write([]byte("compressed"))
+	gzw.Close()
+
+	put("gzip.txt", buf.String(), http.Header{
+		"Content-Encoding": {"gzip"},
+		"Content-Type":     {"text/plain; charset=utf-8"},
+	})
+
+	return &repoSwitch{
+		Storage: store,
+		Hosts: &hostConfig{
+			Primary: "jekyllhistory.org",
+		},
+	}
+}
+
+func TestRepoSwitch(t *testing.T) {
+	rs := newTestRepoSwitch(t)
+
+	for _, test := range []struct {
+		method, path, accept string
+
+		code int
+		body string
+		enc  string
+	}{
+		{http.MethodGet, "/", "", http.StatusOK, "index", ""},
+		{http.MethodGet, "/missing", "", http.StatusNotFound, "not found", ""},
+		{http.MethodGet, "/gzip.txt", "", http.StatusOK, "compressed", ""},
+		{http.MethodGet, "/gzip.txt", "gzip", http.StatusOK, "", "gzip"},
+		{http.MethodHead, "/", "", http.StatusOK, "", ""},
+		{http.MethodHead, "/missing", "", http.StatusNotFound, "", ""},
+		{http.MethodPost, "/", "", http.StatusMethodNotAllowed, "", ""},
+	} {
+		req := httptest.NewRequest(test.method, "http://"+testTag+".jekyllhistory.org"+test.path, nil)
+		if len(test.accept) != 0 {
+			req.Header.Set("Accept-Encoding", test.accept)
+		}
+
+		rw := httptest.NewRecorder()
+		rs.ServeHTTP(rw, req)
+
+		if rw.Code != test.code {
+			t.Errorf("%s %s returned wrong status code, expected %d, got %d", test.method, test.path, test.code, rw.Code)
+		}
+
+		if len(test.body) != 0 && rw.Body.String() != test.body {
+			t.Errorf("%s %s returned wrong body, expected %q, got %q", test.method, test.path, test.body, rw.Body.String())
+		}
+
+		if enc := rw.HeaderMap.Get("Content-Encoding"); enc != test.enc {
+			t.Errorf("%s %s returned wrong Content-Encoding, expected %q, got %q", test.method, test.path, test.enc, enc)
+		}
+	}
+}
+
+func TestRepoSwitchWrongHost(t *testing.T) {
+	rs := newTestRepoSwitch(t)
+
+	rw := httptest.NewRecorder()
+	rs.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://example.com/", nil))
+
+	if rw.Code != http.StatusForbidden {
+		t.Errorf("repoSwitch returned wrong status code for unknown host, expected %d, got %d", http.StatusForbidden, rw.Code)
+	}
+}
+
+func TestRepoSwitchServePath(t *testing.T) {
+	rs := newTestRepoSwitch(t)
+
+	for _, test := range []struct {
+		path string
+
+		code     int
+		body     string
+		location string
+	}{
+		{"/s/" + testTag + "/", http.StatusOK, "index", ""},
+		{"/s/" + testTag, http.StatusMovedPermanently, "", "/s/" + testTag + "/"},
+		{"/s/" + testTag + "/links.html", http.StatusOK, `<a href="/s/` + testTag + `/about/">about</a>`, ""},
+		{"/s/" + testTag + "/gzip.txt", http.StatusOK, "compressed", ""},
+		{"/s/" + testTag + "/missing", http.StatusNotFound, "not found", ""},
+		{"/s/not-a-tag/", http.StatusNotFound, "", ""},
+	} {
+		rw := httptest.NewRecorder()
+		rs.ServePath(rw, httptest.NewRequest(http.MethodGet, "http://jekyllhistory.org"+test.path, nil))
+
+		if rw.Code != test.code {
+			t.Errorf("GET %s returned wrong status code, expected %d, got %d", test.path, test.code, rw.Code)
+		}
+
+		if len(test.body) != 0 && rw.Body.String() != test.body {
+			t.Errorf("GET %s returned wrong body, expected %q, got %q", test.path, test.body, rw.Body.String())
+		}
+
+		if location := rw.HeaderMap.Get("Location"); location != test.location {
+			t.Errorf("GET %s returned wrong Location, expected %q, got %q", test.path, test.location, location)
+		}
+	}
+}
+
+func TestRepoSwitchServePathBaseURL(t *testing.T) {
+	rs := newTestRepoSwitch(t)
+
+	body := `<a href="/s/` + testTag + `/about/">about</a>`
+
+	if err := rs.Storage.Put(tagPrefix(testTag)+"/links.html", bytes.NewReader([]byte(body)), int64(len(body)), http.Header{
+		"Content-Type": {"text/html; charset=utf-8"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := saveSiteRules(rs.Storage, tagPrefix(testTag), &siteRules{
+		BaseURL: sitePathPrefix + testTag,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// sites built with their path as baseurl are not rewritten
+	rw := httptest.NewRecorder()
+	rs.ServePath(rw, httptest.NewRequest(http.MethodGet, "http://jekyllhistory.org/s/"+testTag+"/links.html", nil))
+
+	if rw.Code != http.StatusOK || rw.Body.String() != body {
+		t.Errorf("GET /s/%s/links.html returned %d with %q, expected %d with %q", testTag, rw.Code, rw.Body.String(), http.StatusOK, body)
+	}
+}
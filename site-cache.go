@@ -0,0 +1,114 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import "sync"
+
+// maxSiteCacheSize is roughly how many bytes of site paths
+// and rules are kept in memory.
+const maxSiteCacheSize = 32 << 20
+
+// siteInfo is what is known about a built site beyond its
+// files. Either may be nil.
+type siteInfo struct {
+	Rules *siteRules
+	Paths *sitePaths
+}
+
+func (si *siteInfo) size() int {
+	size := 64
+
+	if si.Rules != nil {
+		size += 256 * (len(si.Rules.Headers) + len(si.Rules.Redirects))
+	}
+
+	if si.Paths != nil {
+		size += 2 * si.Paths.size
+	}
+
+	return size
+}
+
+// siteCache keeps the rules and paths of recently served
+// sites, including that a site has none, so that they are
+// not loaded for every request.
+type siteCache struct {
+	mu    sync.Mutex
+	sites map[string]*siteInfo
+	size  int
+}
+
+func loadSiteInfo(store storage, tagPath string) (*siteInfo, error) {
+	rules, err := loadSiteRules(store, tagPath)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := loadSitePaths(store, tagPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &siteInfo{rules, paths}, nil
+}
+
+// load returns what is known about the built site at
+// tagPath. A nil cache loads it from store every time.
+func (c *siteCache) load(store storage, tagPath string) (*siteInfo, error) {
+	if c == nil {
+		return loadSiteInfo(store, tagPath)
+	}
+
+	c.mu.Lock()
+	si, ok := c.sites[tagPath]
+	c.mu.Unlock()
+
+	if ok {
+		return si, nil
+	}
+
+	si, err := loadSiteInfo(store, tagPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// a site that has not been built yet will have rules
+	// and paths once it is, so only sites built without
+	// them are remembered as having none
+	if si.Rules == nil && si.Paths == nil {
+		if list, err := store.List(tagPath+"/", 1); err != nil {
+			return nil, err
+		} else if len(list) == 0 {
+			return si, nil
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sites == nil {
+		c.sites = make(map[string]*siteInfo)
+	}
+
+	// another request may have loaded it first
+	if cached, ok := c.sites[tagPath]; ok {
+		return cached, nil
+	}
+
+	// built sites never change, so any entry may go
+	for k, v := range c.sites {
+		if c.size+si.size() <= maxSiteCacheSize {
+			break
+		}
+
+		delete(c.sites, k)
+		c.size -= v.size()
+	}
+
+	c.sites[tagPath] = si
+	c.size += si.size()
+	return si, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+func TestSiteCacheBeforeBuild(t *testing.T) {
+	store := newMemoryStorage()
+	tagPath := tagPrefix(testTag)
+
+	var c siteCache
+
+	// a request before the build must not be remembered
+	if si, err := c.load(store, tagPath); err != nil {
+		t.Fatal(err)
+	} else if si.Rules != nil || si.Paths != nil {
+		t.Fatalf("load returned %+v before the build, expected nothing", si)
+	}
+
+	if err := saveSiteRules(store, tagPath, &siteRules{
+		Redirects: []redirectRule{{From: "/old", To: "/new", Status: http.StatusMovedPermanently}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := saveSitePaths(store, tagPath, []string{"/index.html"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Put(tagPath+"/index.html", bytes.NewReader([]byte("index")), 5, http.Header{
+		"Content-Type": {"text/html; charset=utf-8"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	si, err := c.load(store, tagPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if si.Rules == nil || len(si.Rules.Redirects) != 1 {
+		t.Errorf("load returned rules %+v after the build, expected the saved rules", si.Rules)
+	}
+
+	if si.Paths == nil || !si.Paths.has("/index.html") {
+		t.Errorf("load returned paths %+v after the build, expected the saved paths", si.Paths)
+	}
+
+	// sites built without rules or paths are remembered
+	oldPath := tagPrefix("fedcba9876543210fedcba9876543210")
+
+	if err := store.Put(oldPath+"/index.html", bytes.NewReader([]byte("index")), 5, http.Header{
+		"Content-Type": {"text/html; charset=utf-8"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.load(store, oldPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.sites[oldPath]; !ok {
+		t.Error("load did not cache a site built without rules or paths")
+	}
+}
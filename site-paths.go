@@ -0,0 +1,167 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// sitePaths indexes the files of a built site, and the
+// encodings they are stored in, so that requests can be
+// resolved without storage lookups. It is stored next to
+// the site as a newline separated list, see sitePathsLine.
+type sitePaths struct {
+	files     map[string]bool
+	lower     map[string]string
+	encodings map[string][]string
+
+	size int
+}
+
+// sitePathsLine returns the line of the index for the file
+// p stored in encodings, the first being that of the file
+// itself and the rest variants.
+func sitePathsLine(p string, encodings []string) string {
+	if len(encodings) == 0 {
+		return p
+	}
+
+	return p + "\t" + strings.Join(encodings, ",")
+}
+
+func newSitePaths(lines []string) *sitePaths {
+	sp := &sitePaths{
+		files:     make(map[string]bool, len(lines)),
+		lower:     make(map[string]string, len(lines)),
+		encodings: make(map[string][]string),
+	}
+
+	for _, line := range lines {
+		p := line
+		if idx := strings.IndexByte(line, '\t'); idx != -1 {
+			p = line[:idx]
+			sp.encodings[p] = splitList(line[idx+1:])
+		}
+
+		sp.files[p] = true
+		sp.size += len(line)
+
+		if _, dup := sp.lower[strings.ToLower(p)]; !dup {
+			sp.lower[strings.ToLower(p)] = p
+		}
+	}
+
+	return sp
+}
+
+func (sp *sitePaths) has(p string) bool {
+	return sp.files[p]
+}
+
+// variant returns the encoding of the stored variant of the
+// file p that is best for r, or an empty string if the file
+// itself is best.
+func (sp *sitePaths) variant(r *http.Request, p string) string {
+	encodings := sp.encodings[p]
+	if len(encodings) < 2 {
+		return ""
+	}
+
+	return chooseVariant(r, encodings[0], encodings[1:])
+}
+
+// resolve returns the file that serves name as GitHub
+// Pages would, or the path to redirect to. /x is served
+// from x or x.html, and redirected to /x/ if x/index.html
+// exists. Failing that, paths that differ only in case are
+// redirected to. Both are empty if nothing matches.
+func (sp *sitePaths) resolve(name string) (file, redirect string) {
+	clean := path.Clean("/" + name)
+	if strings.HasSuffix(name, "/") && clean != "/" {
+		clean += "/"
+	}
+
+	for _, exact := range [...]bool{true, false} {
+		lookup := func(p string) (string, bool) {
+			if exact {
+				return p, sp.files[p]
+			}
+
+			p, ok := sp.lower[strings.ToLower(p)]
+			return p, ok
+		}
+
+		if strings.HasSuffix(clean, "/") {
+			if p, ok := lookup(clean + "index.html"); ok && exact {
+				return p, ""
+			} else if ok {
+				return "", strings.TrimSuffix(p, "index.html")
+			}
+
+			continue
+		}
+
+		if p, ok := lookup(clean); ok && exact {
+			return p, ""
+		} else if ok {
+			return "", p
+		}
+
+		if p, ok := lookup(clean + ".html"); ok && exact {
+			return p, ""
+		} else if ok {
+			return "", strings.TrimSuffix(p, ".html")
+		}
+
+		if p, ok := lookup(clean + "/index.html"); ok {
+			return "", strings.TrimSuffix(p, "index.html")
+		}
+	}
+
+	return "", ""
+}
+
+func saveSitePaths(store storage, tagPath string, lines []string) error {
+	data := []byte(strings.Join(lines, "\n"))
+
+	return store.Put(tagPath+".paths", bytes.NewReader(data), int64(len(data)), http.Header{
+		"Cache-Control": {builtRepoCacheControl},
+		"Content-Type":  {"text/plain; charset=utf-8"},
+	})
+}
+
+// loadSitePaths returns the index of a built site or nil
+// if it was built without one.
+func loadSitePaths(store storage, tagPath string) (*sitePaths, error) {
+	obj, err := store.Get(tagPath + ".paths")
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	defer obj.Body.Close()
+
+	var lines []string
+
+	s := bufio.NewScanner(obj.Body)
+	for s.Scan() {
+		if line := s.Text(); len(line) != 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	if err = s.Err(); err != nil {
+		return nil, err
+	}
+
+	return newSitePaths(lines), nil
+}
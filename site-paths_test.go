@@ -0,0 +1,201 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSitePathsResolve(t *testing.T) {
+	sp := newSitePaths([]string{
+		"/index.html",
+		"/about.html",
+		"/docs/index.html",
+		"/docs/Guide.html",
+		"/feed.xml",
+		"/both",
+		"/both.html",
+		"/both/index.html",
+	})
+
+	for _, test := range []struct {
+		name           string
+		file, redirect string
+	}{
+		{"/", "/index.html", ""},
+		{"/feed.xml", "/feed.xml", ""},
+		{"/about", "/about.html", ""},
+		{"/about.html", "/about.html", ""},
+		{"/docs", "", "/docs/"},
+		{"/docs/", "/docs/index.html", ""},
+		{"/docs//", "/docs/index.html", ""},
+		{"/docs/Guide", "/docs/Guide.html", ""},
+		{"/both", "/both", ""},
+		{"/ABOUT", "", "/about"},
+		{"/About.HTML", "", "/about.html"},
+		{"/DOCS", "", "/docs/"},
+		{"/Docs/", "", "/docs/"},
+		{"/docs/guide", "", "/docs/Guide"},
+		{"/missing", "", ""},
+		{"/missing/", "", ""},
+		{"/about/", "", ""},
+	} {
+		file, redirect := sp.resolve(test.name)
+		if file != test.file || redirect != test.redirect {
+			t.Errorf("resolve(%q) returned %q, %q, expected %q, %q", test.name, file, redirect, test.file, test.redirect)
+		}
+	}
+}
+
+func TestSitePathsVariant(t *testing.T) {
+	sp := newSitePaths([]string{
+		sitePathsLine("/index.html", []string{"gzip", "br", "zstd"}),
+		sitePathsLine("/feed.xml", []string{"gzip"}),
+		sitePathsLine("/image.png", nil),
+	})
+
+	for _, test := range []struct {
+		name, accept, variant string
+	}{
+		{"/index.html", "", ""},
+		{"/index.html", "gzip", ""},
+		{"/index.html", "gzip, br", "br"},
+		{"/index.html", "gzip, zstd", "zstd"},
+		{"/feed.xml", "br", ""},
+		{"/image.png", "br", ""},
+	} {
+		if !sp.has(test.name) {
+			t.Errorf("has(%q) returned false", test.name)
+		}
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Encoding", test.accept)
+
+		if variant := sp.variant(r, test.name); variant != test.variant {
+			t.Errorf("variant(%q) with Accept-Encoding %q returned %q, expected %q", test.name, test.accept, variant, test.variant)
+		}
+	}
+}
+
+func TestRepoSwitchPaths(t *testing.T) {
+	rs := newTestRepoSwitch(t)
+
+	for name, body := range map[string]string{
+		"about.html":      "about",
+		"docs/index.html": "docs",
+		"a b/index.html":  "a b",
+	} {
+		if err := rs.Storage.Put(tagPrefix(testTag)+"/"+name, bytes.NewReader([]byte(body)), int64(len(body)), http.Header{
+			"Content-Type": {"text/html; charset=utf-8"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path string
+
+		code     int
+		body     string
+		location string
+
+		// sites built without an index do not ignore case
+		unindexedCode int
+	}{
+		{"/about", http.StatusOK, "about", "", http.StatusOK},
+		{"/docs", http.StatusMovedPermanently, "", "/docs/", http.StatusMovedPermanently},
+		{"/docs?a=b", http.StatusMovedPermanently, "", "/docs/?a=b", http.StatusMovedPermanently},
+		{"/docs/", http.StatusOK, "docs", "", http.StatusOK},
+		{"/About", http.StatusMovedPermanently, "", "/about", http.StatusNotFound},
+		{"/Docs/", http.StatusMovedPermanently, "", "/docs/", http.StatusNotFound},
+		{"/missing", http.StatusNotFound, "not found", "", http.StatusNotFound},
+		{"/a%20b", http.StatusMovedPermanently, "", "/a%20b/", http.StatusMovedPermanently},
+		{"/A%20B/", http.StatusMovedPermanently, "", "/a%20b/", http.StatusNotFound},
+	}
+
+	for _, indexed := range []bool{false, true} {
+		if indexed {
+			if err := saveSitePaths(rs.Storage, tagPrefix(testTag), []string{
+				"/404.html",
+				"/a b/index.html",
+				"/about.html",
+				"/docs/index.html",
+				"/gzip.txt",
+				"/index.html",
+				"/links.html",
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			rs.Sites = new(siteCache)
+		}
+
+		for _, test := range tests {
+			code := test.code
+			if !indexed {
+				code = test.unindexedCode
+			}
+
+			rw := httptest.NewRecorder()
+			rs.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://"+testTag+".jekyllhistory.org"+test.path, nil))
+
+			if rw.Code != code {
+				t.Errorf("GET %s returned wrong status code with index %t, expected %d, got %d", test.path, indexed, code, rw.Code)
+				continue
+			}
+
+			if code != test.code {
+				continue
+			}
+
+			if len(test.body) != 0 && rw.Body.String() != test.body {
+				t.Errorf("GET %s returned wrong body with index %t, expected %q, got %q", test.path, indexed, test.body, rw.Body.String())
+			}
+
+			if location := rw.HeaderMap.Get("Location"); location != test.location {
+				t.Errorf("GET %s returned wrong Location with index %t, expected %q, got %q", test.path, indexed, test.location, location)
+			}
+		}
+	}
+
+	// site paths are redirected under the prefix
+	rw := httptest.NewRecorder()
+	rs.ServePath(rw, httptest.NewRequest(http.MethodGet, "http://jekyllhistory.org/s/"+testTag+"/docs", nil))
+
+	if location := "/s/" + testTag + "/docs/"; rw.Code != http.StatusMovedPermanently || rw.HeaderMap.Get("Location") != location {
+		t.Errorf("GET /s/%s/docs returned %d to %q, expected %d to %q", testTag, rw.Code, rw.HeaderMap.Get("Location"), http.StatusMovedPermanently, location)
+	}
+
+	// resolving paths from the index needs no lookups
+	counter := &countingStorage{storage: rs.Storage}
+	rs.Storage = counter
+
+	for _, p := range []string{"/docs", "/About", "/Docs/"} {
+		rs.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://"+testTag+".jekyllhistory.org"+p, nil))
+	}
+
+	if counter.lookups != 0 {
+		t.Errorf("redirects made %d storage lookups, expected none", counter.lookups)
+	}
+}
+
+type countingStorage struct {
+	storage
+	lookups int
+}
+
+func (s *countingStorage) Get(name string) (*storageObject, error) {
+	s.lookups++
+	return s.storage.Get(name)
+}
+
+func (s *countingStorage) Head(name string) (*storageObject, error) {
+	s.lookups++
+	return s.storage.Head(name)
+}
@@ -0,0 +1,433 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxSiteRules is the most header or redirect rules kept
+// for a build.
+const maxSiteRules = 1000
+
+// reservedSiteHeaders are set by the service, or would
+// reach beyond the site, and cannot be changed by a
+// _headers file.
+var reservedSiteHeaders = map[string]bool{
+	"Accept-Ranges":                       true,
+	"Clear-Site-Data":                     true,
+	"Connection":                          true,
+	"Content-Encoding":                    true,
+	"Content-Length":                      true,
+	"Content-Range":                       true,
+	"Content-Security-Policy-Report-Only": true,
+	"Etag":                                true,
+	"Last-Modified":                       true,
+	"Location":                            true,
+	"Report-To":                           true,
+	"Service-Worker-Allowed":              true,
+	"Set-Cookie":                          true,
+	"Strict-Transport-Security":           true,
+	"Transfer-Encoding":                   true,
+	"Vary":                                true,
+}
+
+// originSiteHeaders affect the whole origin a page is
+// served from, so they are dropped when sites share the
+// origin of the service.
+var originSiteHeaders = map[string]bool{
+	"Access-Control-Allow-Credentials": true,
+	"Access-Control-Allow-Headers":     true,
+	"Access-Control-Allow-Methods":     true,
+	"Access-Control-Allow-Origin":      true,
+	"Access-Control-Expose-Headers":    true,
+	"Access-Control-Max-Age":           true,
+	"Cross-Origin-Embedder-Policy":     true,
+	"Cross-Origin-Opener-Policy":       true,
+	"Nel":                              true,
+	"Origin-Agent-Cluster":             true,
+}
+
+// siteRules are the header and redirect rules of a built
+// site. They are read from the Netlify-style _headers and
+// _redirects files in the build output and stored as JSON
+// next to the site, along with the baseurl it was built
+// with.
+type siteRules struct {
+	BaseURL string `json:"base_url,omitempty"`
+
+	Headers   []headerRule   `json:"headers,omitempty"`
+	Redirects []redirectRule `json:"redirects,omitempty"`
+}
+
+type headerRule struct {
+	Path   string      `json:"path"`
+	Header http.Header `json:"header"`
+}
+
+type redirectRule struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Status int    `json:"status"`
+	Force  bool   `json:"force,omitempty"`
+}
+
+// matchSitePath matches p against pattern. A pattern may
+// end in a * segment, which matches the rest of the path,
+// and :name segments match a single segment. Trailing
+// slashes are ignored. The matched segments are returned
+// keyed by name, with the rest of the path as splat.
+func matchSitePath(pattern, p string) (map[string]string, bool) {
+	pattern = strings.TrimSuffix(pattern, "/")
+	p = strings.TrimSuffix(p, "/")
+
+	segs, ps := strings.Split(pattern, "/"), strings.Split(p, "/")
+	params := make(map[string]string)
+
+	for i, seg := range segs {
+		if seg == "*" {
+			if i < len(ps) {
+				params["splat"] = strings.Join(ps[i:], "/")
+			} else {
+				params["splat"] = ""
+			}
+
+			return params, true
+		}
+
+		if i >= len(ps) {
+			return nil, false
+		}
+
+		if strings.HasPrefix(seg, ":") && len(seg) > 1 {
+			if len(ps[i]) == 0 {
+				return nil, false
+			}
+
+			params[seg[1:]] = ps[i]
+		} else if seg != ps[i] {
+			return nil, false
+		}
+	}
+
+	return params, len(segs) == len(ps)
+}
+
+var sitePathParamRegexp = regexp.MustCompile(`:[A-Za-z0-9_]+`)
+
+// expandSitePath replaces the :name placeholders in s with
+// params. Unknown placeholders are left as they are.
+func expandSitePath(s string, params map[string]string) string {
+	return sitePathParamRegexp.ReplaceAllStringFunc(s, func(name string) string {
+		if v, ok := params[name[1:]]; ok {
+			return v
+		}
+
+		return name
+	})
+}
+
+// header returns the headers the rules set for p. If the
+// site shares the origin of the service, originSiteHeaders
+// are left out.
+func (sr *siteRules) header(p string, sharedOrigin bool) http.Header {
+	if sr == nil {
+		return nil
+	}
+
+	var h http.Header
+
+	for _, rule := range sr.Headers {
+		if _, ok := matchSitePath(rule.Path, p); !ok {
+			continue
+		}
+
+		if h == nil {
+			h = make(http.Header)
+		}
+
+		for k, v := range rule.Header {
+			if sharedOrigin && originSiteHeaders[k] {
+				continue
+			}
+
+			h[k] = append(h[k], v...)
+		}
+	}
+
+	return h
+}
+
+// redirect returns the first redirect rule that matches p,
+// along with the target.
+func (sr *siteRules) redirect(p string) (*redirectRule, string) {
+	if sr == nil {
+		return nil, ""
+	}
+
+	for i := range sr.Redirects {
+		rule := &sr.Redirects[i]
+
+		if params, ok := matchSitePath(rule.From, p); ok {
+			return rule, expandSitePath(rule.To, params)
+		}
+	}
+
+	return nil, ""
+}
+
+// isSitePattern reports whether s is a path pattern that
+// is supported. Patterns naming a host are not.
+func isSitePattern(s string) bool {
+	if !strings.HasPrefix(s, "/") || strings.HasPrefix(s, "//") {
+		return false
+	}
+
+	for _, seg := range strings.Split(s, "/") {
+		if strings.Contains(seg, "*") && seg != "*" {
+			return false
+		}
+	}
+
+	return !strings.Contains(s, "*") || strings.HasSuffix(s, "/*")
+}
+
+// parseHeadersFile adds the rules in a _headers file to
+// sr. Rules that cannot be used are logged as warnings.
+func (sr *siteRules) parseHeadersFile(r io.Reader, buildLog io.Writer) error {
+	s := bufio.NewScanner(r)
+
+	var rule *headerRule
+
+	for line := 1; s.Scan(); line++ {
+		text := s.Text()
+
+		trimmed := strings.TrimSpace(text)
+		if len(trimmed) == 0 || trimmed[0] == '#' {
+			continue
+		}
+
+		if text[0] != ' ' && text[0] != '\t' {
+			rule = nil
+
+			if !isSitePattern(trimmed) {
+				fmt.Fprintf(buildLog, "Warning: _headers:%d: unsupported path %q\n", line, trimmed)
+				continue
+			}
+
+			if len(sr.Headers) >= maxSiteRules {
+				fmt.Fprintf(buildLog, "Warning: _headers:%d: more than %d rules\n", line, maxSiteRules)
+				break
+			}
+
+			sr.Headers = append(sr.Headers, headerRule{
+				Path:   trimmed,
+				Header: make(http.Header),
+			})
+			rule = &sr.Headers[len(sr.Headers)-1]
+			continue
+		}
+
+		if rule == nil {
+			continue
+		}
+
+		idx := strings.IndexByte(trimmed, ':')
+		if idx <= 0 {
+			fmt.Fprintf(buildLog, "Warning: _headers:%d: invalid header %q\n", line, trimmed)
+			continue
+		}
+
+		key := http.CanonicalHeaderKey(strings.TrimSpace(trimmed[:idx]))
+		if reservedSiteHeaders[key] {
+			fmt.Fprintf(buildLog, "Warning: _headers:%d: %s cannot be set\n", line, key)
+			continue
+		}
+
+		rule.Header.Add(key, strings.TrimSpace(trimmed[idx+1:]))
+	}
+
+	return s.Err()
+}
+
+// parseRedirectsFile adds the rules in a _redirects file
+// to sr. Rules that cannot be used are logged as warnings.
+func (sr *siteRules) parseRedirectsFile(r io.Reader, buildLog io.Writer) error {
+	s := bufio.NewScanner(r)
+
+	for line := 1; s.Scan(); line++ {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if len(fields) < 2 {
+			fmt.Fprintf(buildLog, "Warning: _redirects:%d: missing target\n", line)
+			continue
+		}
+
+		// query parameters to match come between the path
+		// and the target, which may itself have a query
+		if to := fields[1]; strings.Contains(to, "=") && !strings.HasPrefix(to, "/") && !strings.Contains(to, "://") {
+			fmt.Fprintf(buildLog, "Warning: _redirects:%d: query parameters are not supported\n", line)
+			continue
+		}
+
+		rule := redirectRule{
+			From:   fields[0],
+			To:     fields[1],
+			Status: http.StatusMovedPermanently,
+		}
+
+		if !isSitePattern(rule.From) {
+			fmt.Fprintf(buildLog, "Warning: _redirects:%d: unsupported path %q\n", line, rule.From)
+			continue
+		}
+
+		rest := fields[2:]
+
+		var status string
+		if len(rest) != 0 && !strings.Contains(rest[0], "=") {
+			status, rest = rest[0], rest[1:]
+		}
+
+		if len(rest) != 0 && strings.Contains(rest[0], "=") {
+			fmt.Fprintf(buildLog, "Warning: _redirects:%d: conditions are not supported\n", line)
+			continue
+		} else if len(rest) != 0 {
+			fmt.Fprintf(buildLog, "Warning: _redirects:%d: unexpected %q\n", line, rest[0])
+			continue
+		}
+
+		if len(status) != 0 {
+			rule.Force = strings.HasSuffix(status, "!")
+
+			code, err := strconv.Atoi(strings.TrimSuffix(status, "!"))
+			if err != nil {
+				fmt.Fprintf(buildLog, "Warning: _redirects:%d: invalid status %q\n", line, status)
+				continue
+			}
+
+			rule.Status = code
+		}
+
+		isRedirect := isRedirectStatus(rule.Status)
+
+		switch {
+		case rule.Status != http.StatusOK && !isRedirect && (rule.Status < 400 || rule.Status >= 500):
+			fmt.Fprintf(buildLog, "Warning: _redirects:%d: unsupported status %d\n", line, rule.Status)
+			continue
+		case !isRedirect && !strings.HasPrefix(rule.To, "/"):
+			fmt.Fprintf(buildLog, "Warning: _redirects:%d: proxying to %s is not supported\n", line, rule.To)
+			continue
+		case isRedirect && !strings.HasPrefix(rule.To, "/"):
+			if u, err := url.Parse(rule.To); err != nil || !u.IsAbs() {
+				fmt.Fprintf(buildLog, "Warning: _redirects:%d: invalid target %q\n", line, rule.To)
+				continue
+			}
+		}
+
+		if len(sr.Redirects) >= maxSiteRules {
+			fmt.Fprintf(buildLog, "Warning: _redirects:%d: more than %d rules\n", line, maxSiteRules)
+			break
+		}
+
+		sr.Redirects = append(sr.Redirects, rule)
+	}
+
+	return s.Err()
+}
+
+// parseRulesFile parses the named file of the build
+// output with parse, if it exists.
+func parseRulesFile(name string, parse func(r io.Reader, buildLog io.Writer) error, buildLog io.Writer) error {
+	info, err := os.Lstat(name)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		fmt.Fprintf(buildLog, "skipping %s: not a regular file\n", filepath.Base(name))
+		return nil
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	return parse(f, buildLog)
+}
+
+func isRedirectStatus(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	default:
+		return false
+	}
+}
+
+// baseURL returns the baseurl the site was built with.
+func (sr *siteRules) baseURL() string {
+	if sr == nil {
+		return ""
+	}
+
+	return sr.BaseURL
+}
+
+func (sr *siteRules) empty() bool {
+	return len(sr.BaseURL) == 0 && len(sr.Headers) == 0 && len(sr.Redirects) == 0
+}
+
+func saveSiteRules(store storage, tagPath string, sr *siteRules) error {
+	data, err := json.Marshal(sr)
+	if err != nil {
+		return err
+	}
+
+	return store.Put(tagPath+".rules", bytes.NewReader(data), int64(len(data)), http.Header{
+		"Cache-Control": {builtRepoCacheControl},
+		"Content-Type":  {"application/json; charset=utf-8"},
+	})
+}
+
+// loadSiteRules returns the rules of a built site or nil
+// if it has none.
+func loadSiteRules(store storage, tagPath string) (*siteRules, error) {
+	obj, err := store.Get(tagPath + ".rules")
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	defer obj.Body.Close()
+
+	var sr siteRules
+	if err = json.NewDecoder(obj.Body).Decode(&sr); err != nil {
+		return nil, err
+	}
+
+	return &sr, nil
+}
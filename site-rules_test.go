@@ -0,0 +1,225 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchSitePath(t *testing.T) {
+	for _, test := range []struct {
+		pattern, path string
+
+		ok     bool
+		params map[string]string
+	}{
+		{"/", "/", true, map[string]string{}},
+		{"/about", "/about/", true, map[string]string{}},
+		{"/about/", "/about", true, map[string]string{}},
+		{"/about", "/contact", false, nil},
+		{"/about", "/about/team", false, nil},
+		{"/*", "/", true, map[string]string{"splat": ""}},
+		{"/blog/*", "/blog/2016/post.html", true, map[string]string{"splat": "2016/post.html"}},
+		{"/blog/*", "/news/", false, nil},
+		{"/blog/:year/:slug", "/blog/2016/post", true, map[string]string{"year": "2016", "slug": "post"}},
+		{"/blog/:year/:slug", "/blog/2016", false, nil},
+	} {
+		params, ok := matchSitePath(test.pattern, test.path)
+		if ok != test.ok || ok && !reflect.DeepEqual(params, test.params) {
+			t.Errorf("matchSitePath(%q, %q) returned %v, %t, expected %v, %t", test.pattern, test.path, params, ok, test.params, test.ok)
+		}
+	}
+}
+
+func TestParseRedirectsFile(t *testing.T) {
+	var sr siteRules
+	var buildLog bytes.Buffer
+
+	if err := sr.parseRedirectsFile(strings.NewReader(`# comment
+/old /new
+/blog/:year/:slug /posts/:year-:slug 302
+/docs/* /documentation/:splat 301!
+/app/* /app/index.html 200
+/gone /404.html 404
+/external https://example.com/ 307
+/find /search?q=foo 301
+/query https://example.com/?a=b
+
+/missing
+/proxy https://example.com/api 200
+/store id=:id /blog/:id 301
+/country /uk 302 Country=gb
+/wild/*.html /other
+/bad /other teapot
+/weird /other 500
+/language /en 302 Language=en
+/extra /other 301 junk
+`), &buildLog); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []redirectRule{
+		{From: "/old", To: "/new", Status: http.StatusMovedPermanently},
+		{From: "/blog/:year/:slug", To: "/posts/:year-:slug", Status: http.StatusFound},
+		{From: "/docs/*", To: "/documentation/:splat", Status: http.StatusMovedPermanently, Force: true},
+		{From: "/app/*", To: "/app/index.html", Status: http.StatusOK},
+		{From: "/gone", To: "/404.html", Status: http.StatusNotFound},
+		{From: "/external", To: "https://example.com/", Status: http.StatusTemporaryRedirect},
+		{From: "/find", To: "/search?q=foo", Status: http.StatusMovedPermanently},
+		{From: "/query", To: "https://example.com/?a=b", Status: http.StatusMovedPermanently},
+	}
+	if !reflect.DeepEqual(sr.Redirects, expect) {
+		t.Errorf("parseRedirectsFile returned %+v, expected %+v", sr.Redirects, expect)
+	}
+
+	if _, warnings := parseBuildLog(buildLog.Bytes()); len(warnings) != 9 {
+		t.Errorf("parseRedirectsFile logged %d warnings, expected 9:\n%s", len(warnings), buildLog.String())
+	}
+}
+
+func TestParseHeadersFile(t *testing.T) {
+	var sr siteRules
+	var buildLog bytes.Buffer
+
+	if err := sr.parseHeadersFile(strings.NewReader(`# comment
+/*
+  X-Frame-Options: DENY
+  x-content-type-options: nosniff
+  Set-Cookie: a=b
+  Strict-Transport-Security: max-age=31536000
+  Service-Worker-Allowed: /
+
+/assets/*
+  Cache-Control: public, max-age=60
+  Link: </a.css>; rel=preload
+  Link: </b.js>; rel=preload
+
+https://example.com/*
+  X-Ignored: true
+`), &buildLog); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []headerRule{
+		{Path: "/*", Header: http.Header{
+			"X-Frame-Options":        {"DENY"},
+			"X-Content-Type-Options": {"nosniff"},
+		}},
+		{Path: "/assets/*", Header: http.Header{
+			"Cache-Control": {"public, max-age=60"},
+			"Link":          {"</a.css>; rel=preload", "</b.js>; rel=preload"},
+		}},
+	}
+	if !reflect.DeepEqual(sr.Headers, expect) {
+		t.Errorf("parseHeadersFile returned %+v, expected %+v", sr.Headers, expect)
+	}
+
+	if _, warnings := parseBuildLog(buildLog.Bytes()); len(warnings) != 4 {
+		t.Errorf("parseHeadersFile logged %d warnings, expected 4:\n%s", len(warnings), buildLog.String())
+	}
+}
+
+func TestRepoSwitchRules(t *testing.T) {
+	rs := newTestRepoSwitch(t)
+	rs.Sites = new(siteCache)
+
+	if err := saveSiteRules(rs.Storage, tagPrefix(testTag), &siteRules{
+		Headers: []headerRule{
+			{Path: "/*", Header: http.Header{
+				"X-Frame-Options":             {"DENY"},
+				"Access-Control-Allow-Origin": {"*"},
+			}},
+			{Path: "/links.html", Header: http.Header{
+				"Cache-Control":   {"no-cache"},
+				"X-Frame-Options": {"SAMEORIGIN"},
+			}},
+		},
+		Redirects: []redirectRule{
+			{From: "/gzip.txt", To: "/forced", Status: http.StatusFound, Force: true},
+			{From: "/links.html", To: "/shadowed", Status: http.StatusFound},
+			{From: "/links.html", To: "/later", Status: http.StatusFound, Force: true},
+			{From: "/first", To: "/one", Status: http.StatusFound},
+			{From: "/first", To: "/two", Status: http.StatusFound, Force: true},
+			{From: "/", To: "/shadowed", Status: http.StatusFound},
+			{From: "/old/:page", To: "/:page.html", Status: http.StatusMovedPermanently},
+			{From: "/external", To: "https://example.com/", Status: http.StatusTemporaryRedirect},
+			{From: "/app/*", To: "/index.html", Status: http.StatusOK},
+			{From: "/gone", To: "/404.html", Status: http.StatusGone},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		path string
+
+		code     int
+		body     string
+		location string
+		header   http.Header
+	}{
+		{"/", http.StatusOK, "index", "", http.Header{
+			"X-Frame-Options":             {"DENY"},
+			"Access-Control-Allow-Origin": {"*"},
+			"Cache-Control":               {builtRepoCacheControl},
+		}},
+		{"/links.html", http.StatusOK, `<a href="/about/">about</a>`, "", http.Header{
+			"X-Frame-Options": {"DENY, SAMEORIGIN"},
+			"Cache-Control":   {"no-cache"},
+		}},
+		{"/gzip.txt", http.StatusFound, "", "/forced", nil},
+		{"/first", http.StatusFound, "", "/one", nil},
+		{"/old/links?a=b", http.StatusMovedPermanently, "", "/links.html?a=b", nil},
+		{"/external", http.StatusTemporaryRedirect, "", "https://example.com/", nil},
+		{"/app/some/route", http.StatusOK, "index", "", nil},
+		{"/gone", http.StatusGone, "not found", "", nil},
+		{"/missing", http.StatusNotFound, "not found", "", http.Header{
+			"X-Frame-Options": {"DENY"},
+		}},
+	} {
+		rw := httptest.NewRecorder()
+		rs.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://"+testTag+".jekyllhistory.org"+test.path, nil))
+
+		if rw.Code != test.code {
+			t.Errorf("GET %s returned wrong status code, expected %d, got %d", test.path, test.code, rw.Code)
+		}
+
+		if len(test.body) != 0 && rw.Body.String() != test.body {
+			t.Errorf("GET %s returned wrong body, expected %q, got %q", test.path, test.body, rw.Body.String())
+		}
+
+		if location := rw.HeaderMap.Get("Location"); location != test.location {
+			t.Errorf("GET %s returned wrong Location, expected %q, got %q", test.path, test.location, location)
+		}
+
+		for k, v := range test.header {
+			if got := rw.HeaderMap[k]; !reflect.DeepEqual(got, v) {
+				t.Errorf("GET %s returned wrong %s, expected %q, got %q", test.path, k, v, got)
+			}
+		}
+	}
+
+	// site paths are redirected under the prefix
+	rw := httptest.NewRecorder()
+	rs.ServePath(rw, httptest.NewRequest(http.MethodGet, "http://jekyllhistory.org/s/"+testTag+"/old/links", nil))
+
+	if location := "/s/" + testTag + "/links.html"; rw.Code != http.StatusMovedPermanently || rw.HeaderMap.Get("Location") != location {
+		t.Errorf("GET /s/%s/old/links returned %d to %q, expected %d to %q", testTag, rw.Code, rw.HeaderMap.Get("Location"), http.StatusMovedPermanently, location)
+	}
+
+	// site paths share the origin of the service
+	rw = httptest.NewRecorder()
+	rs.ServePath(rw, httptest.NewRequest(http.MethodGet, "http://jekyllhistory.org/s/"+testTag+"/", nil))
+
+	if rw.HeaderMap.Get("X-Frame-Options") != "DENY" || len(rw.HeaderMap.Get("Access-Control-Allow-Origin")) != 0 {
+		t.Errorf("GET /s/%s/ returned wrong headers %v", testTag, rw.HeaderMap)
+	}
+}
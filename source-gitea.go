@@ -0,0 +1,324 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// giteaSource is a Gitea instance accessed through
+// version 1 of its API.
+type giteaSource struct {
+	name  string
+	web   *url.URL
+	token string
+
+	API sourceAPI
+}
+
+func newGiteaSource(name string, web *url.URL, token string) *giteaSource {
+	web.Path = strings.TrimSuffix(web.Path, "/")
+
+	gs := &giteaSource{
+		name: name,
+		web:  web,
+
+		API: sourceAPI{
+			Base: web.String() + "/api/v1",
+		},
+	}
+
+	if len(token) != 0 {
+		gs.token = token
+		gs.API.Header = http.Header{
+			"Authorization": {"token " + token},
+		}
+	}
+
+	return gs
+}
+
+func (gs *giteaSource) Name() string {
+	return gs.name
+}
+
+func (*giteaSource) Title() string {
+	return "Gitea"
+}
+
+func (gs *giteaSource) WebHost() string {
+	return gs.web.Host
+}
+
+func giteaRepo(user, repo string) string {
+	return "/repos/" + url.PathEscape(user) + "/" + url.PathEscape(repo)
+}
+
+func giteaPageQuery(page, perPage int) url.Values {
+	query := pageQuery(page, perPage)
+	query.Set("limit", query.Get("per_page"))
+	return query
+}
+
+type giteaCommit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	} `json:"commit"`
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+	Files []struct {
+		Filename string `json:"filename"`
+		Status   string `json:"status"`
+	} `json:"files"`
+}
+
+func (gs *giteaSource) convertCommit(commit *giteaCommit) *sourceCommit {
+	scommit := &sourceCommit{
+		SHA:     commit.SHA,
+		Message: commit.Commit.Message,
+		HTMLURL: commit.HTMLURL,
+
+		AuthorName: commit.Commit.Author.Name,
+	}
+
+	if commit.Author != nil && len(commit.Author.Login) != 0 {
+		scommit.AuthorURL = gs.UserURL(commit.Author.Login)
+	}
+
+	for _, parent := range commit.Parents {
+		scommit.Parents = append(scommit.Parents, parent.SHA)
+	}
+
+	for _, file := range commit.Files {
+		scommit.Files = append(scommit.Files, sourceFile{
+			Filename: file.Filename,
+			Status:   file.Status,
+		})
+	}
+
+	return scommit
+}
+
+func (gs *giteaSource) ListRepos(ctx context.Context, user string, page int) ([]*sourceRepo, *sourcePage, error) {
+	var repos []struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		HTMLURL     string `json:"html_url"`
+	}
+
+	query := giteaPageQuery(page, 50)
+
+	// user is either a user or an organisation
+	resp, err := gs.API.getJSON(ctx, "/users/"+url.PathEscape(user)+"/repos", query, &repos)
+	if isSourceNotFound(err) {
+		resp, err = gs.API.getJSON(ctx, "/orgs/"+url.PathEscape(user)+"/repos", query, &repos)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	srepos := make([]*sourceRepo, len(repos))
+
+	for i, repo := range repos {
+		srepos[i] = &sourceRepo{
+			Name:        repo.Name,
+			Description: repo.Description,
+			HTMLURL:     repo.HTMLURL,
+		}
+	}
+
+	return srepos, linkPages(resp), nil
+}
+
+func (gs *giteaSource) ListCommits(ctx context.Context, user, repo, ref string, page int) ([]*sourceCommit, *sourcePage, error) {
+	query := giteaPageQuery(page, 50)
+	if len(ref) != 0 {
+		query.Set("sha", ref)
+	}
+
+	var commits []*giteaCommit
+
+	resp, err := gs.API.getJSON(ctx, giteaRepo(user, repo)+"/commits", query, &commits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scommits := make([]*sourceCommit, len(commits))
+
+	for i, commit := range commits {
+		scommits[i] = gs.convertCommit(commit)
+	}
+
+	return scommits, linkPages(resp), nil
+}
+
+func (gs *giteaSource) GetCommit(ctx context.Context, user, repo, commit string) (*sourceCommit, error) {
+	var gcommit giteaCommit
+
+	if _, err := gs.API.getJSON(ctx, giteaRepo(user, repo)+"/git/commits/"+url.PathEscape(commit), nil, &gcommit); err != nil {
+		return nil, err
+	}
+
+	return gs.convertCommit(&gcommit), nil
+}
+
+// giteaMaxRefPages is the most pages of branches or tags
+// that are read.
+const giteaMaxRefPages = 20
+
+// listAllRefs reads every page of refs from list, as Gitea
+// cannot list them newest first, and returns the newest
+// maxRefs of them.
+func listAllRefs(list func(page int) ([]*sourceRef, *http.Response, error)) ([]*sourceRef, bool, error) {
+	var refs []*sourceRef
+	var more bool
+
+	for page := 1; page <= giteaMaxRefPages; page++ {
+		pageRefs, resp, err := list(page)
+		if err != nil {
+			return nil, false, err
+		}
+
+		refs = append(refs, pageRefs...)
+
+		if linkPages(resp).NextPage == 0 || len(pageRefs) == 0 {
+			break
+		}
+
+		more = page == giteaMaxRefPages
+	}
+
+	sortRefs(refs)
+
+	if len(refs) > maxRefs {
+		return refs[:maxRefs], true, nil
+	}
+
+	return refs, more, nil
+}
+
+func (gs *giteaSource) ListBranches(ctx context.Context, user, repo string) ([]*sourceRef, bool, error) {
+	return listAllRefs(func(page int) ([]*sourceRef, *http.Response, error) {
+		var branches []struct {
+			Name   string `json:"name"`
+			Commit struct {
+				ID        string    `json:"id"`
+				Timestamp time.Time `json:"timestamp"`
+			} `json:"commit"`
+		}
+
+		resp, err := gs.API.getJSON(ctx, giteaRepo(user, repo)+"/branches", giteaPageQuery(page, maxRefs), &branches)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		refs := make([]*sourceRef, len(branches))
+
+		for i, branch := range branches {
+			refs[i] = &sourceRef{branch.Name, branch.Commit.ID, branch.Commit.Timestamp}
+		}
+
+		return refs, resp, nil
+	})
+}
+
+func (gs *giteaSource) ListTags(ctx context.Context, user, repo string) ([]*sourceRef, bool, error) {
+	return listAllRefs(func(page int) ([]*sourceRef, *http.Response, error) {
+		var tags []struct {
+			Name   string `json:"name"`
+			Commit struct {
+				SHA     string    `json:"sha"`
+				Created time.Time `json:"created"`
+			} `json:"commit"`
+		}
+
+		resp, err := gs.API.getJSON(ctx, giteaRepo(user, repo)+"/tags", giteaPageQuery(page, maxRefs), &tags)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		refs := make([]*sourceRef, len(tags))
+
+		for i, tag := range tags {
+			refs[i] = &sourceRef{tag.Name, tag.Commit.SHA, tag.Commit.Created}
+		}
+
+		return refs, resp, nil
+	})
+}
+
+func (gs *giteaSource) ResolveCommit(ctx context.Context, user, repo, ref string) (string, error) {
+	var commits []*giteaCommit
+
+	if _, err := gs.API.getJSON(ctx, giteaRepo(user, repo)+"/commits", url.Values{
+		"sha":   {ref},
+		"limit": {"1"},
+	}, &commits); err != nil {
+		return "", err
+	}
+
+	if len(commits) == 0 {
+		return "", &sourceNotFoundError{fmt.Errorf("no commit found for %s/%s@%s", user, repo, ref)}
+	}
+
+	return commits[0].SHA, nil
+}
+
+func (gs *giteaSource) Archive(ctx context.Context, user, repo, commit string) (io.ReadCloser, error) {
+	resp, err := gs.API.get(ctx, giteaRepo(user, repo)+"/archive/"+url.PathEscape(commit)+".tar.gz", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+func (gs *giteaSource) UserURL(user string) string {
+	return gs.web.String() + "/" + url.PathEscape(user)
+}
+
+func (gs *giteaSource) RepoURL(user, repo string) string {
+	return gs.UserURL(user) + "/" + url.PathEscape(repo)
+}
+
+func (gs *giteaSource) TreeURL(user, repo, tree string) string {
+	return gs.RepoURL(user, repo) + "/src/branch/" + tree
+}
+
+func (gs *giteaSource) CloneURL(user, repo string) string {
+	return gs.RepoURL(user, repo) + ".git"
+}
+
+// CloneAuthorization authenticates with the token as the
+// username, which Gitea accepts with this password.
+func (gs *giteaSource) CloneAuthorization() (string, error) {
+	if len(gs.token) == 0 {
+		return "", nil
+	}
+
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(gs.token+":x-oauth-basic")), nil
+}
+
+func (gs *giteaSource) CommitURL(user, repo, commit string) string {
+	return gs.RepoURL(user, repo) + "/commit/" + url.PathEscape(commit)
+}
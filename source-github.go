@@ -0,0 +1,493 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+type githubSource struct {
+	Client *github.Client
+
+	// HTTPClient is used to download archives.
+	HTTPClient *http.Client
+
+	// Credentials, if set, are used to clone repositories.
+	Credentials githubCredentials
+
+	// Web is the URL of the web interface of a GitHub
+	// Enterprise instance. If nil, github.com is used.
+	Web *url.URL
+}
+
+// authenticated reports whether requests are made with a
+// token or as a GitHub App.
+func (gs *githubSource) authenticated() bool {
+	return gs.HTTPClient != nil
+}
+
+func (*githubSource) Name() string {
+	return defaultSourceName
+}
+
+func (*githubSource) Title() string {
+	return "GitHub"
+}
+
+func (gs *githubSource) WebHost() string {
+	if gs.Web == nil {
+		return "github.com"
+	}
+
+	return gs.Web.Host
+}
+
+func (gs *githubSource) webURL() string {
+	if gs.Web == nil {
+		return "https://github.com"
+	}
+
+	return strings.TrimSuffix(gs.Web.String(), "/")
+}
+
+func (gs *githubSource) convertError(err error) error {
+	if gerr, ok := err.(*github.ErrorResponse); ok {
+		switch gerr.Response.StatusCode {
+		case http.StatusNotFound, http.StatusUnprocessableEntity:
+			return &sourceNotFoundError{err}
+		}
+	}
+
+	return err
+}
+
+func (*githubSource) logRate(resp *github.Response) {
+	if verbose {
+		log.Printf("GitHub API Rate Limit is %d remaining of %d, to be reset at %s\n", resp.Remaining, resp.Limit, resp.Reset)
+	}
+}
+
+func (gs *githubSource) ListRepos(ctx context.Context, user string, page int) ([]*sourceRepo, *sourcePage, error) {
+	repos, resp, err := gs.Client.Repositories.List(ctx, user, &github.RepositoryListOptions{
+		Sort: "updated",
+
+		ListOptions: github.ListOptions{
+			Page: page,
+
+			PerPage: 50,
+		},
+	})
+	if err != nil {
+		return nil, nil, gs.convertError(err)
+	}
+
+	gs.logRate(resp)
+
+	srepos := make([]*sourceRepo, len(repos))
+
+	for i, repo := range repos {
+		srepos[i] = &sourceRepo{
+			Name:        repo.GetName(),
+			Description: repo.GetDescription(),
+			HTMLURL:     repo.GetHTMLURL(),
+		}
+	}
+
+	return srepos, &sourcePage{resp.PrevPage, resp.NextPage}, nil
+}
+
+func (gs *githubSource) ListCommits(ctx context.Context, user, repo, ref string, page int) ([]*sourceCommit, *sourcePage, error) {
+	commits, resp, err := gs.Client.Repositories.ListCommits(ctx, user, repo, &github.CommitsListOptions{
+		SHA: ref,
+
+		ListOptions: github.ListOptions{
+			Page: page,
+
+			PerPage: 50,
+		},
+	})
+	if err != nil {
+		return nil, nil, gs.convertError(err)
+	}
+
+	gs.logRate(resp)
+
+	scommits := make([]*sourceCommit, len(commits))
+
+	for i, commit := range commits {
+		scommits[i] = convertGithubCommit(commit)
+	}
+
+	return scommits, &sourcePage{resp.PrevPage, resp.NextPage}, nil
+}
+
+func (gs *githubSource) GetCommit(ctx context.Context, user, repo, commit string) (*sourceCommit, error) {
+	repoCommit, resp, err := gs.Client.Repositories.GetCommit(ctx, user, repo, commit)
+	if err != nil {
+		return nil, gs.convertError(err)
+	}
+
+	gs.logRate(resp)
+
+	return convertGithubCommit(repoCommit), nil
+}
+
+func convertGithubCommit(commit *github.RepositoryCommit) *sourceCommit {
+	scommit := &sourceCommit{
+		SHA:     commit.GetSHA(),
+		HTMLURL: commit.GetHTMLURL(),
+
+		AuthorURL: commit.GetAuthor().GetHTMLURL(),
+	}
+
+	if c := commit.GetCommit(); c != nil {
+		scommit.Message = c.GetMessage()
+		scommit.AuthorName = c.GetAuthor().GetName()
+	}
+
+	for _, parent := range commit.Parents {
+		scommit.Parents = append(scommit.Parents, parent.GetSHA())
+	}
+
+	for _, file := range commit.Files {
+		scommit.Files = append(scommit.Files, sourceFile{
+			Filename: file.GetFilename(),
+			Status:   file.GetStatus(),
+			Patch:    file.GetPatch(),
+		})
+	}
+
+	return scommit
+}
+
+func (gs *githubSource) ListPulls(ctx context.Context, user, repo string, page int) ([]*sourcePull, *sourcePage, error) {
+	pulls, resp, err := gs.Client.PullRequests.List(ctx, user, repo, &github.PullRequestListOptions{
+		State: "open",
+
+		ListOptions: github.ListOptions{
+			Page: page,
+
+			PerPage: 50,
+		},
+	})
+	if err != nil {
+		return nil, nil, gs.convertError(err)
+	}
+
+	gs.logRate(resp)
+
+	spulls := make([]*sourcePull, len(pulls))
+
+	for i, pull := range pulls {
+		spulls[i] = convertGithubPull(pull)
+	}
+
+	return spulls, &sourcePage{resp.PrevPage, resp.NextPage}, nil
+}
+
+func (gs *githubSource) GetPull(ctx context.Context, user, repo string, number int) (*sourcePull, error) {
+	pull, resp, err := gs.Client.PullRequests.Get(ctx, user, repo, number)
+	if err != nil {
+		return nil, gs.convertError(err)
+	}
+
+	gs.logRate(resp)
+
+	return convertGithubPull(pull), nil
+}
+
+func convertGithubPull(pull *github.PullRequest) *sourcePull {
+	spull := &sourcePull{
+		Number:  pull.GetNumber(),
+		Title:   pull.GetTitle(),
+		HTMLURL: pull.GetHTMLURL(),
+
+		AuthorName: pull.GetUser().GetLogin(),
+		AuthorURL:  pull.GetUser().GetHTMLURL(),
+	}
+
+	head := pull.GetHead()
+	spull.HeadRef = head.GetRef()
+	spull.HeadSHA = head.GetSHA()
+
+	// if the fork was deleted, the head commit is still
+	// found in the base repository
+	repo := head.GetRepo()
+	if repo == nil {
+		repo = pull.GetBase().GetRepo()
+	}
+
+	spull.HeadUser = repo.GetOwner().GetLogin()
+	spull.HeadRepo = repo.GetName()
+
+	return spull
+}
+
+// githubRefsQuery lists the most recent refs under a prefix
+// along with the commit each points to. Annotated tags are
+// peeled to their commit.
+const githubRefsQuery = `query($owner: String!, $name: String!, $prefix: String!, $first: Int!) {
+	repository(owner: $owner, name: $name) {
+		refs(refPrefix: $prefix, first: $first, orderBy: {field: TAG_COMMIT_DATE, direction: DESC}) {
+			pageInfo { hasNextPage }
+			nodes {
+				name
+				target {
+					oid
+					... on Commit { committedDate }
+					... on Tag { target { oid ... on Commit { committedDate } } }
+				}
+			}
+		}
+	}
+}`
+
+type githubRefTarget struct {
+	OID           string    `json:"oid"`
+	CommittedDate time.Time `json:"committedDate"`
+
+	Target *githubRefTarget `json:"target"`
+}
+
+// graphQLRefs lists refs under prefix with the GraphQL API,
+// which returns their dates in the one request. It is only
+// available to authenticated clients.
+func (gs *githubSource) graphQLRefs(ctx context.Context, user, repo, prefix string) ([]*sourceRef, bool, error) {
+	// the GraphQL endpoint is /graphql on github.com and
+	// /api/graphql on GitHub Enterprise, beside /api/v3
+	endpoint := "graphql"
+	if strings.HasSuffix(gs.Client.BaseURL.Path, "/v3/") {
+		endpoint = "../graphql"
+	}
+
+	req, err := gs.Client.NewRequest(http.MethodPost, endpoint, map[string]interface{}{
+		"query": githubRefsQuery,
+		"variables": map[string]interface{}{
+			"owner":  user,
+			"name":   repo,
+			"prefix": prefix,
+			"first":  maxRefs,
+		},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	var result struct {
+		Data struct {
+			Repository *struct {
+				Refs struct {
+					PageInfo struct {
+						HasNextPage bool `json:"hasNextPage"`
+					} `json:"pageInfo"`
+
+					Nodes []struct {
+						Name   string          `json:"name"`
+						Target githubRefTarget `json:"target"`
+					} `json:"nodes"`
+				} `json:"refs"`
+			} `json:"repository"`
+		} `json:"data"`
+
+		Errors []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	resp, err := gs.Client.Do(ctx, req, &result)
+	if err != nil {
+		return nil, false, gs.convertError(err)
+	}
+
+	gs.logRate(resp)
+
+	if len(result.Errors) != 0 {
+		err := fmt.Errorf("GitHub GraphQL query failed: %s", result.Errors[0].Message)
+
+		if result.Errors[0].Type == "NOT_FOUND" {
+			return nil, false, &sourceNotFoundError{err}
+		}
+
+		return nil, false, err
+	}
+
+	if result.Data.Repository == nil {
+		return nil, false, &sourceNotFoundError{fmt.Errorf("repository %s/%s not found", user, repo)}
+	}
+
+	nodes := result.Data.Repository.Refs.Nodes
+	refs := make([]*sourceRef, len(nodes))
+
+	for i, node := range nodes {
+		target := &node.Target
+		if target.Target != nil {
+			target = target.Target
+		}
+
+		refs[i] = &sourceRef{
+			Name: node.Name,
+			SHA:  target.OID,
+			Date: target.CommittedDate,
+		}
+	}
+
+	return refs, result.Data.Repository.Refs.PageInfo.HasNextPage, nil
+}
+
+// ListBranches lists branches with their dates over GraphQL
+// if authenticated. The REST API does not return dates, and
+// fetching each commit would soon use up the rate limit of
+// anonymous clients, so they are left unknown, as they are
+// by ListTags.
+func (gs *githubSource) ListBranches(ctx context.Context, user, repo string) ([]*sourceRef, bool, error) {
+	if gs.authenticated() {
+		return gs.graphQLRefs(ctx, user, repo, "refs/heads/")
+	}
+
+	branches, resp, err := gs.Client.Repositories.ListBranches(ctx, user, repo, &github.ListOptions{
+		PerPage: maxRefs,
+	})
+	if err != nil {
+		return nil, false, gs.convertError(err)
+	}
+
+	gs.logRate(resp)
+
+	refs := make([]*sourceRef, len(branches))
+
+	for i, branch := range branches {
+		refs[i] = &sourceRef{
+			Name: branch.GetName(),
+			SHA:  branch.GetCommit().GetSHA(),
+		}
+	}
+
+	return refs, resp.NextPage != 0, nil
+}
+
+func (gs *githubSource) ListTags(ctx context.Context, user, repo string) ([]*sourceRef, bool, error) {
+	if gs.authenticated() {
+		return gs.graphQLRefs(ctx, user, repo, "refs/tags/")
+	}
+
+	tags, resp, err := gs.Client.Repositories.ListTags(ctx, user, repo, &github.ListOptions{
+		PerPage: maxRefs,
+	})
+	if err != nil {
+		return nil, false, gs.convertError(err)
+	}
+
+	gs.logRate(resp)
+
+	refs := make([]*sourceRef, len(tags))
+
+	for i, tag := range tags {
+		refs[i] = &sourceRef{
+			Name: tag.GetName(),
+			SHA:  tag.GetCommit().GetSHA(),
+		}
+	}
+
+	return refs, resp.NextPage != 0, nil
+}
+
+func (gs *githubSource) ResolveCommit(ctx context.Context, user, repo, ref string) (string, error) {
+	sha, resp, err := gs.Client.Repositories.GetCommitSHA1(ctx, user, repo, ref, "")
+	if err != nil {
+		return "", gs.convertError(err)
+	}
+
+	gs.logRate(resp)
+
+	return sha, nil
+}
+
+func (gs *githubSource) Archive(ctx context.Context, user, repo, commit string) (io.ReadCloser, error) {
+	u, resp, err := gs.Client.Repositories.GetArchiveLink(ctx, user, repo, github.Tarball, &github.RepositoryContentGetOptions{
+		Ref: commit,
+	})
+	if err != nil {
+		return nil, gs.convertError(err)
+	}
+
+	gs.logRate(resp)
+
+	if u == nil {
+		return nil, &sourceNotFoundError{fmt.Errorf("no archive found for %s/%s@%s", user, repo, commit)}
+	}
+
+	client := gs.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req := &http.Request{
+		URL:  u,
+		Host: u.Host,
+		Header: http.Header{
+			"User-Agent": []string{fullVersionStr},
+		},
+	}
+
+	hresp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if hresp.StatusCode != http.StatusOK {
+		hresp.Body.Close()
+		return nil, fmt.Errorf("tarball download failed with status %s", hresp.Status)
+	}
+
+	return hresp.Body, nil
+}
+
+func (gs *githubSource) UserURL(user string) string {
+	return gs.webURL() + "/" + url.QueryEscape(user)
+}
+
+func (gs *githubSource) RepoURL(user, repo string) string {
+	return gs.UserURL(user) + "/" + url.QueryEscape(repo)
+}
+
+func (gs *githubSource) TreeURL(user, repo, tree string) string {
+	return gs.RepoURL(user, repo) + "/tree/" + tree
+}
+
+func (gs *githubSource) CloneURL(user, repo string) string {
+	return gs.RepoURL(user, repo) + ".git"
+}
+
+// CloneAuthorization authenticates with the token, or
+// installation token, as the password of x-access-token.
+func (gs *githubSource) CloneAuthorization() (string, error) {
+	if gs.Credentials == nil {
+		return "", nil
+	}
+
+	auth, err := gs.Credentials.Authorization()
+	if err != nil {
+		return "", err
+	}
+
+	token := strings.TrimPrefix(auth, "token ")
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte("x-access-token:"+token)), nil
+}
+
+func (gs *githubSource) CommitURL(user, repo, commit string) string {
+	return gs.RepoURL(user, repo) + "/commit/" + url.QueryEscape(commit)
+}
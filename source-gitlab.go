@@ -0,0 +1,279 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gitlabSource is a GitLab instance accessed through
+// version 4 of its API.
+type gitlabSource struct {
+	name  string
+	web   *url.URL
+	token string
+
+	API sourceAPI
+}
+
+func newGitlabSource(name string, web *url.URL, token string) *gitlabSource {
+	web.Path = strings.TrimSuffix(web.Path, "/")
+
+	gs := &gitlabSource{
+		name: name,
+		web:  web,
+
+		API: sourceAPI{
+			Base: web.String() + "/api/v4",
+		},
+	}
+
+	if len(token) != 0 {
+		gs.token = token
+		gs.API.Header = http.Header{
+			"Private-Token": {token},
+		}
+	}
+
+	return gs
+}
+
+func (gs *gitlabSource) Name() string {
+	return gs.name
+}
+
+func (*gitlabSource) Title() string {
+	return "GitLab"
+}
+
+func (gs *gitlabSource) WebHost() string {
+	return gs.web.Host
+}
+
+func gitlabProject(user, repo string) string {
+	return "/projects/" + url.PathEscape(user+"/"+repo)
+}
+
+func gitlabPages(resp *http.Response) *sourcePage {
+	prev, _ := strconv.Atoi(resp.Header.Get("X-Prev-Page"))
+	next, _ := strconv.Atoi(resp.Header.Get("X-Next-Page"))
+	return &sourcePage{prev, next}
+}
+
+type gitlabCommit struct {
+	ID         string   `json:"id"`
+	Message    string   `json:"message"`
+	AuthorName string   `json:"author_name"`
+	ParentIDs  []string `json:"parent_ids"`
+}
+
+func (gs *gitlabSource) convertCommit(user, repo string, commit *gitlabCommit) *sourceCommit {
+	return &sourceCommit{
+		SHA:     commit.ID,
+		Message: commit.Message,
+		HTMLURL: gs.CommitURL(user, repo, commit.ID),
+
+		AuthorName: commit.AuthorName,
+
+		Parents: commit.ParentIDs,
+	}
+}
+
+func (gs *gitlabSource) ListRepos(ctx context.Context, user string, page int) ([]*sourceRepo, *sourcePage, error) {
+	var projects []struct {
+		Path        string `json:"path"`
+		Description string `json:"description"`
+		WebURL      string `json:"web_url"`
+	}
+
+	query := pageQuery(page, 50)
+	query.Set("order_by", "last_activity_at")
+
+	// user is either a user or a group namespace
+	resp, err := gs.API.getJSON(ctx, "/users/"+url.PathEscape(user)+"/projects", query, &projects)
+	if isSourceNotFound(err) {
+		resp, err = gs.API.getJSON(ctx, "/groups/"+url.PathEscape(user)+"/projects", query, &projects)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repos := make([]*sourceRepo, len(projects))
+
+	for i, project := range projects {
+		repos[i] = &sourceRepo{
+			Name:        project.Path,
+			Description: project.Description,
+			HTMLURL:     project.WebURL,
+		}
+	}
+
+	return repos, gitlabPages(resp), nil
+}
+
+func (gs *gitlabSource) ListCommits(ctx context.Context, user, repo, ref string, page int) ([]*sourceCommit, *sourcePage, error) {
+	query := pageQuery(page, 50)
+	if len(ref) != 0 {
+		query.Set("ref_name", ref)
+	}
+
+	var commits []*gitlabCommit
+
+	resp, err := gs.API.getJSON(ctx, gitlabProject(user, repo)+"/repository/commits", query, &commits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scommits := make([]*sourceCommit, len(commits))
+
+	for i, commit := range commits {
+		scommits[i] = gs.convertCommit(user, repo, commit)
+	}
+
+	return scommits, gitlabPages(resp), nil
+}
+
+func (gs *gitlabSource) GetCommit(ctx context.Context, user, repo, commit string) (*sourceCommit, error) {
+	commitPath := gitlabProject(user, repo) + "/repository/commits/" + url.PathEscape(commit)
+
+	var gcommit gitlabCommit
+
+	if _, err := gs.API.getJSON(ctx, commitPath, nil, &gcommit); err != nil {
+		return nil, err
+	}
+
+	var diffs []struct {
+		OldPath     string `json:"old_path"`
+		NewPath     string `json:"new_path"`
+		Diff        string `json:"diff"`
+		NewFile     bool   `json:"new_file"`
+		RenamedFile bool   `json:"renamed_file"`
+		DeletedFile bool   `json:"deleted_file"`
+	}
+
+	if _, err := gs.API.getJSON(ctx, commitPath+"/diff", nil, &diffs); err != nil {
+		return nil, err
+	}
+
+	scommit := gs.convertCommit(user, repo, &gcommit)
+
+	for _, diff := range diffs {
+		status := "modified"
+
+		switch {
+		case diff.NewFile:
+			status = "added"
+		case diff.DeletedFile:
+			status = "removed"
+		case diff.RenamedFile:
+			status = "renamed"
+		}
+
+		scommit.Files = append(scommit.Files, sourceFile{
+			Filename: diff.NewPath,
+			Status:   status,
+			Patch:    diff.Diff,
+		})
+	}
+
+	return scommit, nil
+}
+
+func (gs *gitlabSource) listRefs(ctx context.Context, user, repo, kind string) ([]*sourceRef, bool, error) {
+	var refs []struct {
+		Name   string `json:"name"`
+		Commit struct {
+			ID            string    `json:"id"`
+			CommittedDate time.Time `json:"committed_date"`
+		} `json:"commit"`
+	}
+
+	// newest first, so that any that are cut off are the
+	// oldest
+	query := pageQuery(1, maxRefs)
+	query.Set("order_by", "updated")
+	query.Set("sort", "desc")
+
+	resp, err := gs.API.getJSON(ctx, gitlabProject(user, repo)+"/repository/"+kind, query, &refs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	srefs := make([]*sourceRef, len(refs))
+
+	for i, ref := range refs {
+		srefs[i] = &sourceRef{ref.Name, ref.Commit.ID, ref.Commit.CommittedDate}
+	}
+
+	return srefs, linkPages(resp).NextPage != 0, nil
+}
+
+func (gs *gitlabSource) ListBranches(ctx context.Context, user, repo string) ([]*sourceRef, bool, error) {
+	return gs.listRefs(ctx, user, repo, "branches")
+}
+
+func (gs *gitlabSource) ListTags(ctx context.Context, user, repo string) ([]*sourceRef, bool, error) {
+	return gs.listRefs(ctx, user, repo, "tags")
+}
+
+func (gs *gitlabSource) ResolveCommit(ctx context.Context, user, repo, ref string) (string, error) {
+	var commit gitlabCommit
+
+	if _, err := gs.API.getJSON(ctx, gitlabProject(user, repo)+"/repository/commits/"+url.PathEscape(ref), nil, &commit); err != nil {
+		return "", err
+	}
+
+	return commit.ID, nil
+}
+
+func (gs *gitlabSource) Archive(ctx context.Context, user, repo, commit string) (io.ReadCloser, error) {
+	resp, err := gs.API.get(ctx, gitlabProject(user, repo)+"/repository/archive.tar.gz", url.Values{
+		"sha": {commit},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+func (gs *gitlabSource) UserURL(user string) string {
+	return gs.web.String() + "/" + url.PathEscape(user)
+}
+
+func (gs *gitlabSource) RepoURL(user, repo string) string {
+	return gs.UserURL(user) + "/" + url.PathEscape(repo)
+}
+
+func (gs *gitlabSource) TreeURL(user, repo, tree string) string {
+	return gs.RepoURL(user, repo) + "/tree/" + tree
+}
+
+func (gs *gitlabSource) CloneURL(user, repo string) string {
+	return gs.RepoURL(user, repo) + ".git"
+}
+
+// CloneAuthorization authenticates with the token as the
+// password of the oauth2 user.
+func (gs *gitlabSource) CloneAuthorization() (string, error) {
+	if len(gs.token) == 0 {
+		return "", nil
+	}
+
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte("oauth2:"+gs.token)), nil
+}
+
+func (gs *gitlabSource) CommitURL(user, repo, commit string) string {
+	return gs.RepoURL(user, repo) + "/commit/" + url.PathEscape(commit)
+}
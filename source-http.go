@@ -0,0 +1,140 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// sourceAPI is a minimal client for the JSON APIs of
+// GitLab and Gitea.
+type sourceAPI struct {
+	Client *http.Client
+
+	// Base is the URL of the API root, without a
+	// trailing slash.
+	Base string
+
+	Header http.Header
+}
+
+// get requests path, which must already be escaped, and
+// returns the response if it was successful.
+func (api *sourceAPI) get(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	u := api.Base + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range api.Header {
+		req.Header[k] = v
+	}
+
+	req.Header.Set("User-Agent", fullVersionStr)
+
+	client := api.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+	resp.Body.Close()
+
+	err = fmt.Errorf("GET %s returned %s", path, resp.Status)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &sourceNotFoundError{err}
+	}
+
+	return nil, err
+}
+
+func (api *sourceAPI) getJSON(ctx context.Context, path string, query url.Values, v interface{}) (*http.Response, error) {
+	resp, err := api.get(ctx, path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// linkPages returns the adjacent pages from the Link header
+// of resp.
+func linkPages(resp *http.Response) *sourcePage {
+	page := new(sourcePage)
+
+	for _, link := range strings.Split(resp.Header.Get("Link"), ",") {
+		segments := strings.Split(strings.TrimSpace(link), ";")
+		if len(segments) < 2 {
+			continue
+		}
+
+		target := strings.TrimSpace(segments[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+
+		u, err := url.Parse(target[1 : len(target)-1])
+		if err != nil {
+			continue
+		}
+
+		n, err := strconv.Atoi(u.Query().Get("page"))
+		if err != nil {
+			continue
+		}
+
+		for _, rel := range segments[1:] {
+			switch strings.TrimSpace(rel) {
+			case `rel="prev"`:
+				page.PrevPage = n
+			case `rel="next"`:
+				page.NextPage = n
+			}
+		}
+	}
+
+	return page
+}
+
+func pageQuery(page, perPage int) url.Values {
+	if page <= 0 {
+		page = 1
+	}
+
+	return url.Values{
+		"page":     {strconv.Itoa(page)},
+		"per_page": {strconv.Itoa(perPage)},
+	}
+}
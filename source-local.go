@@ -0,0 +1,400 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const localPerPage = 50
+
+// localSource serves bare git repositories stored on the
+// local filesystem as <Root>/<user>/<repo>.git.
+type localSource struct {
+	name string
+
+	Root string
+}
+
+func newLocalSource(name, root string) (*localSource, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localSource{
+		name: name,
+
+		Root: root,
+	}, nil
+}
+
+func (ls *localSource) Name() string {
+	return ls.name
+}
+
+func (*localSource) Title() string {
+	return "git"
+}
+
+func (*localSource) WebHost() string {
+	return ""
+}
+
+func validLocalName(name string) bool {
+	return len(name) != 0 && name[0] != '.' && !strings.ContainsAny(name, "/\\\x00")
+}
+
+// repoDir returns the path of the bare repository for
+// user and repo.
+func (ls *localSource) repoDir(user, repo string) (string, error) {
+	if !validLocalName(user) || !validLocalName(repo) {
+		return "", &sourceNotFoundError{errors.New("invalid repository name")}
+	}
+
+	for _, name := range [...]string{repo + ".git", repo} {
+		dir := filepath.Join(ls.Root, user, name)
+
+		if stat, err := os.Stat(filepath.Join(dir, "HEAD")); err == nil && stat.Mode().IsRegular() {
+			return dir, nil
+		}
+	}
+
+	return "", &sourceNotFoundError{fmt.Errorf("repository %s/%s not found", user, repo)}
+}
+
+func localPage(page, n int) *sourcePage {
+	p := new(sourcePage)
+
+	if page > 1 {
+		p.PrevPage = page - 1
+	}
+
+	if n > page*localPerPage {
+		p.NextPage = page + 1
+	}
+
+	return p
+}
+
+func (ls *localSource) ListRepos(ctx context.Context, user string, page int) ([]*sourceRepo, *sourcePage, error) {
+	if !validLocalName(user) {
+		return nil, nil, &sourceNotFoundError{errors.New("invalid user name")}
+	}
+
+	infos, err := ioutil.ReadDir(filepath.Join(ls.Root, user))
+	if os.IsNotExist(err) {
+		return nil, nil, &sourceNotFoundError{err}
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	var names []string
+
+	for _, info := range infos {
+		if name := strings.TrimSuffix(info.Name(), ".git"); info.IsDir() && validLocalName(name) {
+			if _, err := ls.repoDir(user, name); err == nil {
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	if page <= 0 {
+		page = 1
+	}
+
+	var repos []*sourceRepo
+
+	for i := (page - 1) * localPerPage; i < len(names) && i < page*localPerPage; i++ {
+		repo := &sourceRepo{
+			Name: names[i],
+		}
+
+		dir, _ := ls.repoDir(user, names[i])
+		if desc, err := ioutil.ReadFile(filepath.Join(dir, "description")); err == nil && !bytes.HasPrefix(desc, []byte("Unnamed repository;")) {
+			repo.Description = strings.TrimSpace(string(desc))
+		}
+
+		repos = append(repos, repo)
+	}
+
+	return repos, localPage(page, len(names)), nil
+}
+
+// localCommitFormat separates the fields of each commit with
+// NUL and each commit with a record separator.
+const localCommitFormat = "--format=%H%x00%an%x00%P%x00%B%x1e"
+
+func parseLocalCommits(log string) []*sourceCommit {
+	var commits []*sourceCommit
+
+	for _, record := range strings.Split(log, "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x00", 4)
+		if len(fields) != 4 {
+			continue
+		}
+
+		commits = append(commits, &sourceCommit{
+			SHA:     fields[0],
+			Message: strings.TrimSpace(fields[3]),
+
+			AuthorName: fields[1],
+
+			Parents: strings.Fields(fields[2]),
+		})
+	}
+
+	return commits
+}
+
+func (ls *localSource) resolve(ctx context.Context, dir, ref string) (string, error) {
+	if len(ref) == 0 {
+		ref = "HEAD"
+	}
+
+	if strings.HasPrefix(ref, "-") {
+		return "", &sourceNotFoundError{fmt.Errorf("invalid ref '%s'", ref)}
+	}
+
+	sha, err := gitOutput(ctx, dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", &sourceNotFoundError{err}
+	}
+
+	return strings.TrimSpace(sha), nil
+}
+
+func (ls *localSource) ListCommits(ctx context.Context, user, repo, ref string, page int) ([]*sourceCommit, *sourcePage, error) {
+	dir, err := ls.repoDir(user, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sha, err := ls.resolve(ctx, dir, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+
+	skip := (page - 1) * localPerPage
+
+	log, err := gitOutput(ctx, dir, "log", localCommitFormat, "--skip="+strconv.Itoa(skip), "--max-count="+strconv.Itoa(localPerPage+1), sha, "--")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	commits := parseLocalCommits(log)
+	pages := localPage(page, skip+len(commits))
+
+	if len(commits) > localPerPage {
+		commits = commits[:localPerPage]
+	}
+
+	return commits, pages, nil
+}
+
+func (ls *localSource) GetCommit(ctx context.Context, user, repo, commit string) (*sourceCommit, error) {
+	dir, err := ls.repoDir(user, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	sha, err := ls.resolve(ctx, dir, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	log, err := gitOutput(ctx, dir, "log", localCommitFormat, "--max-count=1", sha, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	commits := parseLocalCommits(log)
+	if len(commits) != 1 {
+		return nil, fmt.Errorf("failed to parse commit %s", sha)
+	}
+
+	names, err := gitOutput(ctx, dir, "diff-tree", "-r", "--root", "--no-commit-id", "--name-status", sha)
+	if err != nil {
+		return nil, err
+	}
+
+	patches, err := gitOutput(ctx, dir, "diff-tree", "-r", "--root", "--no-commit-id", "--patch", sha)
+	if err != nil {
+		return nil, err
+	}
+
+	// both listings are in the same order
+	diffs := strings.Split(patches, "diff --git ")[1:]
+
+	for i, line := range strings.Split(strings.TrimSpace(names), "\n") {
+		fields := strings.SplitN(line, "\t", 2)
+		if len(fields) != 2 {
+			continue
+		}
+
+		file := sourceFile{
+			Filename: fields[1],
+		}
+
+		switch fields[0] {
+		case "A":
+			file.Status = "added"
+		case "D":
+			file.Status = "removed"
+		default:
+			file.Status = "modified"
+		}
+
+		if i < len(diffs) {
+			if idx := strings.Index(diffs[i], "\n@@"); idx != -1 {
+				file.Patch = strings.TrimSuffix(diffs[i][idx+1:], "\n")
+			}
+		}
+
+		commits[0].Files = append(commits[0].Files, file)
+	}
+
+	return commits[0], nil
+}
+
+func (ls *localSource) ResolveCommit(ctx context.Context, user, repo, ref string) (string, error) {
+	dir, err := ls.repoDir(user, repo)
+	if err != nil {
+		return "", err
+	}
+
+	return ls.resolve(ctx, dir, ref)
+}
+
+func (ls *localSource) listRefs(ctx context.Context, user, repo, kind string) ([]*sourceRef, bool, error) {
+	dir, err := ls.repoDir(user, repo)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// annotated tags are peeled to the commit they point to,
+	// and one more is listed to know if there are more
+	out, err := gitOutput(ctx, dir, "for-each-ref", "--count="+strconv.Itoa(maxRefs+1), "--sort=-creatordate",
+		"--format=%(refname:strip=2)%00%(objectname)%00%(*objectname)%00%(creatordate:unix)", "refs/"+kind+"/")
+	if err != nil {
+		return nil, false, err
+	}
+
+	var refs []*sourceRef
+
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 4 {
+			continue
+		}
+
+		ref := &sourceRef{
+			Name: fields[0],
+			SHA:  fields[1],
+		}
+
+		if len(fields[2]) != 0 {
+			ref.SHA = fields[2]
+		}
+
+		if date, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+			ref.Date = time.Unix(date, 0)
+		}
+
+		refs = append(refs, ref)
+	}
+
+	if len(refs) > maxRefs {
+		return refs[:maxRefs], true, nil
+	}
+
+	return refs, false, nil
+}
+
+func (ls *localSource) ListBranches(ctx context.Context, user, repo string) ([]*sourceRef, bool, error) {
+	return ls.listRefs(ctx, user, repo, "heads")
+}
+
+func (ls *localSource) ListTags(ctx context.Context, user, repo string) ([]*sourceRef, bool, error) {
+	return ls.listRefs(ctx, user, repo, "tags")
+}
+
+func (ls *localSource) Archive(ctx context.Context, user, repo, commit string) (io.ReadCloser, error) {
+	dir, err := ls.repoDir(user, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	sha, err := ls.resolve(ctx, dir, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+
+	cmd := gitCommand(ctx, dir, "archive", "--format=tar", "--prefix="+repo+"/", sha)
+
+	go func() {
+		gzw := gzip.NewWriter(pw)
+		cmd.Stdout = gzw
+
+		err := cmd.Run()
+		if closeErr := gzw.Close(); err == nil {
+			err = closeErr
+		}
+
+		pw.CloseWithError(err)
+	}()
+
+	return pr, nil
+}
+
+func (*localSource) CloneAuthorization() (string, error) {
+	return "", nil
+}
+
+func (ls *localSource) CloneURL(user, repo string) string {
+	dir, err := ls.repoDir(user, repo)
+	if err != nil {
+		return ""
+	}
+
+	return dir
+}
+
+func (*localSource) UserURL(user string) string {
+	return ""
+}
+
+func (*localSource) RepoURL(user, repo string) string {
+	return ""
+}
+
+func (*localSource) TreeURL(user, repo, tree string) string {
+	return ""
+}
+
+func (*localSource) CommitURL(user, repo, commit string) string {
+	return ""
+}
@@ -0,0 +1,271 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// defaultSourceName is the source provider used by routes
+// that do not name one.
+const defaultSourceName = "github"
+
+// maxRefs is the most branches or tags that are listed.
+const maxRefs = 100
+
+// sourceProvider is a git hosting service that sites are
+// built from.
+type sourceProvider interface {
+	// Name is the short name used in routes and build keys.
+	Name() string
+	// Title is the human readable name of the service.
+	Title() string
+	// WebHost is the host of the web interface, used to
+	// recognise URLs passed to /goto/. It is empty if the
+	// source has no web interface.
+	WebHost() string
+
+	ListRepos(ctx context.Context, user string, page int) ([]*sourceRepo, *sourcePage, error)
+	ListCommits(ctx context.Context, user, repo, ref string, page int) ([]*sourceCommit, *sourcePage, error)
+	GetCommit(ctx context.Context, user, repo, commit string) (*sourceCommit, error)
+
+	// ResolveCommit returns the full commit SHA for a
+	// branch, tag or abbreviated commit.
+	ResolveCommit(ctx context.Context, user, repo, ref string) (string, error)
+
+	// Archive returns a gzipped tarball of the repository
+	// at commit. Each entry is within a single top-level
+	// directory.
+	Archive(ctx context.Context, user, repo, commit string) (io.ReadCloser, error)
+
+	// The web interface URLs are empty if the source has
+	// no web interface.
+	UserURL(user string) string
+	RepoURL(user, repo string) string
+	TreeURL(user, repo, tree string) string
+	CommitURL(user, repo, commit string) string
+}
+
+// pullRequestSource is implemented by source providers
+// that have pull requests.
+type pullRequestSource interface {
+	// ListPulls lists the open pull requests of repo.
+	ListPulls(ctx context.Context, user, repo string, page int) ([]*sourcePull, *sourcePage, error)
+	GetPull(ctx context.Context, user, repo string, number int) (*sourcePull, error)
+}
+
+// refSource is implemented by source providers that can
+// list branches and tags. Up to maxRefs are returned in no
+// particular order, along with whether there were more.
+type refSource interface {
+	ListBranches(ctx context.Context, user, repo string) ([]*sourceRef, bool, error)
+	ListTags(ctx context.Context, user, repo string) ([]*sourceRef, bool, error)
+}
+
+type sourceRepo struct {
+	Name        string
+	Description string
+	HTMLURL     string
+}
+
+type sourceCommit struct {
+	SHA     string
+	Message string
+	HTMLURL string
+
+	AuthorName string
+	AuthorURL  string
+
+	Parents []string
+	Files   []sourceFile
+}
+
+// sourceRef is a branch or tag. SHA is the commit it
+// points to and Date is when that commit was made.
+type sourceRef struct {
+	Name string
+	SHA  string
+	Date time.Time
+}
+
+// sortRefs sorts refs by the date of their commit, most
+// recent first.
+func sortRefs(refs []*sourceRef) {
+	sort.SliceStable(refs, func(i, j int) bool {
+		if !refs[i].Date.Equal(refs[j].Date) {
+			return refs[i].Date.After(refs[j].Date)
+		}
+
+		return refs[i].Name < refs[j].Name
+	})
+}
+
+// sourcePull is a pull request.
+type sourcePull struct {
+	Number  int
+	Title   string
+	HTMLURL string
+
+	AuthorName string
+	AuthorURL  string
+
+	HeadUser string
+	HeadRepo string
+	HeadRef  string
+	HeadSHA  string
+}
+
+type sourceFile struct {
+	Filename string
+	Status   string
+	Patch    string
+}
+
+// sourcePage holds the adjacent page numbers of a listing.
+// Zero means there is no such page.
+type sourcePage struct {
+	PrevPage int
+	NextPage int
+}
+
+// sourceNotFoundError is returned by a sourceProvider when
+// the user, repository or commit does not exist.
+type sourceNotFoundError struct {
+	Err error
+}
+
+func (e *sourceNotFoundError) Error() string {
+	return e.Err.Error()
+}
+
+func isSourceNotFound(err error) bool {
+	_, ok := err.(*sourceNotFoundError)
+	return ok
+}
+
+// sourceErrorCode returns the HTTP status code that should be
+// sent to the client for an error returned from a sourceProvider.
+func sourceErrorCode(err error) int {
+	if isSourceNotFound(err) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusBadGateway
+}
+
+// sourceRegistry maps the name of each source provider to
+// the provider.
+type sourceRegistry map[string]sourceProvider
+
+// fromParams returns the source provider named by the
+// provider route parameter, or the default provider if
+// there is none.
+func (sr sourceRegistry) fromParams(ps httprouter.Params) (sourceProvider, bool) {
+	name := ps.ByName("provider")
+	if len(name) == 0 {
+		name = defaultSourceName
+	}
+
+	source, ok := sr[name]
+	return source, ok
+}
+
+func getSources(optsflag string, github *githubSource) (sourceRegistry, error) {
+	sources := sourceRegistry{
+		defaultSourceName: github,
+	}
+
+	if len(optsflag) == 0 {
+		return sources, nil
+	}
+
+	var opts []struct {
+		Type  string
+		Name  string
+		URL   string
+		Token string
+		Path  string
+	}
+
+	if err := json.Unmarshal([]byte(optsflag), &opts); err != nil {
+		return nil, err
+	}
+
+	for _, opt := range opts {
+		if len(opt.Name) == 0 {
+			opt.Name = opt.Type
+		}
+
+		if _, dup := sources[opt.Name]; dup {
+			return nil, fmt.Errorf("duplicate source named '%s'", opt.Name)
+		}
+
+		if opt.Type == "local" {
+			if len(opt.Path) == 0 {
+				return nil, fmt.Errorf("source '%s' requires a path", opt.Name)
+			}
+
+			source, err := newLocalSource(opt.Name, opt.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			sources[opt.Name] = source
+			continue
+		}
+
+		base, err := url.Parse(opt.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(base.Scheme) == 0 || len(base.Host) == 0 {
+			return nil, fmt.Errorf("source '%s' requires an absolute URL", opt.Name)
+		}
+
+		switch opt.Type {
+		case "gitlab":
+			sources[opt.Name] = newGitlabSource(opt.Name, base, opt.Token)
+		case "gitea":
+			sources[opt.Name] = newGiteaSource(opt.Name, base, opt.Token)
+		default:
+			return nil, fmt.Errorf("invalid source type '%s'", opt.Type)
+		}
+	}
+
+	return sources, nil
+}
+
+// sourcePrefix returns the path prefix of the routes for
+// the named source provider.
+func sourcePrefix(name string) string {
+	if name == defaultSourceName {
+		return ""
+	}
+
+	return "/p/" + url.QueryEscape(name)
+}
+
+func userPath(source, user string) string {
+	return sourcePrefix(source) + "/u/" + url.QueryEscape(user) + "/"
+}
+
+func repoPath(source, user, repo string) string {
+	return userPath(source, user) + "r/" + url.QueryEscape(repo) + "/"
+}
+
+func commitPath(source, user, repo, commit string) string {
+	return repoPath(source, user, repo) + "c/" + url.QueryEscape(commit) + "/"
+}
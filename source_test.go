@@ -0,0 +1,357 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+const testSHA = "e83c5163316f89bfbde7d9ab23ca2e25604af290"
+
+func newTestSourceServer(t *testing.T, routes map[string]string, header http.Header) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.EscapedPath()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		for k, v := range header {
+			if r.Header.Get(k) != v[0] {
+				t.Errorf("%s did not send %s header", r.URL, k)
+			}
+		}
+
+		w.Header().Set("X-Next-Page", "2")
+		w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestGitlabSource(t *testing.T) {
+	ts := newTestSourceServer(t, map[string]string{
+		"/api/v4/projects/user%2Frepo/repository/commits": `[{"id":"` + testSHA + `","message":"msg","author_name":"author","parent_ids":[]}]`,
+
+		"/api/v4/projects/user%2Frepo/repository/commits/master":               `{"id":"` + testSHA + `"}`,
+		"/api/v4/projects/user%2Frepo/repository/commits/" + testSHA:           `{"id":"` + testSHA + `","message":"msg","author_name":"author","parent_ids":["` + testSHA + `"]}`,
+		"/api/v4/projects/user%2Frepo/repository/commits/" + testSHA + "/diff": `[{"new_path":"a.md","diff":"@@","new_file":true}]`,
+
+		"/api/v4/projects/user%2Frepo/repository/archive.tar.gz": "archive",
+	}, http.Header{"Private-Token": {"token"}})
+	defer ts.Close()
+
+	base, _ := url.Parse(ts.URL)
+	gs := newGitlabSource("gitlab", base, "token")
+
+	testSource(t, gs)
+
+	commit, err := gs.GetCommit(context.Background(), "user", "repo", testSHA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(commit.Files) != 1 || commit.Files[0].Filename != "a.md" || commit.Files[0].Status != "added" || commit.Files[0].Patch != "@@" {
+		t.Errorf("GetCommit returned wrong files %+v", commit.Files)
+	}
+}
+
+func TestGiteaSource(t *testing.T) {
+	commit := `{"sha":"` + testSHA + `","commit":{"message":"msg","author":{"name":"author"}},"parents":[{"sha":"` + testSHA + `"}]}`
+
+	ts := newTestSourceServer(t, map[string]string{
+		"/api/v1/repos/user/repo/commits":                        `[` + commit + `]`,
+		"/api/v1/repos/user/repo/git/commits/" + testSHA:         commit,
+		"/api/v1/repos/user/repo/archive/" + testSHA + ".tar.gz": "archive",
+	}, http.Header{"Authorization": {"token token"}})
+	defer ts.Close()
+
+	base, _ := url.Parse(ts.URL)
+	testSource(t, newGiteaSource("gitea", base, "token"))
+}
+
+func testSource(t *testing.T, source sourceProvider) {
+	ctx := context.Background()
+
+	commits, page, err := source.ListCommits(ctx, "user", "repo", "master", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(commits) != 1 || commits[0].SHA != testSHA || commits[0].Message != "msg" || commits[0].AuthorName != "author" {
+		t.Errorf("ListCommits returned wrong commits %+v", commits)
+	}
+
+	if page.PrevPage != 0 || page.NextPage != 2 {
+		t.Errorf("ListCommits returned wrong page %+v", page)
+	}
+
+	sha, err := source.ResolveCommit(ctx, "user", "repo", "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sha != testSHA {
+		t.Errorf("ResolveCommit returned wrong SHA, expected %s, got %s", testSHA, sha)
+	}
+
+	commit, err := source.GetCommit(ctx, "user", "repo", testSHA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if commit.SHA != testSHA || len(commit.Parents) != 1 || commit.Parents[0] != testSHA {
+		t.Errorf("GetCommit returned wrong commit %+v", commit)
+	}
+
+	archive, err := source.Archive(ctx, "user", "repo", testSHA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadAll(archive)
+	archive.Close()
+
+	if err != nil {
+		t.Error(err)
+	} else if string(data) != "archive" {
+		t.Errorf("Archive returned wrong body %q", data)
+	}
+
+	if _, err := source.GetCommit(ctx, "user", "missing", testSHA); !isSourceNotFound(err) {
+		t.Errorf("GetCommit of missing repo returned %v, expected not found", err)
+	}
+}
+
+func TestGithubSourcePulls(t *testing.T) {
+	fork := `{"number":2,"title":"Fork","user":{"login":"other"},"head":{"ref":"patch-1","sha":"` + testSHA + `","repo":{"name":"fork","owner":{"login":"other"}}},"base":{"repo":{"name":"repo","owner":{"login":"user"}}}}`
+	deleted := `{"number":1,"title":"Deleted","user":{"login":"gone"},"head":{"ref":"patch-2","sha":"` + testSHA + `","repo":null},"base":{"repo":{"name":"repo","owner":{"login":"user"}}}}`
+
+	ts := newTestSourceServer(t, map[string]string{
+		"/repos/user/repo/pulls":   `[` + fork + `,` + deleted + `]`,
+		"/repos/user/repo/pulls/2": fork,
+	}, nil)
+	defer ts.Close()
+
+	gs := &githubSource{
+		Client: github.NewClient(nil),
+	}
+	gs.Client.BaseURL, _ = url.Parse(ts.URL + "/")
+
+	ctx := context.Background()
+
+	pulls, page, err := gs.ListPulls(ctx, "user", "repo", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pulls) != 2 || page.NextPage != 2 {
+		t.Fatalf("ListPulls returned %d pulls and page %+v", len(pulls), page)
+	}
+
+	for _, test := range []struct {
+		pull *sourcePull
+
+		number             int
+		headUser, headRepo string
+	}{
+		{pulls[0], 2, "other", "fork"},
+		{pulls[1], 1, "user", "repo"},
+	} {
+		if test.pull.Number != test.number || test.pull.HeadUser != test.headUser || test.pull.HeadRepo != test.headRepo || test.pull.HeadSHA != testSHA {
+			t.Errorf("ListPulls returned wrong pull %+v", test.pull)
+		}
+	}
+
+	pull, err := gs.GetPull(ctx, "user", "repo", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pull.Title != "Fork" || pull.HeadRef != "patch-1" || pull.AuthorName != "other" {
+		t.Errorf("GetPull returned wrong pull %+v", pull)
+	}
+
+	if _, err := gs.GetPull(ctx, "user", "repo", 3); !isSourceNotFound(err) {
+		t.Errorf("GetPull of missing pull returned %v, expected not found", err)
+	}
+}
+
+func TestSortRefs(t *testing.T) {
+	now := time.Now()
+
+	refs := []*sourceRef{
+		{Name: "old", Date: now.Add(-time.Hour)},
+		{Name: "b", Date: now},
+		{Name: "unknown"},
+		{Name: "a", Date: now},
+	}
+
+	sortRefs(refs)
+
+	var names []string
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+
+	if expect := "a,b,old,unknown"; strings.Join(names, ",") != expect {
+		t.Errorf("sortRefs ordered %v, expected %s", names, expect)
+	}
+}
+
+func TestGithubSourceRefs(t *testing.T) {
+	const otherSHA = "0123456789abcdef0123456789abcdef01234567"
+
+	// commits are not fetched for their dates
+	ts := newTestSourceServer(t, map[string]string{
+		"/repos/user/repo/branches": `[{"name":"master","commit":{"sha":"` + testSHA + `"}},{"name":"gh-pages","commit":{"sha":"` + otherSHA + `"}}]`,
+		"/repos/user/repo/tags":     `[{"name":"v1","commit":{"sha":"` + testSHA + `"}}]`,
+	}, nil)
+	defer ts.Close()
+
+	gs := &githubSource{
+		Client: github.NewClient(nil),
+	}
+	gs.Client.BaseURL, _ = url.Parse(ts.URL + "/")
+
+	ctx := context.Background()
+
+	branches, more, err := gs.ListBranches(ctx, "user", "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sortRefs(branches)
+
+	if len(branches) != 2 || branches[0].Name != "gh-pages" || branches[0].SHA != otherSHA || !branches[1].Date.IsZero() {
+		t.Errorf("ListBranches returned wrong refs %+v %+v", branches[0], branches[1])
+	}
+
+	if !more {
+		t.Error("ListBranches did not report the next page")
+	}
+
+	tags, _, err := gs.ListTags(ctx, "user", "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tags) != 1 || tags[0].Name != "v1" || tags[0].SHA != testSHA {
+		t.Errorf("ListTags returned wrong refs %+v", tags)
+	}
+
+	if _, _, err := gs.ListTags(ctx, "user", "missing"); !isSourceNotFound(err) {
+		t.Errorf("ListTags of missing repo returned %v, expected not found", err)
+	}
+}
+
+func TestGithubSourceGraphQLRefs(t *testing.T) {
+	ts := newTestSourceServer(t, map[string]string{
+		"/api/graphql": `{"data":{"repository":{"refs":{"pageInfo":{"hasNextPage":true},"nodes":[` +
+			`{"name":"v1","target":{"oid":"` + testSHA + `","committedDate":"2016-01-02T00:00:00Z"}},` +
+			`{"name":"v2","target":{"oid":"0123456789abcdef0123456789abcdef01234567","target":{"oid":"` + testSHA + `","committedDate":"2016-03-04T00:00:00Z"}}}]}}}}`,
+	}, nil)
+	defer ts.Close()
+
+	// the GraphQL API is only used by authenticated clients
+	gs := &githubSource{
+		Client:     github.NewClient(nil),
+		HTTPClient: http.DefaultClient,
+	}
+	gs.Client.BaseURL, _ = url.Parse(ts.URL + "/api/v3/")
+
+	tags, more, err := gs.ListTags(context.Background(), "user", "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !more {
+		t.Error("ListTags did not report more tags")
+	}
+
+	if len(tags) != 2 || tags[0].Date.Format("2006-01-02") != "2016-01-02" || tags[1].SHA != testSHA || tags[1].Date.Format("2006-01-02") != "2016-03-04" {
+		t.Errorf("ListTags returned wrong refs %+v", tags)
+	}
+}
+
+func TestGitlabSourceRefsOrder(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query(); q.Get("order_by") != "updated" || q.Get("sort") != "desc" {
+			t.Errorf("%s was not ordered newest first", r.URL)
+		}
+
+		w.Write([]byte(`[{"name":"master","commit":{"id":"` + testSHA + `","committed_date":"2016-01-02T00:00:00Z"}}]`))
+	}))
+	defer ts.Close()
+
+	base, _ := url.Parse(ts.URL)
+	gs := newGitlabSource("gitlab", base, "")
+
+	for _, list := range []func(ctx context.Context, user, repo string) ([]*sourceRef, bool, error){
+		gs.ListBranches,
+		gs.ListTags,
+	} {
+		refs, more, err := list(context.Background(), "user", "repo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(refs) != 1 || more {
+			t.Errorf("listing refs returned %+v, %t", refs, more)
+		}
+	}
+}
+
+func TestGiteaSourceRefs(t *testing.T) {
+	const perPage = 60
+
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// the newest branches are on the last page
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		var branches []string
+
+		for i := (page - 1) * perPage; i < page*perPage; i++ {
+			date := start.Add(time.Duration(i) * time.Hour).Format(time.RFC3339)
+			branches = append(branches, fmt.Sprintf(`{"name":"b%d","commit":{"id":"%s","timestamp":"%s"}}`, i, testSHA, date))
+		}
+
+		if page == 1 {
+			w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+		}
+
+		w.Write([]byte("[" + strings.Join(branches, ",") + "]"))
+	}))
+	defer ts.Close()
+
+	base, _ := url.Parse(ts.URL)
+	gs := newGiteaSource("gitea", base, "")
+
+	refs, more, err := gs.ListBranches(context.Background(), "user", "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(refs) != maxRefs || !more {
+		t.Fatalf("ListBranches returned %d refs and more %t, expected %d and true", len(refs), more, maxRefs)
+	}
+
+	if newest := fmt.Sprintf("b%d", 2*perPage-1); refs[0].Name != newest {
+		t.Errorf("ListBranches listed %s first, expected %s", refs[0].Name, newest)
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// fileStorage stores objects on the local filesystem.
+//
+// The object data is stored under objects/ and the headers
+// passed to Put are stored as JSON under headers/.
+type fileStorage struct {
+	Root string
+}
+
+func getFileStorage(optsflag string) (storage, error) {
+	var opts struct {
+		Path string
+	}
+
+	if len(optsflag) != 0 {
+		if err := json.Unmarshal([]byte(optsflag), &opts); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(opts.Path) == 0 {
+		return nil, errors.New("file storage requires a path")
+	}
+
+	root, err := filepath.Abs(opts.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range [...]string{"objects", "headers"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	return fileStorage{root}, nil
+}
+
+func (s fileStorage) paths(op, name string) (objectPath, headerPath string, err error) {
+	if len(name) == 0 || name[0] == '/' || path.Clean(name) != name || strings.HasPrefix(name, "../") || name == ".." ||
+		filepath.Separator != '/' && strings.ContainsRune(name, filepath.Separator) || strings.Contains(name, "\x00") {
+		return "", "", &os.PathError{Op: op, Path: name, Err: errors.New("invalid object name")}
+	}
+
+	name = filepath.FromSlash(name)
+	return filepath.Join(s.Root, "objects", name), filepath.Join(s.Root, "headers", name), nil
+}
+
+func (s fileStorage) Put(name string, r io.Reader, size int64, header http.Header) error {
+	objectPath, headerPath, err := s.paths("put", name)
+	if err != nil {
+		return err
+	}
+
+	hdr, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(objectPath), 0755); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(headerPath), 0755); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(objectPath), ".put-")
+	if err != nil {
+		return err
+	}
+
+	n, err := copyBuffer(f, io.LimitReader(r, size))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil && n != size {
+		err = fmt.Errorf("short read of %d bytes, expected %d", n, size)
+	}
+
+	if err == nil {
+		err = ioutil.WriteFile(headerPath, hdr, 0644)
+	}
+
+	if err == nil {
+		err = os.Rename(f.Name(), objectPath)
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+	}
+
+	return err
+}
+
+func (s fileStorage) open(op, name string, withBody bool) (*storageObject, error) {
+	objectPath, headerPath, err := s.paths(op, name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(objectPath)
+	if os.IsNotExist(err) {
+		return nil, storageNotExist(op, name)
+	} else if err != nil {
+		return nil, err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if !stat.Mode().IsRegular() {
+		f.Close()
+		return nil, storageNotExist(op, name)
+	}
+
+	header := make(http.Header)
+
+	if hdr, err := ioutil.ReadFile(headerPath); err == nil {
+		if err = json.Unmarshal(hdr, &header); err != nil {
+			f.Close()
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		f.Close()
+		return nil, err
+	}
+
+	header.Set("Content-Length", strconv.FormatInt(stat.Size(), 10))
+	header.Set("Last-Modified", stat.ModTime().UTC().Format(http.TimeFormat))
+
+	if !withBody {
+		f.Close()
+		return &storageObject{Header: header}, nil
+	}
+
+	return &storageObject{
+		Header: header,
+		Body:   f,
+	}, nil
+}
+
+func (s fileStorage) Get(name string) (*storageObject, error) {
+	return s.open("get", name, true)
+}
+
+func (s fileStorage) GetRange(name string, start, length int64) (*storageObject, error) {
+	obj, err := s.open("get", name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	f := obj.Body.(*os.File)
+
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	obj.Header.Set("Content-Length", strconv.FormatInt(length, 10))
+	obj.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(f, length), f}
+	return obj, nil
+}
+
+func (s fileStorage) Head(name string) (*storageObject, error) {
+	return s.open("head", name, false)
+}
+
+func (s fileStorage) List(prefix string, max int) ([]string, error) {
+	objects := filepath.Join(s.Root, "objects")
+
+	dir := objects
+	if idx := strings.LastIndex(prefix, "/"); idx != -1 {
+		dir = filepath.Join(objects, filepath.FromSlash(path.Clean("/"+prefix[:idx])))
+	}
+
+	var names []string
+
+	errStop := errors.New("stop")
+
+	if err := filepath.Walk(dir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if filePath == objects {
+			return nil
+		}
+
+		name := filepath.ToSlash(filePath[len(objects)+1:])
+
+		if info.IsDir() {
+			if strings.HasPrefix(name+"/", prefix) || strings.HasPrefix(prefix, name+"/") {
+				return nil
+			}
+
+			return filepath.SkipDir
+		}
+
+		if !info.Mode().IsRegular() || strings.HasPrefix(info.Name(), ".put-") || !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+
+		names = append(names, name)
+
+		if max > 0 && len(names) >= max {
+			return errStop
+		}
+
+		return nil
+	}); err != nil && err != errStop {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+func (s fileStorage) Delete(name string) error {
+	objectPath, headerPath, err := s.paths("delete", name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(objectPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.Remove(headerPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
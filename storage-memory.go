@@ -0,0 +1,149 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryObject struct {
+	header  http.Header
+	data    []byte
+	modTime time.Time
+}
+
+type memoryStorage struct {
+	mu      sync.RWMutex
+	objects map[string]*memoryObject
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{
+		objects: make(map[string]*memoryObject),
+	}
+}
+
+func (s *memoryStorage) Put(name string, r io.Reader, size int64, header http.Header) error {
+	data, err := ioutil.ReadAll(io.LimitReader(r, size))
+	if err != nil {
+		return err
+	}
+
+	if int64(len(data)) != size {
+		return fmt.Errorf("short read of %d bytes, expected %d", len(data), size)
+	}
+
+	obj := &memoryObject{
+		header:  make(http.Header, len(header)),
+		data:    data,
+		modTime: time.Now(),
+	}
+
+	for k, v := range header {
+		if len(v) != 0 {
+			obj.header[k] = append([]string(nil), v...)
+		}
+	}
+
+	s.mu.Lock()
+	s.objects[name] = obj
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *memoryStorage) lookup(op, name string) (*memoryObject, *storageObject, error) {
+	s.mu.RLock()
+	obj, ok := s.objects[name]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, nil, storageNotExist(op, name)
+	}
+
+	header := make(http.Header, len(obj.header)+2)
+	for k, v := range obj.header {
+		header[k] = append([]string(nil), v...)
+	}
+
+	header.Set("Content-Length", strconv.Itoa(len(obj.data)))
+	header.Set("Last-Modified", obj.modTime.UTC().Format(http.TimeFormat))
+
+	return obj, &storageObject{Header: header}, nil
+}
+
+func (s *memoryStorage) Get(name string) (*storageObject, error) {
+	obj, sobj, err := s.lookup("get", name)
+	if err != nil {
+		return nil, err
+	}
+
+	sobj.Body = ioutil.NopCloser(bytes.NewReader(obj.data))
+	return sobj, nil
+}
+
+func (s *memoryStorage) GetRange(name string, start, length int64) (*storageObject, error) {
+	obj, sobj, err := s.lookup("get", name)
+	if err != nil {
+		return nil, err
+	}
+
+	data := obj.data
+
+	if start > int64(len(data)) {
+		start = int64(len(data))
+	}
+
+	if end := start + length; end < int64(len(data)) {
+		data = data[:end]
+	}
+
+	data = data[start:]
+
+	sobj.Header.Set("Content-Length", strconv.Itoa(len(data)))
+	sobj.Body = ioutil.NopCloser(bytes.NewReader(data))
+	return sobj, nil
+}
+
+func (s *memoryStorage) Head(name string) (*storageObject, error) {
+	_, sobj, err := s.lookup("head", name)
+	return sobj, err
+}
+
+func (s *memoryStorage) List(prefix string, max int) ([]string, error) {
+	var names []string
+
+	s.mu.RLock()
+	for name := range s.objects {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(names)
+
+	if max > 0 && len(names) > max {
+		names = names[:max]
+	}
+
+	return names, nil
+}
+
+func (s *memoryStorage) Delete(name string) error {
+	s.mu.Lock()
+	delete(s.objects, name)
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path"
+
+	"github.com/mitchellh/goamz/s3"
+)
+
+// storage is a backend that built sites are uploaded to
+// and served from.
+//
+// Names are slash separated and never begin with a slash.
+// Get and Head return an error that satisfies os.IsNotExist
+// if the named object does not exist.
+type storage interface {
+	Put(name string, r io.Reader, size int64, header http.Header) error
+
+	Get(name string) (*storageObject, error)
+	Head(name string) (*storageObject, error)
+
+	List(prefix string, max int) ([]string, error)
+
+	Delete(name string) error
+}
+
+// rangeStorage is implemented by storage backends that
+// can return part of an object.
+type rangeStorage interface {
+	// GetRange returns length bytes of the named object
+	// starting at start. The Content-Length header is the
+	// length of the range.
+	GetRange(name string, start, length int64) (*storageObject, error)
+}
+
+// storageObject is an object returned from storage.
+//
+// Header contains at least Content-Length and, if known,
+// Last-Modified along with any headers passed to Put.
+// Body is nil for objects returned from Head.
+type storageObject struct {
+	Header http.Header
+	Body   io.ReadCloser
+}
+
+func storageNotExist(op, name string) error {
+	return &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+}
+
+// storageErrorCode returns the HTTP status code that should be
+// sent to the client for an error returned from storage.
+func storageErrorCode(err error) int {
+	if os.IsNotExist(err) {
+		return http.StatusNotFound
+	}
+
+	if _, ok := err.(*s3.Error); ok {
+		return http.StatusBadGateway
+	}
+
+	return http.StatusInternalServerError
+}
+
+func tagPrefix(tag string) string {
+	return path.Join(tag[0:1], tag[1:2], tag[2:])
+}
@@ -0,0 +1,160 @@
+// Copyright 2016 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func testStorage(t *testing.T, store storage) {
+	for name, body := range map[string]string{
+		"a/b/cdef/index.html":    "<!doctype html>",
+		"a/b/cdef/css/style.css": "body{}",
+		"a/b/cdxx/index.html":    "other",
+		"z/z/zzzz/index.html":    "another",
+	} {
+		if err := store.Put(name, bytes.NewReader([]byte(body)), int64(len(body)), http.Header{
+			"Content-Type": {"text/plain"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	obj, err := store.Get("a/b/cdef/index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := ioutil.ReadAll(obj.Body)
+	obj.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(body) != "<!doctype html>" {
+		t.Errorf("Get returned wrong body, expected %q, got %q", "<!doctype html>", body)
+	}
+
+	if ctype := obj.Header.Get("Content-Type"); ctype != "text/plain" {
+		t.Errorf("Get returned wrong Content-Type, expected text/plain, got %s", ctype)
+	}
+
+	if clen := obj.Header.Get("Content-Length"); clen != "15" {
+		t.Errorf("Get returned wrong Content-Length, expected 15, got %s", clen)
+	}
+
+	if rstore, ok := store.(rangeStorage); ok {
+		obj, err := rstore.GetRange("a/b/cdef/index.html", 2, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := ioutil.ReadAll(obj.Body)
+		obj.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(body) != "doctype" || obj.Header.Get("Content-Length") != "7" {
+			t.Errorf("GetRange returned wrong range, expected %q, got %q with length %s", "doctype", body, obj.Header.Get("Content-Length"))
+		}
+	}
+
+	obj, err = store.Head("a/b/cdef/css/style.css")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if obj.Body != nil {
+		t.Error("Head returned body")
+	}
+
+	if clen := obj.Header.Get("Content-Length"); clen != "6" {
+		t.Errorf("Head returned wrong Content-Length, expected 6, got %s", clen)
+	}
+
+	if _, err = store.Get("a/b/cdef/missing.html"); !os.IsNotExist(err) {
+		t.Errorf("Get returned wrong error for missing object, got %v", err)
+	}
+
+	if _, err = store.Head("a/b/cdef/missing.html"); !os.IsNotExist(err) {
+		t.Errorf("Head returned wrong error for missing object, got %v", err)
+	}
+
+	names, err := store.List("a/b/cdef/", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []string{"a/b/cdef/css/style.css", "a/b/cdef/index.html"}; !reflect.DeepEqual(names, expect) {
+		t.Errorf("List returned wrong names, expected %v, got %v", expect, names)
+	}
+
+	names, err = store.List("a/b/cd", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(names) != 3 {
+		t.Errorf("List returned wrong names, expected 3 names, got %v", names)
+	}
+
+	names, err = store.List("a/b/cdef/", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(names) != 1 {
+		t.Errorf("List did not respect max, expected 1 name, got %v", names)
+	}
+
+	if names, err = store.List("b/", 0); err != nil || len(names) != 0 {
+		t.Errorf("List returned (%v, %v) for missing prefix, expected ([], <nil>)", names, err)
+	}
+
+	if err = store.Delete("a/b/cdef/index.html"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = store.Get("a/b/cdef/index.html"); !os.IsNotExist(err) {
+		t.Errorf("Get returned wrong error for deleted object, got %v", err)
+	}
+
+	if err = store.Delete("a/b/cdef/index.html"); err != nil {
+		t.Errorf("Delete returned error for missing object: %v", err)
+	}
+}
+
+func TestMemoryStorage(t *testing.T) {
+	testStorage(t, newMemoryStorage())
+}
+
+func TestFileStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jklhstry-test.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	store, err := getFileStorage(`{"Path":"` + dir + `"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testStorage(t, store)
+
+	for _, name := range [...]string{"", "/a", "../a", "a/../../b", "a//b", "a/"} {
+		if err := store.Put(name, bytes.NewReader(nil), 0, nil); err == nil {
+			t.Errorf("Put did not reject invalid name %q", name)
+		}
+	}
+}
@@ -17,6 +17,7 @@ import (
 
 var (
 	templateFuncs = map[string]interface{}{
+		"ago":        ago,
 		"asset_path": assetPath,
 		"truncate":   truncate,
 	}
@@ -25,7 +26,11 @@ var (
 	indexTemplate  = template.Must(template.New("index.tmpl").Funcs(templateFuncs).Parse(string(MustAsset("views/index.tmpl"))))
 	userTemplate   = template.Must(template.New("user.tmpl").Funcs(templateFuncs).Parse(string(MustAsset("views/user.tmpl"))))
 	repoTemplate   = template.Must(template.New("repo.tmpl").Funcs(templateFuncs).Parse(string(MustAsset("views/repo.tmpl"))))
+	refsTemplate   = template.Must(template.New("refs.tmpl").Funcs(templateFuncs).Parse(string(MustAsset("views/refs.tmpl"))))
+	pullsTemplate  = template.Must(template.New("pulls.tmpl").Funcs(templateFuncs).Parse(string(MustAsset("views/pulls.tmpl"))))
+	pullTemplate   = template.Must(template.New("pull.tmpl").Funcs(templateFuncs).Parse(string(MustAsset("views/pull.tmpl"))))
 	commitTemplate = template.Must(template.New("commit.tmpl").Funcs(templateFuncs).Parse(string(MustAsset("views/commit.tmpl"))))
+	statusTemplate = template.Must(template.New("status.tmpl").Funcs(templateFuncs).Parse(string(MustAsset("views/status.tmpl"))))
 )
 
 func assetPath(name string) (string, error) {
@@ -55,6 +60,31 @@ func truncate(value string, length int) string {
 	return value
 }
 
+// ago returns how long ago t was in the largest whole
+// unit, such as "3 days ago".
+func ago(t time.Time) string {
+	d := time.Since(t)
+
+	for _, unit := range []struct {
+		name string
+		size time.Duration
+	}{
+		{"year", 365 * 24 * time.Hour},
+		{"month", 30 * 24 * time.Hour},
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	} {
+		if n := int(d / unit.size); n == 1 {
+			return "1 " + unit.name + " ago"
+		} else if n > 1 {
+			return strconv.Itoa(n) + " " + unit.name + "s ago"
+		}
+	}
+
+	return "just now"
+}
+
 var indexModTime time.Time
 
 func init() {
@@ -8,6 +8,7 @@ package main
 import (
 	"regexp"
 	"testing"
+	"time"
 )
 
 func TestTemplateFuncs(t *testing.T) {
@@ -18,6 +19,26 @@ func TestTemplateFuncs(t *testing.T) {
 	if _, ok := templateFuncs["truncate"]; !ok {
 		t.Error("templateFuncs does not contain truncate")
 	}
+
+	if _, ok := templateFuncs["ago"]; !ok {
+		t.Error("templateFuncs does not contain ago")
+	}
+}
+
+func TestAgo(t *testing.T) {
+	now := time.Now()
+
+	for d, expect := range map[time.Duration]string{
+		10 * time.Second:     "just now",
+		time.Minute:          "1 minute ago",
+		3 * time.Hour:        "3 hours ago",
+		75 * time.Hour:       "3 days ago",
+		400 * 24 * time.Hour: "1 year ago",
+	} {
+		if s := ago(now.Add(-d)); s != expect {
+			t.Errorf("ago(now - %s) = %q, expected %q", d, s, expect)
+		}
+	}
 }
 
 func TestAssetPath(t *testing.T) {
@@ -12,11 +12,10 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/google/go-github/github"
 	"github.com/julienschmidt/httprouter"
 )
 
-func getUserHandler(githubClient *github.Client) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+func getUserHandler(sources sourceRegistry) func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	var cacheControl = fmt.Sprintf("public, max-age=%d", time.Minute/time.Second)
 
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
@@ -27,6 +26,14 @@ func getUserHandler(githubClient *github.Client) func(w http.ResponseWriter, r *
 			return
 		}
 
+		source, ok := sources.fromParams(ps)
+		if !ok {
+			h.Del("Cache-Control")
+
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
 		page, redirect, err := parsePageString(ps.ByName("page"))
 		if err != nil {
 			h.Del("Cache-Control")
@@ -40,37 +47,30 @@ func getUserHandler(githubClient *github.Client) func(w http.ResponseWriter, r *
 
 		user := ps.ByName("user")
 
-		repos, resp, err := githubClient.Repositories.List(context.Background(), user, &github.RepositoryListOptions{
-			Sort: "updated",
-
-			ListOptions: github.ListOptions{
-				Page: page,
-
-				PerPage: 50,
-			},
-		})
+		repos, resp, err := source.ListRepos(context.Background(), user, page)
 		if err != nil {
 			h.Del("Cache-Control")
 
-			if gerr, ok := err.(*github.ErrorResponse); ok && gerr.Response.StatusCode == http.StatusNotFound {
-				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
-			} else {
+			code := sourceErrorCode(err)
+			if code != http.StatusNotFound {
 				log.Printf("%[1]T %[1]v", err)
-				http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
 			}
 
+			http.Error(w, http.StatusText(code), code)
 			return
 		}
 
-		if verbose {
-			log.Printf("GitHub API Rate Limit is %d remaining of %d, to be reset at %s\n", resp.Remaining, resp.Limit, resp.Reset)
-		}
-
 		if wrote, err := executeTemplate(userTemplate, struct {
+			Source sourceProvider
+			Prefix string
+
 			User  string
-			Repos []*github.Repository
-			Resp  *github.Response
+			Repos []*sourceRepo
+			Resp  *sourcePage
 		}{
+			Source: source,
+			Prefix: sourcePrefix(source.Name()),
+
 			User:  user,
 			Repos: repos,
 			Resp:  resp,